@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The broker filter receives the events Brokers' Channels fan out to their Triggers, and delivers
+// those that pass a Trigger's filter to the Trigger's subscriber.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	"github.com/knative/eventing/pkg/buses"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+var (
+	readTimeout  = 1 * time.Minute
+	writeTimeout = 1 * time.Minute
+
+	port int
+)
+
+func init() {
+	flag.IntVar(&port, "port", 8080, "The port to receive events on.")
+	flag.DurationVar(&buses.DefaultDialTimeout, "dispatchDialTimeout", 0, "The maximum time to establish a connection to a Trigger's subscriber. Zero means no limit.")
+	flag.DurationVar(&buses.DefaultRequestTimeout, "dispatchRequestTimeout", 0, "The maximum time of each request to a Trigger's subscriber. Zero means no limit.")
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+
+	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	if err != nil {
+		logger.Fatal("Error starting up.", zap.Error(err))
+	}
+	eventingv1alpha1.AddToScheme(mgr.GetScheme())
+
+	// The manager's client reads Triggers from its cache, which is only filled once the manager
+	// has started.
+	s := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      filter.NewHandler(logger, mgr.GetClient()),
+		ErrorLog:     zap.NewStdLog(logger),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		// set up signals so we handle the first shutdown signal gracefully
+		stopCh := signals.SetupSignalHandler()
+		// Start blocks forever, so run it in a goroutine.
+		return mgr.Start(stopCh)
+	})
+	logger.Info("Broker filter Listening...", zap.String("Address", s.Addr))
+	g.Go(s.ListenAndServe)
+	err = g.Wait()
+	if err != nil {
+		logger.Error("Either the HTTP server or the manager failed.", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	s.Shutdown(ctx)
+}
@@ -37,6 +37,8 @@ import (
 	"github.com/knative/eventing/pkg/controller/bus"
 	"github.com/knative/eventing/pkg/controller/channel"
 	"github.com/knative/eventing/pkg/controller/clusterbus"
+	"github.com/knative/eventing/pkg/controller/eventing/broker"
+	"github.com/knative/eventing/pkg/controller/eventing/subscription"
 	sharedclientset "github.com/knative/pkg/client/clientset/versioned"
 	sharedinformers "github.com/knative/pkg/client/informers/externalversions"
 	"github.com/knative/pkg/signals"
@@ -169,6 +171,8 @@ func main() {
 func init() {
 	flag.StringVar(&experimentalControllers, "experimentalControllers", "", "List of experimental controllers to include in the Knative Controller.")
 	flag.BoolVar(&hardcodedLoggingConfig, "hardCodedLoggingConfig", false, "If true, use the hard coded logging config. It is intended to be used only when debugging outside a Kubernetes cluster.")
+	flag.IntVar(&subscription.MaxReplyChainDepth, "maxReplyChainDepth", subscription.MaxReplyChainDepth, "The maximum number of reply hops in a chain of Subscriptions.")
+	flag.StringVar(&broker.FilterDomain, "brokerFilterDomain", broker.FilterDomain, "The domain of the broker filter, which delivers the events of each Broker to its Triggers.")
 }
 
 func getLoggingConfigOrDie() map[string]string {
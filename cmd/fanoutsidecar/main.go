@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/knative/eventing/pkg/buses"
 	"github.com/knative/eventing/pkg/sidecar/configmap/filesystem"
 	"github.com/knative/eventing/pkg/sidecar/configmap/watcher"
 	"github.com/knative/eventing/pkg/sidecar/swappable"
@@ -56,6 +57,10 @@ var (
 	configMapNoticer   string
 	configMapNamespace string
 	configMapName      string
+
+	ackPort        int
+	ackCallbackURL string
+	ackTimeout     time.Duration
 )
 
 func init() {
@@ -63,6 +68,11 @@ func init() {
 	flag.StringVar(&configMapNoticer, "config_map_noticer", "", fmt.Sprintf("The system to notice changes to the ConfigMap. Valid values are: %s", configMapNoticerValues()))
 	flag.StringVar(&configMapNamespace, "config_map_namespace", system.Namespace, "The namespace of the ConfigMap that is watched for configuration.")
 	flag.StringVar(&configMapName, "config_map_name", defaultConfigMapName, "The name of the ConfigMap that is watched for configuration.")
+	flag.DurationVar(&buses.DefaultDialTimeout, "dispatchDialTimeout", 0, "The maximum time to establish a connection to a subscriber. Zero means no limit.")
+	flag.DurationVar(&buses.DefaultRequestTimeout, "dispatchRequestTimeout", 0, "The maximum time of each request to a subscriber. Zero means no limit.")
+	flag.IntVar(&ackPort, "ackPort", 8081, "The port to receive asynchronous delivery acknowledgments on, when ackCallbackURL is set.")
+	flag.StringVar(&ackCallbackURL, "ackCallbackURL", "", "The URL at which subscribers reach ackPort of this sidecar to acknowledge deliveries asynchronously, e.g. 'http://$(POD_IP):8081'. Empty disables asynchronous acknowledgment.")
+	flag.DurationVar(&ackTimeout, "ackTimeout", time.Minute, "The maximum time to wait for a subscriber to acknowledge a delivery asynchronously.")
 }
 
 func configMapNoticerValues() string {
@@ -81,6 +91,16 @@ func main() {
 		logger.Fatal("--sidecar_port flag must be set")
 	}
 
+	var ackServer *http.Server
+	if ackCallbackURL != "" {
+		buses.DefaultAckTracker = buses.NewAckTracker(ackCallbackURL, ackTimeout, logger.Sugar())
+		ackServer = &http.Server{
+			Addr:     fmt.Sprintf(":%d", ackPort),
+			Handler:  buses.DefaultAckTracker,
+			ErrorLog: zap.NewStdLog(logger),
+		}
+	}
+
 	sh, err := swappable.NewEmptyHandler(logger)
 	if err != nil {
 		logger.Fatal("Unable to create swappable.Handler", zap.Error(err))
@@ -99,7 +119,7 @@ func main() {
 		WriteTimeout: writeTimeout,
 	}
 
-	// Start both the manager (which notices ConfigMap changes) and the HTTP server.
+	// Start both the manager (which notices ConfigMap changes) and the HTTP servers.
 	var g errgroup.Group
 	g.Go(func() error {
 		// set up signals so we handle the first shutdown signal gracefully
@@ -109,6 +129,10 @@ func main() {
 	})
 	logger.Info("Fanout sidecar Listening...", zap.String("Address", s.Addr))
 	g.Go(s.ListenAndServe)
+	if ackServer != nil {
+		logger.Info("Fanout sidecar receiving acknowledgments...", zap.String("Address", ackServer.Addr))
+		g.Go(ackServer.ListenAndServe)
+	}
 	err = g.Wait()
 	if err != nil {
 		logger.Error("Either the HTTP server or the ConfigMap noticer failed.", zap.Error(err))
@@ -117,6 +141,9 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
 	defer cancel()
 	s.Shutdown(ctx)
+	if ackServer != nil {
+		ackServer.Shutdown(ctx)
+	}
 }
 
 func setupConfigMapNoticer(logger *zap.Logger, configUpdated swappable.UpdateConfig) (manager.Manager, error) {
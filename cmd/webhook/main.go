@@ -18,6 +18,8 @@ package main
 import (
 	"flag"
 	"log"
+	"net/http"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -33,14 +35,34 @@ import (
 	flowsv1alpha1 "github.com/knative/eventing/pkg/apis/flows/v1alpha1"
 	"github.com/knative/eventing/pkg/logconfig"
 	"github.com/knative/eventing/pkg/system"
+	eventingwebhook "github.com/knative/eventing/pkg/webhook"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
+// internalPort is where the admission controller itself listens, behind the handlers that need
+// more of the admission request than it passes on.
+const internalPort = 8443
+
+var (
+	requiredChannelLabels        string
+	allowedProvisionerNamespaces string
+	reservedMetadataWriters      string
+)
+
 func main() {
 	flag.Parse()
+	if requiredChannelLabels != "" {
+		eventingv1alpha1.RequiredLabels = strings.Split(requiredChannelLabels, ",")
+	}
+	if allowedProvisionerNamespaces != "" {
+		eventingv1alpha1.AllowedProvisionerNamespaces = strings.Split(allowedProvisionerNamespaces, ",")
+	}
+	if reservedMetadataWriters != "" {
+		eventingv1alpha1.ReservedMetadataWriters.Insert(strings.Split(reservedMetadataWriters, ",")...)
+	}
 	// Read the logging config and setup a logger.
 	cm, err := configmap.Load("/etc/config-logging")
 	if err != nil {
@@ -90,10 +112,12 @@ func main() {
 		Options: options,
 		Handlers: map[schema.GroupVersionKind]webhook.GenericCRD{
 			// For group eventing.knative.dev,
+			eventingv1alpha1.SchemeGroupVersion.WithKind("Broker"):             &eventingv1alpha1.Broker{},
 			eventingv1alpha1.SchemeGroupVersion.WithKind("Channel"):            &eventingv1alpha1.Channel{},
 			eventingv1alpha1.SchemeGroupVersion.WithKind("ClusterProvisioner"): &eventingv1alpha1.ClusterProvisioner{},
 			eventingv1alpha1.SchemeGroupVersion.WithKind("Source"):             &eventingv1alpha1.Source{},
 			eventingv1alpha1.SchemeGroupVersion.WithKind("Subscription"):       &eventingv1alpha1.Subscription{},
+			eventingv1alpha1.SchemeGroupVersion.WithKind("Trigger"):            &eventingv1alpha1.Trigger{},
 
 			// For group channels.knative.dev,
 			channelsv1alpha1.SchemeGroupVersion.WithKind("Bus"):          &channelsv1alpha1.Bus{},
@@ -116,5 +140,28 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to create the admission controller", zap.Error(err))
 	}
-	controller.Run(stopCh)
+	checks := func(next http.Handler) http.Handler {
+		return &eventingwebhook.Handler{
+			Next:   next,
+			Logger: logger,
+			Checks: []eventingwebhook.Check{
+				eventingwebhook.CheckReservedMetadata,
+				eventingwebhook.CheckChannelCreate,
+			},
+		}
+	}
+	if err := eventingwebhook.Run(&controller, checks, internalPort, stopCh); err != nil {
+		logger.Fatal("Failed to run the admission controller", zap.Error(err))
+	}
+}
+
+func init() {
+	flag.StringVar(&requiredChannelLabels, "requiredChannelLabels", "", "Comma-separated list of labels every Channel must have when it is created, unless it is owned by another resource.")
+	flag.BoolVar(&eventingv1alpha1.RestrictProvisionerNamespaces, "restrictProvisionerNamespaces", false, "If true, Channels may only reference Provisioners in their own namespace or in one of allowedProvisionerNamespaces.")
+	flag.IntVar(&eventingv1alpha1.MaxArgumentsSize, "maxChannelArgumentsSize", eventingv1alpha1.MaxArgumentsSize, "Maximum size in bytes of a Channel's serialized spec.arguments.")
+	flag.StringVar(&allowedProvisionerNamespaces, "allowedProvisionerNamespaces", "", "Comma-separated list of namespaces whose Provisioners Channels in any namespace may reference.")
+	flag.StringVar(&reservedMetadataWriters, "reservedMetadataWriters", "", "Comma-separated list of users, besides the controllers' service accounts, allowed to change reserved Channel labels and annotations.")
+	flag.DurationVar(&eventingv1alpha1.MaxClockSkew, "maxConditionClockSkew", eventingv1alpha1.MaxClockSkew, "How far in the future a Channel condition's lastTransitionTime may be.")
+	flag.BoolVar(&eventingv1alpha1.AllowCrossNamespaceTriggerSubscribers, "allowCrossNamespaceTriggerSubscribers", false, "If true, a Trigger's subscriber may be in another namespace than the Trigger.")
+	flag.StringVar(&eventingv1alpha1.DefaultBrokerProvisioner, "defaultBrokerProvisioner", eventingv1alpha1.DefaultBrokerProvisioner, "The ClusterProvisioner of the Channel of a Broker that doesn't specify a channelTemplate.")
 }
@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultBrokerProvisioner is the name of the ClusterProvisioner of the Channels of Brokers that
+// don't specify a ChannelTemplate.
+var DefaultBrokerProvisioner = "in-memory-channel"
+
+func (b *Broker) SetDefaults() {
+	b.Spec.SetDefaults()
+}
+
+func (bs *BrokerSpec) SetDefaults() {
+	if bs.ChannelTemplate == nil {
+		bs.ChannelTemplate = &ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					APIVersion: SchemeGroupVersion.String(),
+					Kind:       "ClusterProvisioner",
+					Name:       DefaultBrokerProvisioner,
+				},
+			},
+		}
+	}
+	bs.ChannelTemplate.SetDefaults()
+}
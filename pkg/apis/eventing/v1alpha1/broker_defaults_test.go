@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBrokerDefaults(t *testing.T) {
+	testCases := map[string]struct {
+		initial  *ChannelSpec
+		expected *ChannelSpec
+	}{
+		"unset": {
+			expected: &ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "eventing.knative.dev/v1alpha1",
+						Kind:       "ClusterProvisioner",
+						Name:       "in-memory-channel",
+					},
+				},
+			},
+		},
+		"set": {
+			initial: &ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "v1alpha1",
+						Kind:       "clusterprovisioner",
+						Name:       " kafka ",
+					},
+				},
+			},
+			expected: &ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "eventing.knative.dev/v1alpha1",
+						Kind:       "ClusterProvisioner",
+						Name:       "kafka",
+					},
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			b := Broker{
+				Spec: BrokerSpec{
+					ChannelTemplate: tc.initial,
+				},
+			}
+			b.SetDefaults()
+			if diff := cmp.Diff(tc.expected, b.Spec.ChannelTemplate); diff != "" {
+				t.Errorf("unexpected ChannelTemplate (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
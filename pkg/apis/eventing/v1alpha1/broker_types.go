@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:defaulter-gen=true
+
+// Broker collects the events sent to it in a Channel and delivers each of them to the Triggers in
+// its namespace whose filter it passes. It corresponds to the brokers.eventing.knative.dev CRD.
+type Broker struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              BrokerSpec   `json:"spec"`
+	Status            BrokerStatus `json:"status,omitempty"`
+}
+
+// Check that Broker can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*Broker)(nil)
+var _ apis.Defaultable = (*Broker)(nil)
+var _ apis.Immutable = (*Broker)(nil)
+var _ runtime.Object = (*Broker)(nil)
+var _ webhook.GenericCRD = (*Broker)(nil)
+
+// BrokerLabelKey is the label the Broker controller puts on the Channel it creates for a Broker,
+// whose value is the Broker's name.
+const BrokerLabelKey = "eventing.knative.dev/broker"
+
+// BrokerSpec specifies the Channel that holds a Broker's events.
+type BrokerSpec struct {
+	// ChannelTemplate is the spec of the Channel the Broker's events are held in. It defaults to a
+	// Channel of DefaultBrokerProvisioner.
+	//
+	// This field is immutable, as the events in the Channel would be lost when it is replaced.
+	// +optional
+	ChannelTemplate *ChannelSpec `json:"channelTemplate,omitempty"`
+}
+
+// brokerCondSet is a condition set with Ready as the happy condition and ChannelReady and Sinkable
+// as the dependent conditions.
+var brokerCondSet = duckv1alpha1.NewLivingConditionSet(BrokerConditionChannel, BrokerConditionSinkable)
+
+// BrokerStatus represents the current state of a Broker.
+type BrokerStatus struct {
+	// Represents the latest available observations of a Broker's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Broker is Sinkable. Events sent to its domain are delivered to its Triggers.
+	Sinkable duckv1alpha1.Sinkable `json:"sinkable,omitempty"`
+
+	// Channel is the Channel the Broker's events are held in.
+	// +optional
+	Channel *corev1.ObjectReference `json:"channel,omitempty"`
+}
+
+const (
+	// BrokerConditionReady has status True when all subconditions below have been set to True.
+	BrokerConditionReady = duckv1alpha1.ConditionReady
+
+	// BrokerConditionChannel has status True when the Broker's Channel is Ready.
+	BrokerConditionChannel duckv1alpha1.ConditionType = "ChannelReady"
+
+	// BrokerConditionSinkable has status True when the Broker has a domain events can be sent to.
+	BrokerConditionSinkable duckv1alpha1.ConditionType = "Sinkable"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (bs *BrokerStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return brokerCondSet.Manage(bs).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (bs *BrokerStatus) IsReady() bool {
+	return brokerCondSet.Manage(bs).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (bs *BrokerStatus) InitializeConditions() {
+	brokerCondSet.Manage(bs).InitializeConditions()
+}
+
+// PropagateChannelStatus records ch as the Broker's Channel, and sets the ChannelReady and Sinkable
+// conditions from its readiness and domain. The Broker's domain is the Channel's domain.
+func (bs *BrokerStatus) PropagateChannelStatus(ch *Channel) {
+	bs.Channel = &corev1.ObjectReference{
+		APIVersion: SchemeGroupVersion.String(),
+		Kind:       "Channel",
+		Namespace:  ch.Namespace,
+		Name:       ch.Name,
+	}
+	if ch.Status.IsReady() {
+		brokerCondSet.Manage(bs).MarkTrue(BrokerConditionChannel)
+	} else {
+		brokerCondSet.Manage(bs).MarkFalse(BrokerConditionChannel, "ChannelNotReady", "the Channel %s is not ready", ch.Name)
+	}
+	bs.Sinkable.DomainInternal = ch.Status.Sinkable.DomainInternal
+	if bs.Sinkable.DomainInternal != "" {
+		brokerCondSet.Manage(bs).MarkTrue(BrokerConditionSinkable)
+	} else {
+		brokerCondSet.Manage(bs).MarkFalse(BrokerConditionSinkable, "emptyDomainInternal", "the Channel %s has no domain", ch.Name)
+	}
+}
+
+// MarkChannelFailed sets the ChannelReady and Sinkable conditions to False, and clears the Broker's
+// domain, for when the Broker's Channel can't be created or used.
+func (bs *BrokerStatus) MarkChannelFailed(reason, messageFormat string, messageA ...interface{}) {
+	bs.Sinkable.DomainInternal = ""
+	brokerCondSet.Manage(bs).MarkFalse(BrokerConditionChannel, reason, messageFormat, messageA...)
+	brokerCondSet.Manage(bs).MarkFalse(BrokerConditionSinkable, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BrokerList is a collection of Brokers.
+type BrokerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Broker `json:"items"`
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBrokerStatus_PropagateChannelStatus(t *testing.T) {
+	readyChannel := func(domain string) *Channel {
+		ch := &Channel{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "default-broker",
+			},
+		}
+		ch.Status.InitializeConditions()
+		ch.Status.MarkCompatible()
+		ch.Status.MarkProvisioned()
+		ch.Status.SetSinkable(domain)
+		ch.Status.SetSubscribable(ch.Namespace, ch.Name)
+		return ch
+	}
+	testCases := map[string]struct {
+		ch            *Channel
+		wantChannel   corev1.ConditionStatus
+		wantSinkable  corev1.ConditionStatus
+		wantReady     bool
+		wantDomain    string
+		wantReference *corev1.ObjectReference
+	}{
+		"channel ready": {
+			ch:           readyChannel("default-broker.test-namespace.svc.cluster.local"),
+			wantChannel:  corev1.ConditionTrue,
+			wantSinkable: corev1.ConditionTrue,
+			wantReady:    true,
+			wantDomain:   "default-broker.test-namespace.svc.cluster.local",
+		},
+		"channel not ready": {
+			ch: &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-namespace",
+					Name:      "default-broker",
+				},
+			},
+			wantChannel:  corev1.ConditionFalse,
+			wantSinkable: corev1.ConditionFalse,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			bs := &BrokerStatus{}
+			bs.InitializeConditions()
+			bs.PropagateChannelStatus(tc.ch)
+			if got := bs.GetCondition(BrokerConditionChannel).Status; got != tc.wantChannel {
+				t.Errorf("unexpected ChannelReady status: want %v, got %v", tc.wantChannel, got)
+			}
+			if got := bs.GetCondition(BrokerConditionSinkable).Status; got != tc.wantSinkable {
+				t.Errorf("unexpected Sinkable status: want %v, got %v", tc.wantSinkable, got)
+			}
+			if got := bs.IsReady(); got != tc.wantReady {
+				t.Errorf("unexpected readiness: want %v, got %v", tc.wantReady, got)
+			}
+			if bs.Sinkable.DomainInternal != tc.wantDomain {
+				t.Errorf("unexpected domain: want %q, got %q", tc.wantDomain, bs.Sinkable.DomainInternal)
+			}
+			want := &corev1.ObjectReference{
+				APIVersion: SchemeGroupVersion.String(),
+				Kind:       "Channel",
+				Namespace:  "test-namespace",
+				Name:       "default-broker",
+			}
+			if diff := cmp.Diff(want, bs.Channel); diff != "" {
+				t.Errorf("unexpected channel reference (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestBrokerStatus_MarkChannelFailed(t *testing.T) {
+	bs := &BrokerStatus{
+		Sinkable: duckv1alpha1.Sinkable{
+			DomainInternal: "default-broker.test-namespace.svc.cluster.local",
+		},
+	}
+	bs.InitializeConditions()
+	bs.MarkChannelFailed("ChannelNotOwned", "the Channel %s is not owned by the Broker", "default-broker")
+	if bs.IsReady() {
+		t.Errorf("expected the Broker not to be ready")
+	}
+	for _, ct := range []duckv1alpha1.ConditionType{BrokerConditionChannel, BrokerConditionSinkable} {
+		c := bs.GetCondition(ct)
+		if c.Status != corev1.ConditionFalse || c.Reason != "ChannelNotOwned" || c.Message != "the Channel default-broker is not owned by the Broker" {
+			t.Errorf("unexpected %s condition: %+v", ct, c)
+		}
+	}
+	if bs.Sinkable.DomainInternal != "" {
+		t.Errorf("expected the domain to be cleared, got %q", bs.Sinkable.DomainInternal)
+	}
+}
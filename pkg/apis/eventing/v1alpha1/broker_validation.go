@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+)
+
+func (b *Broker) Validate() *apis.FieldError {
+	return b.Spec.Validate().ViaField("spec")
+}
+
+func (bs *BrokerSpec) Validate() *apis.FieldError {
+	if bs.ChannelTemplate == nil {
+		return apis.ErrMissingField("channelTemplate")
+	}
+	var errs *apis.FieldError
+	if bs.ChannelTemplate.Channelable != nil && len(bs.ChannelTemplate.Channelable.Subscribers) > 0 {
+		fe := apis.ErrDisallowedFields("channelTemplate.channelable")
+		fe.Details = "the subscribers of a Broker's Channel are its Triggers"
+		errs = errs.Also(fe)
+	}
+	return errs.Also(bs.ChannelTemplate.Validate().ViaField("channelTemplate"))
+}
+
+func (current *Broker) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	original, ok := og.(*Broker)
+	if !ok {
+		return &apis.FieldError{Message: "The provided original was not a Broker"}
+	}
+	if original == nil {
+		return nil
+	}
+
+	if diff := cmp.Diff(original.Spec, current.Spec); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec"},
+			Details: diff,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func getValidChannelTemplate() *ChannelSpec {
+	return &ChannelSpec{
+		Provisioner: &ProvisionerReference{
+			Ref: &corev1.ObjectReference{
+				Name: "in-memory-channel",
+			},
+		},
+	}
+}
+
+func TestBrokerValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Broker
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		b: &Broker{
+			Spec: BrokerSpec{
+				ChannelTemplate: getValidChannelTemplate(),
+			},
+		},
+		want: nil,
+	}, {
+		name: "missing channel template",
+		b:    &Broker{},
+		want: apis.ErrMissingField("spec.channelTemplate"),
+	}, {
+		name: "missing provisioner",
+		b: &Broker{
+			Spec: BrokerSpec{
+				ChannelTemplate: &ChannelSpec{},
+			},
+		},
+		want: apis.ErrMissingField("spec.channelTemplate.provisioner"),
+	}, {
+		name: "subscribers",
+		b: &Broker{
+			Spec: BrokerSpec{
+				ChannelTemplate: &ChannelSpec{
+					Provisioner: getValidChannelTemplate().Provisioner,
+					Channelable: &Channelable{
+						Subscribers: []ChannelSubscriberSpec{{
+							CallableDomain: "foo",
+						}},
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("spec.channelTemplate.channelable")
+			fe.Details = "the subscribers of a Broker's Channel are its Triggers"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.b.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestBrokerImmutable(t *testing.T) {
+	withArguments := getValidChannelTemplate()
+	withArguments.Arguments = &runtime.RawExtension{Raw: []byte(`{"topic":"foo"}`)}
+
+	tests := []struct {
+		name string
+		c    *Broker
+		og   *Broker
+		want bool
+	}{{
+		name: "unchanged",
+		c:    &Broker{Spec: BrokerSpec{ChannelTemplate: getValidChannelTemplate()}},
+		og:   &Broker{Spec: BrokerSpec{ChannelTemplate: getValidChannelTemplate()}},
+	}, {
+		name: "new nil is ok",
+		c:    &Broker{Spec: BrokerSpec{ChannelTemplate: getValidChannelTemplate()}},
+		og:   nil,
+	}, {
+		name: "channel template changed",
+		c:    &Broker{Spec: BrokerSpec{ChannelTemplate: withArguments}},
+		og:   &Broker{Spec: BrokerSpec{ChannelTemplate: getValidChannelTemplate()}},
+		want: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.c.CheckImmutableFields(test.og)
+			if (got != nil) != test.want {
+				t.Errorf("%s: CheckImmutableFields want error %v, got %v", test.name, test.want, got)
+			}
+			if got != nil && got.Paths[0] != "spec" {
+				t.Errorf("%s: CheckImmutableFields want path spec, got %v", test.name, got.Paths)
+			}
+		})
+	}
+
+	if got := (&Broker{}).CheckImmutableFields(&DummyImmutableType{}); got == nil {
+		t.Errorf("expected an error checking a Broker against another type")
+	}
+}
@@ -0,0 +1,543 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ProvisionerArgumentKeys are, by Provisioner name, the only keys of spec.arguments that the
+// Channels of Provisioners declaring them may set. Channels of other Provisioners may set any keys.
+var ProvisionerArgumentKeys = map[string]sets.String{}
+
+// ProvisionerArgumentGroups are, by Provisioner name, groups of spec.arguments keys that the
+// Channels of the Provisioner must set together, e.g. a topic and its partition count: if any key of
+// a group is set, all of them must be.
+var ProvisionerArgumentGroups = map[string][][]string{}
+
+// ProvisionerMutableArguments are, by Provisioner name, the paths of spec.arguments, such as
+// "retention" or "retention.hours", that the Provisioner can reconfigure after the Channel is
+// created. All other arguments are immutable, as are all the arguments of other Provisioners.
+var ProvisionerMutableArguments = map[string][]string{}
+
+// ChannelArguments holds the well-known keys of a Channel's spec.arguments, which are validated
+// on admission. Provisioners may accept any other keys, which are left to them to validate.
+type ChannelArguments struct {
+	// ConsumerGroupPrefix is prepended to the names of the consumer groups (or subscriptions) the
+	// Provisioner creates for the Channel, letting them be namespaced per environment. It must be
+	// a DNS-1123 label.
+	// +optional
+	ConsumerGroupPrefix *string `json:"consumerGroupPrefix,omitempty"`
+
+	// OrderedDelivery requests that events are delivered to each subscriber in the order they
+	// were received. PartitionKey must also be set, as events are ordered per partition.
+	// +optional
+	OrderedDelivery *bool `json:"orderedDelivery,omitempty"`
+
+	// PartitionKey is the event attribute whose value partitions events across subscribers, with
+	// ordering guaranteed only within a partition.
+	// +optional
+	PartitionKey *string `json:"partitionKey,omitempty"`
+
+	// DeadLetterSink is where the Provisioner sends events that could not be delivered. If its
+	// namespace is omitted, it is in the Channel's namespace.
+	// +optional
+	DeadLetterSink *corev1.ObjectReference `json:"deadLetterSink,omitempty"`
+
+	// DefaultConcurrency is the maximum number of events delivered to each subscriber at the same
+	// time, for Subscriptions that don't set their own concurrency.
+	// +optional
+	DefaultConcurrency *int32 `json:"defaultConcurrency,omitempty"`
+
+	// Placement constrains where stateful Provisioners schedule the pods backing the Channel.
+	// +optional
+	Placement *ChannelPlacement `json:"placement,omitempty"`
+
+	// EventSchema is a JSON schema that schema-aware Provisioners validate incoming events against,
+	// rejecting those that don't conform.
+	// +optional
+	EventSchema *runtime.RawExtension `json:"eventSchema,omitempty"`
+}
+
+// ChannelPlacement constrains the nodes the pods backing a Channel may run on.
+type ChannelPlacement struct {
+	// NodeSelector must match a node's labels for the pods to be scheduled onto it.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Zones are the topology zones the pods may be spread across.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+}
+
+// Validate checks that the node selector and zones are well-formed label keys and values.
+func (p *ChannelPlacement) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	keys := make([]string, 0, len(p.NodeSelector))
+	for k := range p.NodeSelector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if msgs := validation.IsQualifiedName(k); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidKeyName(k, "nodeSelector", msgs...))
+		}
+		if v := p.NodeSelector[k]; len(validation.IsValidLabelValue(v)) > 0 {
+			fe := apis.ErrInvalidValue(v, apis.CurrentField)
+			fe.Details = strings.Join(validation.IsValidLabelValue(v), ", ")
+			errs = errs.Also(fe.ViaFieldKey("nodeSelector", k))
+		}
+	}
+	for i, z := range p.Zones {
+		if msgs := validation.IsValidLabelValue(z); z == "" || len(msgs) > 0 {
+			fe := apis.ErrInvalidValue(z, apis.CurrentField)
+			fe.Details = strings.Join(msgs, ", ")
+			errs = errs.Also(fe.ViaFieldIndex("zones", i))
+		}
+	}
+	return errs
+}
+
+// Validate validates the well-known arguments.
+func (a *ChannelArguments) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if a.ConsumerGroupPrefix != nil {
+		if msgs := validation.IsDNS1123Label(*a.ConsumerGroupPrefix); len(msgs) > 0 {
+			fe := apis.ErrInvalidValue(*a.ConsumerGroupPrefix, "consumerGroupPrefix")
+			fe.Details = strings.Join(msgs, ", ")
+			errs = errs.Also(fe)
+		}
+	}
+	if a.PartitionKey != nil && *a.PartitionKey == "" {
+		errs = errs.Also(apis.ErrInvalidValue("", "partitionKey"))
+	}
+	errs = errs.Also(a.validateOrdering())
+	if a.DefaultConcurrency != nil && *a.DefaultConcurrency < 1 {
+		fe := apis.ErrInvalidValue(strconv.Itoa(int(*a.DefaultConcurrency)), "defaultConcurrency")
+		fe.Details = "must be at least 1"
+		errs = errs.Also(fe)
+	}
+	if a.Placement != nil {
+		errs = errs.Also(a.Placement.Validate().ViaField("placement"))
+	}
+	if a.EventSchema != nil {
+		errs = errs.Also(validateEventSchema(a.EventSchema).ViaField("eventSchema"))
+	}
+	return errs
+}
+
+// validateEventSchema checks that the schema is a JSON schema document, i.e. a JSON object or a
+// boolean.
+func validateEventSchema(schema *runtime.RawExtension) *apis.FieldError {
+	var v interface{}
+	if err := json.Unmarshal(schema.Raw, &v); err != nil {
+		fe := apis.ErrInvalidValue(string(schema.Raw), apis.CurrentField)
+		fe.Details = err.Error()
+		return fe
+	}
+	switch v.(type) {
+	case map[string]interface{}, bool:
+		return nil
+	}
+	fe := apis.ErrInvalidValue(string(schema.Raw), apis.CurrentField)
+	fe.Details = "a JSON schema must be an object or a boolean"
+	return fe
+}
+
+// EffectiveArguments returns the well-known arguments Dispatchers should deliver the Channel's
+// events with: every argument the Channel sets, and policy's value for every argument it doesn't.
+// policy may be nil. Arguments that can't be decoded are ignored, as validation rejects them.
+func (c *Channel) EffectiveArguments(policy *ChannelArguments) ChannelArguments {
+	effective := ChannelArguments{}
+	if policy != nil {
+		policy.DeepCopyInto(&effective)
+	}
+	own, _ := decodeChannelArguments(c.Spec.Arguments)
+	if own == nil {
+		return effective
+	}
+	if own.ConsumerGroupPrefix != nil {
+		effective.ConsumerGroupPrefix = own.ConsumerGroupPrefix
+	}
+	if own.OrderedDelivery != nil {
+		effective.OrderedDelivery = own.OrderedDelivery
+	}
+	if own.PartitionKey != nil {
+		effective.PartitionKey = own.PartitionKey
+	}
+	if own.DeadLetterSink != nil {
+		effective.DeadLetterSink = own.DeadLetterSink
+	}
+	if own.DefaultConcurrency != nil {
+		effective.DefaultConcurrency = own.DefaultConcurrency
+	}
+	if own.Placement != nil {
+		effective.Placement = own.Placement
+	}
+	if own.EventSchema != nil {
+		effective.EventSchema = own.EventSchema
+	}
+	return effective
+}
+
+// validateOrdering checks that ordered delivery names the attribute events are ordered by.
+func (a *ChannelArguments) validateOrdering() *apis.FieldError {
+	if a.OrderedDelivery == nil || !*a.OrderedDelivery || a.PartitionKey != nil {
+		return nil
+	}
+	fe := apis.ErrMissingField("partitionKey")
+	fe.Details = "ordered delivery requires a partition key to order events by"
+	return fe
+}
+
+// ValidateDeadLetterSinkNamespace returns an error if the Channel's dead-letter sink is in a
+// namespace not in allowed, for clusters whose policy restricts where dead letters may go.
+func (c *Channel) ValidateDeadLetterSinkNamespace(allowed []string) *apis.FieldError {
+	ca, fe := decodeChannelArguments(c.Spec.Arguments)
+	if fe != nil {
+		return fe.ViaField("spec")
+	}
+	if ca == nil || ca.DeadLetterSink == nil {
+		return nil
+	}
+	namespace := ca.DeadLetterSink.Namespace
+	if namespace == "" {
+		namespace = c.Namespace
+	}
+	for _, a := range allowed {
+		if namespace == a {
+			return nil
+		}
+	}
+	fe = apis.ErrInvalidValue(namespace, "spec.arguments.deadLetterSink.namespace")
+	fe.Details = fmt.Sprintf("dead-letter sinks must be in one of the namespaces %v", allowed)
+	return fe
+}
+
+// DecodeArguments unmarshals the spec's arguments into the struct pointed to by into. If there
+// are no arguments, it leaves into untouched and returns nil.
+func (cs *ChannelSpec) DecodeArguments(into interface{}) error {
+	if cs.Arguments == nil || len(cs.Arguments.Raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(cs.Arguments.Raw, into)
+}
+
+// validateArgumentKeys returns an error naming every top-level key of the arguments that is not in
+// allowed. Arguments that are not a JSON object are left for decodeChannelArguments to reject.
+func validateArgumentKeys(args *runtime.RawExtension, allowed sets.String) *apis.FieldError {
+	var disallowed []string
+	for k := range topLevelArguments(args) {
+		if !allowed.Has(k) {
+			disallowed = append(disallowed, "arguments."+k)
+		}
+	}
+	if len(disallowed) == 0 {
+		return nil
+	}
+	sort.Strings(disallowed)
+	fe := apis.ErrDisallowedFields(disallowed...)
+	fe.Details = fmt.Sprintf("the provisioner only accepts the arguments %v", allowed.List())
+	return fe
+}
+
+// validateArgumentGroups returns an error naming every key missing from the groups of which the
+// arguments set some, but not all, keys.
+func validateArgumentGroups(args *runtime.RawExtension, groups [][]string) *apis.FieldError {
+	set := topLevelArguments(args)
+	var errs *apis.FieldError
+	for _, group := range groups {
+		var present, missing []string
+		for _, k := range group {
+			if _, ok := set[k]; ok {
+				present = append(present, k)
+			} else {
+				missing = append(missing, "arguments."+k)
+			}
+		}
+		if len(present) == 0 || len(missing) == 0 {
+			continue
+		}
+		fe := apis.ErrMissingField(missing...)
+		fe.Details = fmt.Sprintf("the arguments %v must be set together", group)
+		errs = errs.Also(fe)
+	}
+	return errs
+}
+
+// topLevelArguments returns the top-level keys of the arguments and their values. It returns nil
+// if there are no arguments or they are not a JSON object.
+func topLevelArguments(args *runtime.RawExtension) map[string]json.RawMessage {
+	if args == nil || len(args.Raw) == 0 {
+		return nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(args.Raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// checkImmutableArguments returns an error naming every path of the arguments that changed between
+// original and current and is not one of, or beneath one of, the mutable paths.
+func checkImmutableArguments(original, current *runtime.RawExtension, mutable []string) *apis.FieldError {
+	var ov, cv interface{}
+	if original != nil && len(original.Raw) > 0 {
+		if err := json.Unmarshal(original.Raw, &ov); err != nil {
+			ov = string(original.Raw)
+		}
+	}
+	if current != nil && len(current.Raw) > 0 {
+		if err := json.Unmarshal(current.Raw, &cv); err != nil {
+			cv = string(current.Raw)
+		}
+	}
+	var changed []string
+	for _, p := range changedArgumentPaths(ov, cv, "") {
+		if !isMutableArgumentPath(p, mutable) {
+			if p == "" {
+				changed = append(changed, "arguments")
+			} else {
+				changed = append(changed, "arguments."+p)
+			}
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return &apis.FieldError{
+		Message: "Immutable fields changed",
+		Paths:   changed,
+	}
+}
+
+// changedArgumentPaths returns the sorted dotted paths, under prefix, at which the decoded JSON
+// values original and current differ. Objects are compared key by key, any other values whole.
+func changedArgumentPaths(original, current interface{}, prefix string) []string {
+	om, ook := original.(map[string]interface{})
+	cm, cok := current.(map[string]interface{})
+	if !ook || !cok {
+		if reflect.DeepEqual(original, current) {
+			return nil
+		}
+		return []string{prefix}
+	}
+	keys := make(map[string]bool, len(om)+len(cm))
+	for k := range om {
+		keys[k] = true
+	}
+	for k := range cm {
+		keys[k] = true
+	}
+	var changed []string
+	for k := range keys {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		changed = append(changed, changedArgumentPaths(om[k], cm[k], p)...)
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// isMutableArgumentPath returns true if path is one of, or beneath one of, the mutable paths.
+func isMutableArgumentPath(path string, mutable []string) bool {
+	for _, m := range mutable {
+		if path == m || strings.HasPrefix(path, m+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeChannelArguments decodes the well-known keys of the arguments. It returns nil if there are
+// no arguments.
+func decodeChannelArguments(args *runtime.RawExtension) (*ChannelArguments, *apis.FieldError) {
+	if args == nil || len(args.Raw) == 0 {
+		return nil, nil
+	}
+	ca := &ChannelArguments{}
+	if err := json.Unmarshal(args.Raw, ca); err != nil {
+		fe := apis.ErrInvalidValue(string(args.Raw), "arguments")
+		fe.Details = err.Error()
+		return nil, fe
+	}
+	return ca, nil
+}
+
+// argumentsTemplateKeys are the metadata fields templates in a Channel's arguments may reference,
+// e.g. '{{.Name}}'.
+var argumentsTemplateKeys = []string{"Name", "Namespace", "UID"}
+
+// argumentsTemplateData returns the data templates in the Channel's arguments are rendered against.
+// Metadata that isn't set yet, such as the uid of a Channel that is being created or a name still to
+// be generated, is left out, so that templates referencing it fail to render rather than render
+// empty.
+func (c *Channel) argumentsTemplateData() map[string]string {
+	data := map[string]string{}
+	for k, v := range map[string]string{
+		"Name":      c.Name,
+		"Namespace": c.Namespace,
+		"UID":       string(c.UID),
+	} {
+		if v != "" {
+			data[k] = v
+		}
+	}
+	return data
+}
+
+// renderArgumentsTemplates renders every templated string value in the Channel's arguments
+// against the Channel's metadata. Values that fail to render, because they reference an unknown key
+// or metadata that isn't set yet, are left untouched, so that validation can report them.
+func (c *Channel) renderArgumentsTemplates() {
+	args := c.Spec.Arguments
+	if args == nil || len(args.Raw) == 0 || !bytes.Contains(args.Raw, []byte("{{")) {
+		return
+	}
+	var v interface{}
+	if err := json.Unmarshal(args.Raw, &v); err != nil {
+		// Not our problem, the provisioner will reject the arguments.
+		return
+	}
+	data := c.argumentsTemplateData()
+	rendered, changed := walkArgumentStrings(v, func(s string) string {
+		if r, err := renderArgumentTemplate(s, data); err == nil {
+			return r
+		}
+		return s
+	})
+	if !changed {
+		return
+	}
+	if raw, err := json.Marshal(rendered); err == nil {
+		c.Spec.Arguments = &runtime.RawExtension{Raw: raw}
+	}
+}
+
+// validateArgumentsTemplates returns an error for every string value in the arguments that
+// contains a template that can't be rendered, such as one referencing an unknown key.
+func validateArgumentsTemplates(args *runtime.RawExtension) *apis.FieldError {
+	all := map[string]string{}
+	for _, k := range argumentsTemplateKeys {
+		all[k] = k
+	}
+	return forEachArgumentsTemplate(args, func(s string) *apis.FieldError {
+		if _, err := renderArgumentTemplate(s, all); err != nil {
+			fe := apis.ErrInvalidValue(s, "arguments")
+			fe.Details = "only {{.Name}}, {{.Namespace}} and {{.UID}} may be referenced"
+			return fe
+		}
+		return nil
+	})
+}
+
+// validateArgumentsTemplatesRendered returns an error for every template SetDefaults couldn't render
+// because it references metadata the Channel doesn't have yet: the uid, which is only assigned once
+// the Channel is created, or the name of a Channel using generateName. Templates referencing unknown
+// keys are reported by validateArgumentsTemplates instead.
+func (c *Channel) validateArgumentsTemplatesRendered() *apis.FieldError {
+	if validateArgumentsTemplates(c.Spec.Arguments) != nil {
+		return nil
+	}
+	data := c.argumentsTemplateData()
+	return forEachArgumentsTemplate(c.Spec.Arguments, func(s string) *apis.FieldError {
+		if _, err := renderArgumentTemplate(s, data); err != nil {
+			fe := apis.ErrInvalidValue(s, "arguments")
+			fe.Details = "references metadata that isn't set when the Channel is created, such as {{.UID}}, or {{.Name}} with generateName"
+			return fe
+		}
+		return nil
+	})
+}
+
+// forEachArgumentsTemplate calls f on every string value in the arguments that contains a template,
+// and returns the errors it returned.
+func forEachArgumentsTemplate(args *runtime.RawExtension, f func(string) *apis.FieldError) *apis.FieldError {
+	if args == nil || len(args.Raw) == 0 || !bytes.Contains(args.Raw, []byte("{{")) {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(args.Raw, &v); err != nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	walkArgumentStrings(v, func(s string) string {
+		if strings.Contains(s, "{{") {
+			errs = errs.Also(f(s))
+		}
+		return s
+	})
+	return errs
+}
+
+func renderArgumentTemplate(s string, data map[string]string) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	t, err := template.New("argument").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// walkArgumentStrings calls f on every string value in the decoded JSON value v, replacing it with
+// the result. It returns the resulting value and whether any string was changed.
+func walkArgumentStrings(v interface{}, f func(string) string) (interface{}, bool) {
+	switch t := v.(type) {
+	case string:
+		r := f(t)
+		return r, r != t
+	case []interface{}:
+		changed := false
+		for i := range t {
+			var c bool
+			t[i], c = walkArgumentStrings(t[i], f)
+			changed = changed || c
+		}
+		return t, changed
+	case map[string]interface{}:
+		changed := false
+		for k := range t {
+			var c bool
+			t[k], c = walkArgumentStrings(t[k], f)
+			changed = changed || c
+		}
+		return t, changed
+	default:
+		return v, false
+	}
+}
@@ -0,0 +1,356 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestChannelArgumentsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args *runtime.RawExtension
+		want *apis.FieldError
+	}{{
+		name: "omitted",
+		args: &runtime.RawExtension{Raw: []byte(`{"topic":"foo"}`)},
+		want: nil,
+	}, {
+		name: "valid consumer group prefix",
+		args: &runtime.RawExtension{Raw: []byte(`{"consumerGroupPrefix":"staging"}`)},
+		want: nil,
+	}, {
+		name: "invalid consumer group prefix",
+		args: &runtime.RawExtension{Raw: []byte(`{"consumerGroupPrefix":"Staging_Env"}`)},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("Staging_Env", "spec.arguments.consumerGroupPrefix")
+			fe.Details = "a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"
+			return fe
+		}(),
+	}, {
+		name: "valid default concurrency",
+		args: &runtime.RawExtension{Raw: []byte(`{"defaultConcurrency":4}`)},
+	}, {
+		name: "invalid default concurrency",
+		args: &runtime.RawExtension{Raw: []byte(`{"defaultConcurrency":0}`)},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("0", "spec.arguments.defaultConcurrency")
+			fe.Details = "must be at least 1"
+			return fe
+		}(),
+	}, {
+		name: "valid placement",
+		args: &runtime.RawExtension{Raw: []byte(`{"placement":{"nodeSelector":{"kubernetes.io/arch":"amd64"},"zones":["us-east1-b","us-east1-c"]}}`)},
+	}, {
+		name: "malformed placement node selector",
+		args: &runtime.RawExtension{Raw: []byte(`{"placement":{"nodeSelector":{"bad key":"amd64","disk":"not ssd"}}}`)},
+		want: func() *apis.FieldError {
+			key := apis.ErrInvalidKeyName("bad key", "spec.arguments.placement.nodeSelector",
+				"name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')")
+			value := apis.ErrInvalidValue("not ssd", "spec.arguments.placement.nodeSelector[disk]")
+			value.Details = "a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')"
+			return key.Also(value)
+		}(),
+	}, {
+		name: "empty placement zone",
+		args: &runtime.RawExtension{Raw: []byte(`{"placement":{"zones":[""]}}`)},
+		want: apis.ErrInvalidValue("", "spec.arguments.placement.zones[0]"),
+	}, {
+		name: "valid event schema",
+		args: &runtime.RawExtension{Raw: []byte(`{"eventSchema":{"type":"object","required":["id"]}}`)},
+	}, {
+		name: "unparseable event schema",
+		args: &runtime.RawExtension{Raw: []byte(`{"eventSchema":"type: object"}`)},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue(`"type: object"`, "spec.arguments.eventSchema")
+			fe.Details = "a JSON schema must be an object or a boolean"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Channel{
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+					Arguments: test.args,
+				},
+			}
+			got := c.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestChannelArgumentsOrderingValidation(t *testing.T) {
+	twoSubscribers := &Channelable{
+		Subscribers: []ChannelSubscriberSpec{{
+			CallableDomain: "one",
+		}, {
+			CallableDomain: "two",
+		}},
+	}
+	tests := []struct {
+		name        string
+		args        string
+		channelable *Channelable
+		want        *apis.FieldError
+	}{{
+		name:        "ordered, multiple subscribers, partitioned",
+		args:        `{"orderedDelivery":true,"partitionKey":"subject"}`,
+		channelable: twoSubscribers,
+		want:        nil,
+	}, {
+		name: "ordered, single subscriber, not partitioned",
+		args: `{"orderedDelivery":true}`,
+		channelable: &Channelable{
+			Subscribers: []ChannelSubscriberSpec{{
+				CallableDomain: "one",
+			}},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.arguments.partitionKey")
+			fe.Details = "ordered delivery requires a partition key to order events by"
+			return fe
+		}(),
+	}, {
+		name: "ordered, no subscribers, partitioned",
+		args: `{"orderedDelivery":true,"partitionKey":"subject"}`,
+		want: nil,
+	}, {
+		name: "unordered, not partitioned",
+		args: `{"orderedDelivery":false}`,
+		want: nil,
+	}, {
+		name:        "unordered, multiple subscribers",
+		args:        `{"orderedDelivery":false}`,
+		channelable: twoSubscribers,
+		want:        nil,
+	}, {
+		name:        "ordered, multiple subscribers, not partitioned",
+		args:        `{"orderedDelivery":true}`,
+		channelable: twoSubscribers,
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.arguments.partitionKey")
+			fe.Details = "ordered delivery requires a partition key to order events by"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Channel{
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+					Arguments:   &runtime.RawExtension{Raw: []byte(test.args)},
+					Channelable: test.channelable,
+				},
+			}
+			got := c.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidateDeadLetterSinkNamespace(t *testing.T) {
+	allowed := []string{"dead-letters", "ops"}
+	tests := []struct {
+		name string
+		args string
+		want *apis.FieldError
+	}{{
+		name: "no dead-letter sink",
+		args: `{"topic":"orders"}`,
+		want: nil,
+	}, {
+		name: "permitted namespace",
+		args: `{"deadLetterSink":{"apiVersion":"v1","kind":"Service","name":"dlq","namespace":"ops"}}`,
+		want: nil,
+	}, {
+		name: "forbidden namespace",
+		args: `{"deadLetterSink":{"apiVersion":"v1","kind":"Service","name":"dlq","namespace":"scratch"}}`,
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("scratch", "spec.arguments.deadLetterSink.namespace")
+			fe.Details = "dead-letter sinks must be in one of the namespaces [dead-letters ops]"
+			return fe
+		}(),
+	}, {
+		name: "defaults to the Channel's forbidden namespace",
+		args: `{"deadLetterSink":{"apiVersion":"v1","kind":"Service","name":"dlq"}}`,
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("test-namespace", "spec.arguments.deadLetterSink.namespace")
+			fe.Details = "dead-letter sinks must be in one of the namespaces [dead-letters ops]"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-namespace",
+				},
+				Spec: ChannelSpec{
+					Arguments: &runtime.RawExtension{Raw: []byte(test.args)},
+				},
+			}
+			got := c.ValidateDeadLetterSinkNamespace(allowed)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: ValidateDeadLetterSinkNamespace (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestChannel_EffectiveArguments(t *testing.T) {
+	prefix := "staging"
+	ordered := true
+	partitionKey := "subject"
+	concurrency := int32(8)
+	policyConcurrency := int32(2)
+	policy := &ChannelArguments{
+		DeadLetterSink: &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Name:       "cluster-dlq",
+		},
+		DefaultConcurrency: &policyConcurrency,
+	}
+	testCases := map[string]struct {
+		args   string
+		policy *ChannelArguments
+		want   ChannelArguments
+	}{
+		"channel overrides policy": {
+			args:   `{"defaultConcurrency":8,"deadLetterSink":{"apiVersion":"v1","kind":"Service","name":"dlq"}}`,
+			policy: policy,
+			want: ChannelArguments{
+				DeadLetterSink: &corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       "dlq",
+				},
+				DefaultConcurrency: &concurrency,
+			},
+		},
+		"policy fills gaps": {
+			args:   `{"consumerGroupPrefix":"staging","orderedDelivery":true}`,
+			policy: policy,
+			want: ChannelArguments{
+				ConsumerGroupPrefix: &prefix,
+				OrderedDelivery:     &ordered,
+				DeadLetterSink: &corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       "cluster-dlq",
+				},
+				DefaultConcurrency: &policyConcurrency,
+			},
+		},
+		"channel sets placement and schema": {
+			args:   `{"partitionKey":"subject","placement":{"zones":["us-east1-b"]},"eventSchema":true}`,
+			policy: policy,
+			want: ChannelArguments{
+				PartitionKey: &partitionKey,
+				DeadLetterSink: &corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       "cluster-dlq",
+				},
+				DefaultConcurrency: &policyConcurrency,
+				Placement:          &ChannelPlacement{Zones: []string{"us-east1-b"}},
+				EventSchema:        &runtime.RawExtension{Raw: []byte(`true`)},
+			},
+		},
+		"no arguments": {
+			policy: policy,
+			want:   *policy,
+		},
+		"no policy": {
+			args: `{"defaultConcurrency":8}`,
+			want: ChannelArguments{
+				DefaultConcurrency: &concurrency,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{}
+			if tc.args != "" {
+				c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(tc.args)}
+			}
+			got := c.EffectiveArguments(tc.policy)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected effective arguments (-want, +got) = %v", diff)
+			}
+		})
+	}
+	if *policy.DefaultConcurrency != 2 || policy.DeadLetterSink.Name != "cluster-dlq" {
+		t.Errorf("expected the policy not to be modified, got %+v", policy)
+	}
+}
+
+func TestChannelSpec_DecodeArguments(t *testing.T) {
+	type kafkaArguments struct {
+		Topic      string `json:"topic"`
+		Partitions int    `json:"partitions"`
+	}
+
+	cs := &ChannelSpec{
+		Arguments: &runtime.RawExtension{Raw: []byte(`{"topic":"orders","partitions":3}`)},
+	}
+	got := kafkaArguments{}
+	if err := cs.DecodeArguments(&got); err != nil {
+		t.Fatalf("Unexpected error decoding arguments: %v", err)
+	}
+	if diff := cmp.Diff(kafkaArguments{Topic: "orders", Partitions: 3}, got); diff != "" {
+		t.Errorf("unexpected arguments (-want, +got) = %v", diff)
+	}
+
+	untouched := kafkaArguments{Topic: "default"}
+	if err := (&ChannelSpec{}).DecodeArguments(&untouched); err != nil {
+		t.Fatalf("Unexpected error decoding nil arguments: %v", err)
+	}
+	if diff := cmp.Diff(kafkaArguments{Topic: "default"}, untouched); diff != "" {
+		t.Errorf("expected nil arguments to leave the target untouched (-want, +got) = %v", diff)
+	}
+
+	malformed := &ChannelSpec{
+		Arguments: &runtime.RawExtension{Raw: []byte(`{"topic":`)},
+	}
+	if err := malformed.DecodeArguments(&kafkaArguments{}); err == nil {
+		t.Errorf("expected an error decoding malformed arguments")
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ChannelOption is a functional option for building a Channel.
+type ChannelOption func(*Channel)
+
+// WithProvisioner sets the Provisioner of the Channel.
+func WithProvisioner(p *ProvisionerReference) ChannelOption {
+	return func(c *Channel) {
+		c.Spec.Provisioner = p
+	}
+}
+
+// WithArguments sets the Provisioner arguments of the Channel.
+func WithArguments(args *runtime.RawExtension) ChannelOption {
+	return func(c *Channel) {
+		c.Spec.Arguments = args
+	}
+}
+
+// NewChannelOwnedBy creates a Channel controlled by owner, whose kind is gvk. The controller owner
+// reference has BlockOwnerDeletion set, so that the Channel is garbage collected when the owner is
+// deleted and the owner's deletion waits for it.
+func NewChannelOwnedBy(owner metav1.Object, gvk schema.GroupVersionKind, name, namespace string, opts ...ChannelOption) *Channel {
+	c := &Channel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "Channel",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(owner, gvk),
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
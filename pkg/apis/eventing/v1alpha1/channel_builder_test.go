@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewChannelOwnedBy(t *testing.T) {
+	owner := &metav1.ObjectMeta{
+		Name:      "test-owner",
+		Namespace: "test-namespace",
+		UID:       "test-owner-uid",
+	}
+	gvk := schema.GroupVersionKind{
+		Group:   "example.knative.dev",
+		Version: "v1alpha1",
+		Kind:    "Owner",
+	}
+	provisioner := &ProvisionerReference{
+		Ref: &corev1.ObjectReference{
+			Name: "test-provisioner",
+		},
+	}
+	args := &runtime.RawExtension{Raw: []byte(`{"foo":"bar"}`)}
+
+	c := NewChannelOwnedBy(owner, gvk, "test-channel", "test-namespace", WithProvisioner(provisioner), WithArguments(args))
+
+	if c.Name != "test-channel" || c.Namespace != "test-namespace" {
+		t.Errorf("unexpected name: want test-namespace/test-channel, got %s/%s", c.Namespace, c.Name)
+	}
+	if c.Kind != "Channel" || c.APIVersion != SchemeGroupVersion.String() {
+		t.Errorf("unexpected TypeMeta: %v", c.TypeMeta)
+	}
+	if diff := cmp.Diff(provisioner, c.Spec.Provisioner); diff != "" {
+		t.Errorf("unexpected provisioner (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(args, c.Spec.Arguments); diff != "" {
+		t.Errorf("unexpected arguments (-want, +got) = %v", diff)
+	}
+
+	controllers := 0
+	for _, ref := range c.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			controllers++
+		}
+	}
+	if controllers != 1 {
+		t.Fatalf("unexpected number of controller references: want 1, got %d", controllers)
+	}
+	ref := metav1.GetControllerOf(c)
+	if ref.UID != owner.UID {
+		t.Errorf("unexpected owner UID: want %q, got %q", owner.UID, ref.UID)
+	}
+	if ref.Kind != gvk.Kind || ref.APIVersion != gvk.GroupVersion().String() || ref.Name != owner.Name {
+		t.Errorf("unexpected owner reference: %v", ref)
+	}
+	if ref.BlockOwnerDeletion == nil || !*ref.BlockOwnerDeletion {
+		t.Errorf("unexpected BlockOwnerDeletion: want true, got %v", ref.BlockOwnerDeletion)
+	}
+}
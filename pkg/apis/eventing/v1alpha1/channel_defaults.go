@@ -16,11 +16,78 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
 //TODO replace this with openapi defaults when
 // https://github.com/kubernetes/features/issues/575 lands (scheduled for 1.13)
 func (c *Channel) SetDefaults() {
+	// Channels decoded from admission requests may have an empty TypeMeta.
+	if c.Kind == "" {
+		c.Kind = "Channel"
+	}
+	if c.APIVersion == "" {
+		c.APIVersion = SchemeGroupVersion.String()
+	}
 	c.Spec.SetDefaults()
+	c.renderArgumentsTemplates()
 }
 
 func (fs *ChannelSpec) SetDefaults() {
+	if fs.Provisioner != nil {
+		fs.Provisioner.SetDefaults()
+	}
+}
+
+// ApplyClusterDefaults fills in the Channel's spec from a cluster-wide defaults Channel. The
+// defaults' Provisioner is used if the Channel doesn't have one, and the defaults' arguments are
+// merged beneath the Channel's own: objects are merged key by key, and any other value set on the
+// Channel takes precedence. A nil defaults is a no-op. An error is returned if either Channel's
+// arguments are not a JSON object.
+func (c *Channel) ApplyClusterDefaults(defaults *Channel) error {
+	if defaults == nil {
+		return nil
+	}
+	if c.Spec.Provisioner == nil && defaults.Spec.Provisioner != nil {
+		c.Spec.Provisioner = defaults.Spec.Provisioner.DeepCopy()
+	}
+	if defaults.Spec.Arguments == nil || len(defaults.Spec.Arguments.Raw) == 0 {
+		return nil
+	}
+	base := map[string]interface{}{}
+	if err := defaults.Spec.DecodeArguments(&base); err != nil {
+		return err
+	}
+	own := map[string]interface{}{}
+	if err := c.Spec.DecodeArguments(&own); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(mergeArguments(base, own))
+	if err != nil {
+		return err
+	}
+	c.Spec.Arguments = &runtime.RawExtension{Raw: raw}
+	return nil
+}
+
+// mergeArguments returns the result of merging over on top of base, recursing into objects present
+// in both.
+func mergeArguments(base, over map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(over))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range over {
+		bm, bok := merged[k].(map[string]interface{})
+		om, ook := v.(map[string]interface{})
+		if bok && ook {
+			merged[k] = mergeArguments(bm, om)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
 }
@@ -16,10 +16,178 @@ limitations under the License.
 
 package v1alpha1
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
-// No-op test because method does nothing.
 func TestChannelSetDefaults(t *testing.T) {
-	c := Channel{}
+	testCases := map[string]struct {
+		args *runtime.RawExtension
+		want *runtime.RawExtension
+	}{
+		"nil arguments": {},
+		"literal": {
+			args: &runtime.RawExtension{Raw: []byte(`{"topic":"events"}`)},
+			want: &runtime.RawExtension{Raw: []byte(`{"topic":"events"}`)},
+		},
+		"name": {
+			args: &runtime.RawExtension{Raw: []byte(`{"topic":"{{.Name}}-events"}`)},
+			want: &runtime.RawExtension{Raw: []byte(`{"topic":"test-name-events"}`)},
+		},
+		"namespace": {
+			args: &runtime.RawExtension{Raw: []byte(`{"topic":"{{.Namespace}}"}`)},
+			want: &runtime.RawExtension{Raw: []byte(`{"topic":"test-namespace"}`)},
+		},
+		"uid": {
+			args: &runtime.RawExtension{Raw: []byte(`{"group":{"id":"{{.UID}}"}}`)},
+			want: &runtime.RawExtension{Raw: []byte(`{"group":{"id":"test-uid"}}`)},
+		},
+		"unknown key is left for validation": {
+			args: &runtime.RawExtension{Raw: []byte(`{"topic":"{{.Bogus}}"}`)},
+			want: &runtime.RawExtension{Raw: []byte(`{"topic":"{{.Bogus}}"}`)},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-namespace",
+					Name:      "test-name",
+					UID:       "test-uid",
+				},
+				Spec: ChannelSpec{
+					Arguments: tc.args,
+				},
+			}
+			c.SetDefaults()
+			if diff := cmp.Diff(tc.want, c.Spec.Arguments); diff != "" {
+				t.Errorf("unexpected arguments (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelSetDefaults_UnsetMetadata(t *testing.T) {
+	// A Channel being created has no uid yet, nor a name when it uses generateName.
+	c := &Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    "test-namespace",
+			GenerateName: "test-",
+		},
+		Spec: ChannelSpec{
+			Arguments: &runtime.RawExtension{Raw: []byte(`{"group":"{{.UID}}","namespace":"{{.Namespace}}","topic":"{{.Name}}-events"}`)},
+		},
+	}
 	c.SetDefaults()
+	want := &runtime.RawExtension{Raw: []byte(`{"group":"{{.UID}}","namespace":"test-namespace","topic":"{{.Name}}-events"}`)}
+	if diff := cmp.Diff(want, c.Spec.Arguments); diff != "" {
+		t.Errorf("unexpected arguments (-want, +got) = %v", diff)
+	}
+}
+
+func TestChannelSetDefaults_TypeMeta(t *testing.T) {
+	testCases := map[string]struct {
+		typeMeta metav1.TypeMeta
+		want     metav1.TypeMeta
+	}{
+		"empty": {
+			want: metav1.TypeMeta{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+			},
+		},
+		"already set": {
+			typeMeta: metav1.TypeMeta{
+				APIVersion: "eventing.knative.dev/v1alpha2",
+				Kind:       "Channel",
+			},
+			want: metav1.TypeMeta{
+				APIVersion: "eventing.knative.dev/v1alpha2",
+				Kind:       "Channel",
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{TypeMeta: tc.typeMeta}
+			c.SetDefaults()
+			if diff := cmp.Diff(tc.want, c.TypeMeta); diff != "" {
+				t.Errorf("unexpected TypeMeta (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelApplyClusterDefaults(t *testing.T) {
+	provisioner := func(name string) *ProvisionerReference {
+		return &ProvisionerReference{
+			Ref: &corev1.ObjectReference{
+				Name: name,
+			},
+		}
+	}
+	args := func(raw string) *runtime.RawExtension {
+		return &runtime.RawExtension{Raw: []byte(raw)}
+	}
+	testCases := map[string]struct {
+		spec     ChannelSpec
+		defaults *Channel
+		want     ChannelSpec
+		wantErr  bool
+	}{
+		"nil defaults": {
+			spec: ChannelSpec{Provisioner: provisioner("kafka")},
+			want: ChannelSpec{Provisioner: provisioner("kafka")},
+		},
+		"provisioner from defaults": {
+			defaults: &Channel{Spec: ChannelSpec{Provisioner: provisioner("kafka")}},
+			want:     ChannelSpec{Provisioner: provisioner("kafka")},
+		},
+		"own provisioner takes precedence": {
+			spec:     ChannelSpec{Provisioner: provisioner("in-memory-channel")},
+			defaults: &Channel{Spec: ChannelSpec{Provisioner: provisioner("kafka")}},
+			want:     ChannelSpec{Provisioner: provisioner("in-memory-channel")},
+		},
+		"arguments from defaults": {
+			defaults: &Channel{Spec: ChannelSpec{Arguments: args(`{"retention":"1d"}`)}},
+			want:     ChannelSpec{Arguments: args(`{"retention":"1d"}`)},
+		},
+		"own arguments take precedence": {
+			spec: ChannelSpec{Arguments: args(`{"retention":"7d","config":{"partitions":6}}`)},
+			defaults: &Channel{Spec: ChannelSpec{
+				Arguments: args(`{"retention":"1d","replicas":3,"config":{"partitions":3,"compression":"gzip"}}`),
+			}},
+			want: ChannelSpec{
+				Arguments: args(`{"config":{"compression":"gzip","partitions":6},"replicas":3,"retention":"7d"}`),
+			},
+		},
+		"malformed default arguments": {
+			spec:     ChannelSpec{Arguments: args(`{"retention":"7d"}`)},
+			defaults: &Channel{Spec: ChannelSpec{Arguments: args(`["retention"]`)}},
+			wantErr:  true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{Spec: tc.spec}
+			err := c.ApplyClusterDefaults(tc.defaults)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got spec %+v", c.Spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, c.Spec); diff != "" {
+				t.Errorf("unexpected spec (-want, +got) = %v", diff)
+			}
+		})
+	}
 }
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ChannelsEquivalent returns true if a and b are functionally interchangeable: they use the same
+// Provisioner, their arguments are semantically equal and they have the same labels, ignoring the
+// Provisioner's reserved labels. Status and all other metadata are ignored.
+func ChannelsEquivalent(a, b *Channel) bool {
+	return provisionersEquivalent(a.Spec.Provisioner, b.Spec.Provisioner) &&
+		argumentsEquivalent(a.Spec.Arguments, b.Spec.Arguments) &&
+		reflect.DeepEqual(userLabels(a.Labels), userLabels(b.Labels))
+}
+
+// provisionersEquivalent compares the Provisioners the references resolve to, filling in the
+// defaults for an omitted apiVersion and kind.
+func provisionersEquivalent(a, b *ProvisionerReference) bool {
+	ra, rb := normalizedProvisionerRef(a), normalizedProvisionerRef(b)
+	if ra == nil || rb == nil {
+		return ra == rb
+	}
+	return ra.APIVersion == rb.APIVersion && ra.Kind == rb.Kind && ra.Name == rb.Name
+}
+
+func normalizedProvisionerRef(p *ProvisionerReference) *corev1.ObjectReference {
+	if p == nil || p.Ref == nil {
+		return nil
+	}
+	ref := p.Ref.DeepCopy()
+	if ref.APIVersion == "" {
+		ref.APIVersion = SchemeGroupVersion.String()
+	}
+	if ref.Kind == "" {
+		ref.Kind = "ClusterProvisioner"
+	}
+	return ref
+}
+
+// argumentsEquivalent compares the decoded arguments, so that formatting and key order don't
+// matter. Omitted arguments are equivalent to an empty object.
+func argumentsEquivalent(a, b *runtime.RawExtension) bool {
+	ra, rb := rawArguments(a), rawArguments(b)
+	var va, vb interface{}
+	if json.Unmarshal(ra, &va) != nil || json.Unmarshal(rb, &vb) != nil {
+		return bytes.Equal(ra, rb)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func rawArguments(args *runtime.RawExtension) []byte {
+	if args == nil || len(args.Raw) == 0 {
+		return []byte("{}")
+	}
+	return args.Raw
+}
+
+// userLabels returns the labels not under ReservedMetadataPrefix.
+func userLabels(labels map[string]string) map[string]string {
+	filtered := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if ReservedMetadataPrefix == "" || !strings.HasPrefix(k, ReservedMetadataPrefix) {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestChannelsEquivalent(t *testing.T) {
+	base := &Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "a",
+			Namespace: "test-namespace",
+			Labels:    map[string]string{"app": "orders"},
+		},
+		Spec: ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Name: "kafka",
+				},
+			},
+			Arguments: &runtime.RawExtension{Raw: []byte(`{"topic":"orders","partitions":3}`)},
+		},
+	}
+	testCases := map[string]struct {
+		mutate func(*Channel)
+		want   bool
+	}{
+		"identical": {
+			mutate: func(*Channel) {},
+			want:   true,
+		},
+		"metadata and status noise": {
+			mutate: func(c *Channel) {
+				c.Name = "b"
+				c.UID = "test-uid"
+				c.Labels["internal.eventing.knative.dev/topic"] = "orders"
+				c.Status.MarkProvisioned()
+			},
+			want: true,
+		},
+		"explicit provisioner defaults": {
+			mutate: func(c *Channel) {
+				c.Spec.Provisioner.Ref.APIVersion = "eventing.knative.dev/v1alpha1"
+				c.Spec.Provisioner.Ref.Kind = "ClusterProvisioner"
+			},
+			want: true,
+		},
+		"reordered arguments": {
+			mutate: func(c *Channel) {
+				c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(`{ "partitions": 3, "topic": "orders" }`)}
+			},
+			want: true,
+		},
+		"different provisioner": {
+			mutate: func(c *Channel) {
+				c.Spec.Provisioner.Ref.Name = "in-memory-channel"
+			},
+			want: false,
+		},
+		"different arguments": {
+			mutate: func(c *Channel) {
+				c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(`{"topic":"orders","partitions":4}`)}
+			},
+			want: false,
+		},
+		"different labels": {
+			mutate: func(c *Channel) {
+				c.Labels["app"] = "payments"
+			},
+			want: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			other := base.DeepCopy()
+			tc.mutate(other)
+			if got := ChannelsEquivalent(base, other); got != tc.want {
+				t.Errorf("unexpected equivalence: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
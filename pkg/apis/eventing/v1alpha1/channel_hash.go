@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SpecHashAnnotation is the annotation on which a Channel's reconciler records the Hash of the
+// spec it last reconciled, so that spec changes can be detected by comparing against it.
+const SpecHashAnnotation = "eventing.knative.dev/specHash"
+
+// SpecHash returns the Hash of the Channel's spec. Metadata and status don't affect it.
+func (c *Channel) SpecHash() (string, error) {
+	return c.Spec.Hash()
+}
+
+// Hash returns a stable SHA256 hash of the spec, suitable for stamping as an annotation to detect
+// spec changes. The Arguments are canonicalized first, so that their key order and formatting
+// don't affect the hash. Generation is not part of the hash.
+func (cs *ChannelSpec) Hash() (string, error) {
+	canonical := cs.DeepCopy()
+	canonical.Generation = 0
+	if cs.Arguments != nil && len(cs.Arguments.Raw) > 0 {
+		var args interface{}
+		if err := json.Unmarshal(cs.Arguments.Raw, &args); err != nil {
+			return "", err
+		}
+		// encoding/json sorts map keys.
+		raw, err := json.Marshal(args)
+		if err != nil {
+			return "", err
+		}
+		canonical.Arguments = &runtime.RawExtension{Raw: raw}
+	}
+	b, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestChannelSpec_Hash(t *testing.T) {
+	spec := func(provisioner, args string) *ChannelSpec {
+		return &ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Name: provisioner,
+				},
+			},
+			Arguments: &runtime.RawExtension{Raw: []byte(args)},
+		}
+	}
+	hash := func(cs *ChannelSpec) string {
+		h, err := cs.Hash()
+		if err != nil {
+			t.Fatalf("Unexpected error hashing %+v: %v", cs, err)
+		}
+		return h
+	}
+
+	original := hash(spec("kafka", `{"topic":"orders","config":{"retention":"1d","partitions":3}}`))
+	reordered := hash(spec("kafka", `{ "config": { "partitions": 3, "retention": "1d" }, "topic": "orders" }`))
+	if original != reordered {
+		t.Errorf("expected reordered arguments to hash the same, got %q and %q", original, reordered)
+	}
+
+	regenerated := spec("kafka", `{"topic":"orders","config":{"retention":"1d","partitions":3}}`)
+	regenerated.Generation = 7
+	if h := hash(regenerated); h != original {
+		t.Errorf("expected Generation to be ignored, got %q and %q", original, h)
+	}
+
+	changed := hash(spec("in-memory-channel", `{"topic":"orders","config":{"retention":"1d","partitions":3}}`))
+	if original == changed {
+		t.Errorf("expected a changed provisioner to change the hash, got %q for both", original)
+	}
+
+	changedArgs := hash(spec("kafka", `{"topic":"orders","config":{"retention":"7d","partitions":3}}`))
+	if original == changedArgs {
+		t.Errorf("expected changed arguments to change the hash, got %q for both", original)
+	}
+
+	if _, err := spec("kafka", `{not json`).Hash(); err == nil {
+		t.Errorf("expected an error hashing malformed arguments")
+	}
+}
+
+func TestChannel_SpecHash(t *testing.T) {
+	channel := func(args string) *Channel {
+		return &Channel{
+			Spec: ChannelSpec{
+				Arguments: &runtime.RawExtension{Raw: []byte(args)},
+			},
+		}
+	}
+	a := channel(`{"topic":"orders","partitions":3}`)
+	a.Name = "a"
+	a.Status.MarkProvisioned()
+	b := channel(`{"partitions":3,"topic":"orders"}`)
+	b.Name = "b"
+
+	ha, err := a.SpecHash()
+	if err != nil {
+		t.Fatalf("Unexpected error hashing %+v: %v", a, err)
+	}
+	hb, err := b.SpecHash()
+	if err != nil {
+		t.Fatalf("Unexpected error hashing %+v: %v", b, err)
+	}
+	if ha != hb {
+		t.Errorf("expected equal specs to hash the same regardless of metadata and status, got %q and %q", ha, hb)
+	}
+
+	hc, err := channel(`{"topic":"orders","partitions":4}`).SpecHash()
+	if err != nil {
+		t.Fatalf("Unexpected error hashing: %v", err)
+	}
+	if ha == hc {
+		t.Errorf("expected a changed spec to change the hash, got %q for both", ha)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// chanManagedConditionTypes are the conditions reported by ChannelConditionMetrics.
+var chanManagedConditionTypes = append([]duckv1alpha1.ConditionType{ChannelConditionReady}, chanDependentConditionTypes...)
+
+// ChannelConditionMetrics returns a gauge value for the Ready condition and each condition it
+// depends on, keyed by condition type: 1 if the condition is True, 0 if it is False and -1 if it is
+// Unknown or not set. It registers nothing, so callers can feed the values to their own collector.
+func ChannelConditionMetrics(ch *Channel) map[string]float64 {
+	metrics := make(map[string]float64, len(chanManagedConditionTypes))
+	for _, t := range chanManagedConditionTypes {
+		value := float64(-1)
+		if c := ch.Status.GetCondition(t); c != nil {
+			switch c.Status {
+			case corev1.ConditionTrue:
+				value = 1
+			case corev1.ConditionFalse:
+				value = 0
+			}
+		}
+		metrics[string(t)] = value
+	}
+	return metrics
+}
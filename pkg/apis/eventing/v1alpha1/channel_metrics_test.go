@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestChannelConditionMetrics(t *testing.T) {
+	ch := &Channel{
+		Status: ChannelStatus{
+			Conditions: []duckv1alpha1.Condition{{
+				Type:   ChannelConditionReady,
+				Status: corev1.ConditionFalse,
+			}, {
+				Type:   ChannelConditionProvisioned,
+				Status: corev1.ConditionTrue,
+			}, {
+				Type:   ChannelConditionSinkable,
+				Status: corev1.ConditionUnknown,
+			}},
+		},
+	}
+	want := map[string]float64{
+		"Ready":        0,
+		"Provisioned":  1,
+		"Sinkable":     -1,
+		"Subscribable": -1,
+		"Compatible":   -1,
+	}
+	got := ChannelConditionMetrics(ch)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected metrics (-want, +got) = %v", diff)
+	}
+}
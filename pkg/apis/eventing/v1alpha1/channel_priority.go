@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// ReconcilePriorityAnnotation marks a Channel as one to reconcile before or after others, e.g.
+	// so that critical Channels recover first after a controller restart. Its value is one of
+	// ReconcilePriorityHighValue or ReconcilePriorityLowValue.
+	ReconcilePriorityAnnotation = "eventing.knative.dev/reconcilePriority"
+
+	ReconcilePriorityHighValue = "high"
+	ReconcilePriorityLowValue  = "low"
+)
+
+const (
+	// ReconcilePriorityLow is the priority of Channels annotated as low priority.
+	ReconcilePriorityLow = -1
+	// ReconcilePriorityDefault is the priority of Channels without a recognized priority annotation.
+	ReconcilePriorityDefault = 0
+	// ReconcilePriorityHigh is the priority of Channels annotated as high priority.
+	ReconcilePriorityHigh = 1
+)
+
+// ReconcilePriority returns the priority a workqueue should give the Channel, from its
+// ReconcilePriorityAnnotation. Channels with higher priorities should be reconciled first.
+func (c *Channel) ReconcilePriority() int {
+	switch c.Annotations[ReconcilePriorityAnnotation] {
+	case ReconcilePriorityHighValue:
+		return ReconcilePriorityHigh
+	case ReconcilePriorityLowValue:
+		return ReconcilePriorityLow
+	default:
+		return ReconcilePriorityDefault
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestChannel_ReconcilePriority(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        int
+	}{
+		"annotated high": {
+			annotations: map[string]string{ReconcilePriorityAnnotation: "high"},
+			want:        ReconcilePriorityHigh,
+		},
+		"annotated low": {
+			annotations: map[string]string{ReconcilePriorityAnnotation: "low"},
+			want:        ReconcilePriorityLow,
+		},
+		"unrecognized value": {
+			annotations: map[string]string{ReconcilePriorityAnnotation: "urgent"},
+			want:        ReconcilePriorityDefault,
+		},
+		"default": {
+			want: ReconcilePriorityDefault,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			if got := c.ReconcilePriority(); got != tc.want {
+				t.Errorf("unexpected priority: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// ChannelStatusRecorder wraps the Mark* and Set* helpers of a Channel's status, emitting a
+// Kubernetes event on the Channel whenever one of its conditions changes status or reason.
+// Repeated identical calls don't change the condition, so they don't emit events either.
+// +k8s:deepcopy-gen=false
+type ChannelStatusRecorder struct {
+	channel  *Channel
+	recorder record.EventRecorder
+}
+
+// NewChannelStatusRecorder returns a ChannelStatusRecorder for the given Channel. If recorder is
+// nil, the status is updated without emitting any events.
+func NewChannelStatusRecorder(c *Channel, recorder record.EventRecorder) *ChannelStatusRecorder {
+	return &ChannelStatusRecorder{
+		channel:  c,
+		recorder: recorder,
+	}
+}
+
+// MarkProvisioned calls ChannelStatus.MarkProvisioned.
+func (r *ChannelStatusRecorder) MarkProvisioned() {
+	r.record(func(cs *ChannelStatus) { cs.MarkProvisioned() })
+}
+
+// PropagateProvisionerStatus calls ChannelStatus.PropagateProvisionerStatus.
+func (r *ChannelStatusRecorder) PropagateProvisionerStatus(ready bool, reason, message string) {
+	r.record(func(cs *ChannelStatus) { cs.PropagateProvisionerStatus(ready, reason, message) })
+}
+
+// MarkProvisioningFailed calls ChannelStatus.MarkProvisioningFailed.
+func (r *ChannelStatusRecorder) MarkProvisioningFailed(reason, messageFormat string, messageA ...interface{}) {
+	r.record(func(cs *ChannelStatus) { cs.MarkProvisioningFailed(reason, messageFormat, messageA...) })
+}
+
+// MarkCompatible calls ChannelStatus.MarkCompatible.
+func (r *ChannelStatusRecorder) MarkCompatible() {
+	r.record(func(cs *ChannelStatus) { cs.MarkCompatible() })
+}
+
+// MarkIncompatible calls ChannelStatus.MarkIncompatible.
+func (r *ChannelStatusRecorder) MarkIncompatible(reason, message string) {
+	r.record(func(cs *ChannelStatus) { cs.MarkIncompatible(reason, message) })
+}
+
+// MarkIngressReady calls ChannelStatus.MarkIngressReady.
+func (r *ChannelStatusRecorder) MarkIngressReady() {
+	r.record(func(cs *ChannelStatus) { cs.MarkIngressReady() })
+}
+
+// MarkIngressNotReady calls ChannelStatus.MarkIngressNotReady.
+func (r *ChannelStatusRecorder) MarkIngressNotReady(reason, message string) {
+	r.record(func(cs *ChannelStatus) { cs.MarkIngressNotReady(reason, message) })
+}
+
+// MarkQuarantined calls ChannelStatus.MarkQuarantined.
+func (r *ChannelStatusRecorder) MarkQuarantined(reason string) {
+	r.record(func(cs *ChannelStatus) { cs.MarkQuarantined(reason) })
+}
+
+// MarkWarning calls ChannelStatus.MarkWarning.
+func (r *ChannelStatusRecorder) MarkWarning(conditionType duckv1alpha1.ConditionType, reason, message string) {
+	r.record(func(cs *ChannelStatus) { cs.MarkWarning(conditionType, reason, message) })
+}
+
+// MarkInfo calls ChannelStatus.MarkInfo.
+func (r *ChannelStatusRecorder) MarkInfo(conditionType duckv1alpha1.ConditionType, reason, message string) {
+	r.record(func(cs *ChannelStatus) { cs.MarkInfo(conditionType, reason, message) })
+}
+
+// SetSubscribable calls ChannelStatus.SetSubscribable.
+func (r *ChannelStatusRecorder) SetSubscribable(namespace, name string) {
+	r.record(func(cs *ChannelStatus) { cs.SetSubscribable(namespace, name) })
+}
+
+// SetSubscribableTargets calls ChannelStatus.SetSubscribableTargets.
+func (r *ChannelStatusRecorder) SetSubscribableTargets(refs []corev1.ObjectReference) {
+	r.record(func(cs *ChannelStatus) { cs.SetSubscribableTargets(refs) })
+}
+
+// SetSinkable calls ChannelStatus.SetSinkable.
+func (r *ChannelStatusRecorder) SetSinkable(domainInternal string) {
+	r.record(func(cs *ChannelStatus) { cs.SetSinkable(domainInternal) })
+}
+
+// record applies mark to the Channel's status and emits an event for every condition whose status
+// or reason it changed. Conditions turning False are reported as warnings.
+func (r *ChannelStatusRecorder) record(mark func(*ChannelStatus)) {
+	before := make(map[duckv1alpha1.ConditionType]duckv1alpha1.Condition, len(r.channel.Status.Conditions))
+	for _, c := range r.channel.Status.Conditions {
+		before[c.Type] = c
+	}
+	mark(&r.channel.Status)
+	if r.recorder == nil {
+		return
+	}
+	for _, c := range r.channel.Status.Conditions {
+		if b, ok := before[c.Type]; ok && b.Status == c.Status && b.Reason == c.Reason {
+			continue
+		}
+		eventType := corev1.EventTypeNormal
+		if c.Status == corev1.ConditionFalse {
+			eventType = corev1.EventTypeWarning
+		}
+		message := fmt.Sprintf("%s is %s", c.Type, c.Status)
+		if c.Reason != "" {
+			message = fmt.Sprintf("%s: %s - %s", message, c.Reason, c.Message)
+		}
+		r.recorder.Event(r.channel, eventType, string(c.Type), message)
+	}
+}
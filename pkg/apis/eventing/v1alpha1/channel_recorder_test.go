@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestChannelStatusRecorder(t *testing.T) {
+	c := &Channel{}
+	c.Status.InitializeConditions()
+	recorder := record.NewFakeRecorder(10)
+	r := NewChannelStatusRecorder(c, recorder)
+
+	r.MarkProvisioned()
+	if diff := cmp.Diff([]string{"Normal Provisioned Provisioned is True"}, drainEvents(recorder)); diff != "" {
+		t.Errorf("unexpected events on transition (-want, +got) = %v", diff)
+	}
+	if !c.Status.GetCondition(ChannelConditionProvisioned).IsTrue() {
+		t.Errorf("expected the Provisioned condition to be True")
+	}
+
+	r.MarkProvisioned()
+	if events := drainEvents(recorder); len(events) != 0 {
+		t.Errorf("expected no events on a repeated identical call, got %v", events)
+	}
+
+	r.SetSinkable("")
+	want := []string{
+		"Warning Ready Ready is False: emptyDomainInternal - domainInternal is the empty string",
+		"Warning Sinkable Sinkable is False: emptyDomainInternal - domainInternal is the empty string",
+	}
+	if diff := cmp.Diff(want, drainEvents(recorder)); diff != "" {
+		t.Errorf("unexpected events on failure (-want, +got) = %v", diff)
+	}
+
+	r.SetSinkable("")
+	if events := drainEvents(recorder); len(events) != 0 {
+		t.Errorf("expected no events on a repeated identical call, got %v", events)
+	}
+}
+
+func TestChannelStatusRecorder_NilRecorder(t *testing.T) {
+	c := &Channel{}
+	NewChannelStatusRecorder(c, nil).MarkProvisioned()
+	if !c.Status.GetCondition(ChannelConditionProvisioned).IsTrue() {
+		t.Errorf("expected the Provisioned condition to be True")
+	}
+}
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
@@ -17,6 +17,10 @@
 package v1alpha1
 
 import (
+	"errors"
+	"sort"
+	"time"
+
 	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/webhook"
@@ -65,18 +69,49 @@ type ChannelSpec struct {
 
 	// Provisioner defines the name of the Provisioner backing this channel.
 	// TODO: +optional If missing, a default Provisioner may be selected for the Channel.
-	Provisioner *ProvisionerReference `json:"provisioner,omitempty"`
+	// This field is immutable.
+	Provisioner *ProvisionerReference `json:"provisioner,omitempty" immutable:"true"`
 
 	// Arguments defines the arguments to pass to the Provisioner which provisions
-	// this Channel.
+	// this Channel. They are immutable, except for those the Provisioner can reconfigure.
 	// +optional
 	Arguments *runtime.RawExtension `json:"arguments,omitempty"`
 
 	// Channel conforms to Duck type Channelable.
-	Channelable *duckv1alpha1.Channelable `json:"channelable,omitempty"`
+	Channelable *Channelable `json:"channelable,omitempty"`
+
+	// Paused asks the Provisioner to stop delivering events to subscribers, without deleting the
+	// Channel, e.g. during maintenance.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// chanDependentConditionTypes are the conditions ChannelConditionReady depends on.
+var chanDependentConditionTypes = []duckv1alpha1.ConditionType{
+	ChannelConditionProvisioned,
+	ChannelConditionSinkable,
+	ChannelConditionSubscribable,
+	ChannelConditionCompatible,
 }
 
-var chanCondSet = duckv1alpha1.NewLivingConditionSet(ChannelConditionProvisioned, ChannelConditionSinkable, ChannelConditionSubscribable)
+var chanCondSet = duckv1alpha1.NewLivingConditionSet(chanDependentConditionTypes...)
+
+// BackingResourceNotReadyReason is the reason ChannelConditionProvisioned is False when a backing
+// resource is not ready and no more specific reason was given.
+const BackingResourceNotReadyReason = "BackingResourceNotReady"
+
+// chanConditionTypes are all the condition types a Channel may have, regardless of the
+// Provisioner backing it.
+var chanConditionTypes = []duckv1alpha1.ConditionType{
+	ChannelConditionReady,
+	ChannelConditionProvisioned,
+	ChannelConditionSinkable,
+	ChannelConditionSubscribable,
+	ChannelConditionCompatible,
+	ChannelConditionIngressReady,
+	ChannelConditionQuarantined,
+	ChannelConditionPaused,
+}
 
 // ChannelStatus represents the current state of a Channel.
 type ChannelStatus struct {
@@ -93,9 +128,60 @@ type ChannelStatus struct {
 	// It generally has the form {channel}.{namespace}.svc.cluster.local
 	Sinkable duckv1alpha1.Sinkable `json:"sinkable,omitempty"`
 
+	// DomainExternal is the publicly routable domain through which the Channel accepts events from
+	// outside the cluster, for Provisioners that expose one.
+	// +optional
+	DomainExternal string `json:"domainExternal,omitempty"`
+
 	// Channel is Subscribable. It just points to itself
 	Subscribable duckv1alpha1.Subscribable `json:"subscribable,omitempty"`
 
+	// SubscribableTargets are the Channelables a Channel that fans out to several backing channels
+	// can be subscribed through. Subscribable points at the first of them.
+	// +optional
+	SubscribableTargets []corev1.ObjectReference `json:"subscribableTargets,omitempty"`
+
+	// MetricsAddress is the host:port of the metrics endpoint the Provisioner exposes for this
+	// Channel, if any, for scrapers to discover.
+	// +optional
+	MetricsAddress string `json:"metricsAddress,omitempty"`
+
+	// EffectiveRetention is how long the Provisioner actually retains events in the Channel,
+	// which may differ from the retention requested in the arguments, e.g. "168h0m0s". It is
+	// informational only.
+	// +optional
+	EffectiveRetention *metav1.Duration `json:"effectiveRetention,omitempty"`
+
+	// Backend identifies the concrete backend serving the Channel, e.g. the URL of a Kafka
+	// cluster, for when several instances of its Provisioner exist. It is informational only.
+	// +optional
+	Backend string `json:"backend,omitempty"`
+
+	// DeliveryStats is the backlog of events the Provisioner observed in the Channel, e.g. for
+	// autoscaling subscribers. It is informational only.
+	// +optional
+	DeliveryStats *ChannelDeliveryStats `json:"deliveryStats,omitempty"`
+
+	// SubscriberLags is how far behind each subscriber is, as observed by the Provisioner, for
+	// operators to spot slow consumers. It is informational only.
+	// +optional
+	SubscriberLags []SubscriberLag `json:"subscriberLags,omitempty"`
+
+	// LastReadyTime is when the Channel last became Ready. It is kept when the Channel stops being
+	// Ready, to tell a degraded Channel apart from one that was never Ready.
+	// +optional
+	LastReadyTime *apis.VolatileTime `json:"lastReadyTime,omitempty"`
+
+	// ConditionTransitions is, by condition type, how many times the condition left True or False
+	// state, to tell a flapping Channel apart from one that is merely not Ready yet.
+	// +optional
+	ConditionTransitions map[duckv1alpha1.ConditionType]int64 `json:"conditionTransitions,omitempty"`
+
+	// ConditionSeverities is, by condition type, the severity of the condition's current state.
+	// Conditions that are not listed have ConditionSeverityError severity.
+	// +optional
+	ConditionSeverities map[duckv1alpha1.ConditionType]ConditionSeverity `json:"conditionSeverities,omitempty"`
+
 	// Represents the latest available observations of a channel's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -119,6 +205,43 @@ const (
 	// ChannelConditionSubscribable has status true when this Channel meets the Subscribable
 	// contract and has a non-empty Channelable object reference.
 	ChannelConditionSubscribable duckv1alpha1.ConditionType = "Subscribable"
+
+	// ChannelConditionCompatible has status True when the installed version of the Channel's
+	// Provisioner accepts the Channel's arguments.
+	ChannelConditionCompatible duckv1alpha1.ConditionType = "Compatible"
+
+	// ChannelConditionIngressReady has status True when the Channel's ingress is accepting
+	// traffic, which it may do while its backing storage is still being provisioned or rebalanced.
+	// It is set only by Provisioners that track their ingress separately. While it is present and
+	// not True, the Channel is not Sinkable.
+	ChannelConditionIngressReady duckv1alpha1.ConditionType = "IngressReady"
+
+	// ChannelConditionQuarantined has status True when the Channel's provisioner has detected
+	// persistent downstream delivery failures. It is informational only and does not affect
+	// ChannelConditionReady.
+	ChannelConditionQuarantined duckv1alpha1.ConditionType = "Quarantined"
+
+	// ChannelConditionPaused has status True when the Channel's provisioner has stopped
+	// delivering events because the Channel is paused. It is informational only and does not
+	// affect ChannelConditionReady.
+	ChannelConditionPaused duckv1alpha1.ConditionType = "Paused"
+)
+
+// ConditionSeverity is how serious the current state of a condition is. Only conditions with
+// ConditionSeverityError severity keep a Channel from being Ready.
+type ConditionSeverity string
+
+const (
+	// ConditionSeverityError is the severity of conditions that keep the Channel from being Ready
+	// unless they are True. It is the default.
+	ConditionSeverityError ConditionSeverity = "Error"
+
+	// ConditionSeverityWarning is the severity of conditions that surface a problem, such as a
+	// deprecation, that doesn't keep the Channel from being Ready.
+	ConditionSeverityWarning ConditionSeverity = "Warning"
+
+	// ConditionSeverityInfo is the severity of conditions that are informational only.
+	ConditionSeverityInfo ConditionSeverity = "Info"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -126,9 +249,122 @@ func (cs *ChannelStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha
 	return chanCondSet.Manage(cs).GetCondition(t)
 }
 
-// IsReady returns true if the resource is ready overall.
+// GetConditions implements duckv1alpha1.ConditionsAccessor.
+func (cs *ChannelStatus) GetConditions() duckv1alpha1.Conditions {
+	return cs.Conditions
+}
+
+// SetConditions implements duckv1alpha1.ConditionsAccessor, counting the transitions of every
+// condition that leaves True or False state in ConditionTransitions. Conditions that change, or are
+// removed, revert to ConditionSeverityError severity.
+func (cs *ChannelStatus) SetConditions(conditions duckv1alpha1.Conditions) {
+	severities := make(map[duckv1alpha1.ConditionType]ConditionSeverity)
+	for _, c := range conditions {
+		old := cs.GetCondition(c.Type)
+		if old != nil && old.Status == c.Status && old.Reason == c.Reason && old.Message == c.Message {
+			if s, ok := cs.ConditionSeverities[c.Type]; ok {
+				severities[c.Type] = s
+			}
+		}
+		if old == nil || old.Status == c.Status || old.Status == corev1.ConditionUnknown {
+			continue
+		}
+		if cs.ConditionTransitions == nil {
+			cs.ConditionTransitions = make(map[duckv1alpha1.ConditionType]int64)
+		}
+		cs.ConditionTransitions[c.Type]++
+	}
+	if len(severities) == 0 {
+		severities = nil
+	}
+	cs.ConditionSeverities = severities
+	cs.Conditions = conditions
+}
+
+// GetConditionSeverity returns the severity of the current state of the condition of type t.
+func (cs *ChannelStatus) GetConditionSeverity(t duckv1alpha1.ConditionType) ConditionSeverity {
+	if s, ok := cs.ConditionSeverities[t]; ok {
+		return s
+	}
+	return ConditionSeverityError
+}
+
+// CountConditionTransitions returns how many times the condition of type t left True or False
+// state, e.g. for alerting on a flapping Channel.
+func CountConditionTransitions(cs *ChannelStatus, t duckv1alpha1.ConditionType) int {
+	return int(cs.ConditionTransitions[t])
+}
+
+// IsReady returns true if the resource is ready overall: Ready is True, or every condition it
+// depends on is True but for those with a severity below ConditionSeverityError.
 func (cs *ChannelStatus) IsReady() bool {
-	return chanCondSet.Manage(cs).IsHappy()
+	if chanCondSet.Manage(cs).IsHappy() {
+		return true
+	}
+	for _, t := range chanDependentConditionTypes {
+		c := cs.GetCondition(t)
+		if c == nil || !c.IsTrue() && cs.GetConditionSeverity(t) == ConditionSeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// ExitCode summarizes the Channel's readiness as a process exit code, for commands that wait on a
+// Channel: 0 when it is ready, 1 when it has failed and 2 while it is still being reconciled.
+func (cs *ChannelStatus) ExitCode() int {
+	c := cs.GetCondition(ChannelConditionReady)
+	switch {
+	case c == nil:
+		return 2
+	case c.IsTrue():
+		return 0
+	case c.IsFalse():
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ObserveGeneration records that the status reflects the given generation of the spec. The
+// ObservedGeneration only ever moves forward, so that a stale controller can't make the Channel
+// look like it regressed.
+func (cs *ChannelStatus) ObserveGeneration(gen int64) {
+	if gen > cs.ObservedGeneration {
+		cs.ObservedGeneration = gen
+	}
+}
+
+// IsReadyForGeneration returns true if the resource is ready overall and its status reflects the
+// given generation of the spec. If the status reflects an older generation, its conditions are
+// stale and the resource is not considered ready.
+func (cs *ChannelStatus) IsReadyForGeneration(gen int64) bool {
+	return cs.IsReady() && cs.ObservedGeneration == gen
+}
+
+// CompactConditions returns the conditions to display when space is limited. If the Channel is
+// ready, the Provisioned, Sinkable, Subscribable and Compatible conditions it depends on are omitted, since
+// they are all True. Otherwise, all conditions are returned so that the cause is visible.
+// Informational conditions, such as Quarantined, are always returned.
+func (cs *ChannelStatus) CompactConditions() []duckv1alpha1.Condition {
+	if !cs.IsReady() {
+		return append([]duckv1alpha1.Condition(nil), cs.Conditions...)
+	}
+	var compact []duckv1alpha1.Condition
+	for _, c := range cs.Conditions {
+		if !containsConditionType(chanDependentConditionTypes, c.Type) || !c.IsTrue() {
+			compact = append(compact, c)
+		}
+	}
+	return compact
+}
+
+// SortedConditions returns a copy of the conditions sorted by type, e.g. for stable output in
+// golden files. The conditions themselves are left untouched.
+func (cs *ChannelStatus) SortedConditions() []duckv1alpha1.Condition {
+	sorted := append([]duckv1alpha1.Condition(nil), cs.Conditions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Type < sorted[j].Type })
+	return sorted
 }
 
 // InitializeConditions sets relevant unset conditions to Unknown state.
@@ -136,15 +372,237 @@ func (cs *ChannelStatus) InitializeConditions() {
 	chanCondSet.Manage(cs).InitializeConditions()
 }
 
+// ResetConditions sets the Provisioned, Sinkable, Subscribable and Compatible conditions to Unknown state,
+// regardless of their current state. Unlike InitializeConditions, conditions that are already set
+// are overwritten, forcing them to be re-evaluated.
+func (cs *ChannelStatus) ResetConditions() {
+	for _, t := range chanDependentConditionTypes {
+		chanCondSet.Manage(cs).MarkUnknown(t, "Reset", "condition reset for re-evaluation")
+	}
+}
+
+// ClearStaleConditions removes every condition that is neither one of the Channel's own conditions
+// nor one of the given types. Provisioners pass the provisioner-specific condition types that
+// apply to the Channel's current configuration, so that conditions left over from a previous
+// configuration (e.g. DispatcherReady after a switch to a dispatcherless mode) are removed.
+func (cs *ChannelStatus) ClearStaleConditions(applicable ...duckv1alpha1.ConditionType) {
+	var conditions duckv1alpha1.Conditions
+	for _, c := range cs.Conditions {
+		if containsConditionType(chanConditionTypes, c.Type) || containsConditionType(applicable, c.Type) {
+			conditions = append(conditions, c)
+		}
+	}
+	cs.Conditions = conditions
+}
+
+func containsConditionType(types []duckv1alpha1.ConditionType, t duckv1alpha1.ConditionType) bool {
+	for _, ct := range types {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
 // MarkProvisioned sets ChannelConditionProvisioned condition to True state.
 func (cs *ChannelStatus) MarkProvisioned() {
+	cs.InitializeConditions()
 	chanCondSet.Manage(cs).MarkTrue(ChannelConditionProvisioned)
+	cs.recordReady()
+}
+
+// PropagateProvisionerStatus sets ChannelConditionProvisioned from the readiness of the resources
+// backing the Channel, e.g. a Deployment and a Service, so that reconcilers report any backing
+// resource's readiness through a single entry point. When not ready, reason defaults to
+// BackingResourceNotReadyReason.
+func (cs *ChannelStatus) PropagateProvisionerStatus(ready bool, reason, message string) {
+	if ready {
+		cs.MarkProvisioned()
+		return
+	}
+	if reason == "" {
+		reason = BackingResourceNotReadyReason
+	}
+	cs.InitializeConditions()
+	chanCondSet.Manage(cs).MarkFalse(ChannelConditionProvisioned, reason, "%s", message)
+}
+
+// MarkProvisioningFailed marks every condition Ready depends on False with the same reason and
+// message, and clears the Channel's address and self-reference, for when provisioning failed
+// altogether, e.g. because the Provisioner can't be reached.
+func (cs *ChannelStatus) MarkProvisioningFailed(reason, messageFormat string, messageA ...interface{}) {
+	cs.InitializeConditions()
+	cs.Sinkable.DomainInternal = ""
+	cs.DomainExternal = ""
+	cs.Subscribable.Channelable = corev1.ObjectReference{}
+	cs.SubscribableTargets = nil
+	for _, t := range chanDependentConditionTypes {
+		chanCondSet.Manage(cs).MarkFalse(t, reason, messageFormat, messageA...)
+	}
+}
+
+// recordReady sets LastReadyTime if the Channel is Ready.
+func (cs *ChannelStatus) recordReady() {
+	if !cs.IsReady() {
+		return
+	}
+	cs.syncReady()
+	t := cs.GetCondition(ChannelConditionReady).LastTransitionTime
+	cs.LastReadyTime = &t
+}
+
+// syncReady sets ChannelConditionReady to True state if the Channel is ready although not every
+// condition Ready depends on is True, because those that aren't have a severity below
+// ConditionSeverityError.
+func (cs *ChannelStatus) syncReady() {
+	if !cs.IsReady() || cs.GetCondition(ChannelConditionReady).IsTrue() {
+		return
+	}
+	chanCondSet.Manage(cs).SetCondition(duckv1alpha1.Condition{
+		Type:   ChannelConditionReady,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// IsDegraded returns true if the Channel was Ready at some point, but one of the conditions its
+// readiness depends on is now False with ConditionSeverityError severity. Channels that were never
+// Ready are not degraded.
+func (cs *ChannelStatus) IsDegraded() bool {
+	if cs.LastReadyTime == nil {
+		return false
+	}
+	for _, t := range chanDependentConditionTypes {
+		if c := cs.GetCondition(t); c != nil && c.Status == corev1.ConditionFalse && cs.GetConditionSeverity(t) == ConditionSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkCompatible sets ChannelConditionCompatible condition to True state.
+func (cs *ChannelStatus) MarkCompatible() {
+	cs.InitializeConditions()
+	chanCondSet.Manage(cs).MarkTrue(ChannelConditionCompatible)
+	cs.recordReady()
+}
+
+// MarkIncompatible sets ChannelConditionCompatible condition to False state, e.g. when the
+// installed version of the Provisioner no longer accepts the Channel's arguments.
+func (cs *ChannelStatus) MarkIncompatible(reason, message string) {
+	cs.InitializeConditions()
+	chanCondSet.Manage(cs).MarkFalse(ChannelConditionCompatible, reason, "%s", message)
+}
+
+// MarkIngressReady sets the ChannelConditionIngressReady condition to True state, making the Channel
+// Sinkable if it has an address.
+func (cs *ChannelStatus) MarkIngressReady() {
+	chanCondSet.Manage(cs).SetCondition(duckv1alpha1.Condition{
+		Type:   ChannelConditionIngressReady,
+		Status: corev1.ConditionTrue,
+	})
+	if cs.Sinkable.DomainInternal != "" {
+		cs.syncSinkable()
+	}
+}
+
+// MarkIngressNotReady sets the ChannelConditionIngressReady condition to False state, making the
+// Channel not Sinkable until its ingress is ready again.
+func (cs *ChannelStatus) MarkIngressNotReady(reason, message string) {
+	chanCondSet.Manage(cs).SetCondition(duckv1alpha1.Condition{
+		Type:    ChannelConditionIngressReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if cs.Sinkable.DomainInternal != "" {
+		cs.syncSinkable()
+	}
+}
+
+// MarkQuarantined sets the informational ChannelConditionQuarantined condition to True state,
+// without affecting the Channel's readiness.
+func (cs *ChannelStatus) MarkQuarantined(reason string) {
+	chanCondSet.Manage(cs).SetCondition(duckv1alpha1.Condition{
+		Type:    ChannelConditionQuarantined,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: "persistent downstream delivery failures were detected",
+	})
+}
+
+// MarkWarning surfaces a problem, such as a deprecation, that does not affect the Channel's
+// readiness, as the condition conditionType with ConditionSeverityWarning severity. A condition Ready
+// depends on is set to False state, and no longer keeps the Channel from being Ready until it is
+// set again. Any other condition is set to True state; Provisioners must pass its type to
+// ClearStaleConditions to keep it. Calls for Ready itself are ignored.
+func (cs *ChannelStatus) MarkWarning(conditionType duckv1alpha1.ConditionType, reason, message string) {
+	cs.markWithSeverity(conditionType, ConditionSeverityWarning, reason, message)
+}
+
+// MarkInfo is like MarkWarning, for a condition with ConditionSeverityInfo severity.
+func (cs *ChannelStatus) MarkInfo(conditionType duckv1alpha1.ConditionType, reason, message string) {
+	cs.markWithSeverity(conditionType, ConditionSeverityInfo, reason, message)
+}
+
+func (cs *ChannelStatus) markWithSeverity(conditionType duckv1alpha1.ConditionType, severity ConditionSeverity, reason, message string) {
+	if conditionType == ChannelConditionReady {
+		return
+	}
+	status := corev1.ConditionTrue
+	if containsConditionType(chanDependentConditionTypes, conditionType) {
+		status = corev1.ConditionFalse
+	}
+	// SetCondition, unlike MarkFalse, leaves Ready alone.
+	chanCondSet.Manage(cs).SetCondition(duckv1alpha1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	if cs.ConditionSeverities == nil {
+		cs.ConditionSeverities = make(map[duckv1alpha1.ConditionType]ConditionSeverity)
+	}
+	cs.ConditionSeverities[conditionType] = severity
+	cs.recordReady()
+}
+
+// ClearQuarantine removes the ChannelConditionQuarantined condition.
+func (cs *ChannelStatus) ClearQuarantine() {
+	var conditions duckv1alpha1.Conditions
+	for _, c := range cs.Conditions {
+		if c.Type != ChannelConditionQuarantined {
+			conditions = append(conditions, c)
+		}
+	}
+	cs.Conditions = conditions
+}
+
+// MarkPaused sets the informational ChannelConditionPaused condition to True state, without
+// affecting the Channel's readiness.
+func (cs *ChannelStatus) MarkPaused() {
+	chanCondSet.Manage(cs).SetCondition(duckv1alpha1.Condition{
+		Type:    ChannelConditionPaused,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Paused",
+		Message: "delivery to subscribers is paused",
+	})
+}
+
+// MarkUnpaused sets the informational ChannelConditionPaused condition to False state, without
+// affecting the Channel's readiness.
+func (cs *ChannelStatus) MarkUnpaused() {
+	chanCondSet.Manage(cs).SetCondition(duckv1alpha1.Condition{
+		Type:   ChannelConditionPaused,
+		Status: corev1.ConditionFalse,
+	})
 }
 
 // SetSubscribable makes this Channel Subscribable, by having it point at itself. The 'name' and
 // 'namespace' should be the name and namespace of the Channel this ChannelStatus is on. It also
-// sets the ChannelConditionSubscribable to true.
+// sets the ChannelConditionSubscribable to true. It is safe to call on a zero-value ChannelStatus.
 func (cs *ChannelStatus) SetSubscribable(namespace, name string) {
+	cs.InitializeConditions()
+	cs.SubscribableTargets = nil
 	if namespace != "" || name != "" {
 		cs.Subscribable.Channelable = corev1.ObjectReference{
 			Kind:       "Channel",
@@ -153,6 +611,7 @@ func (cs *ChannelStatus) SetSubscribable(namespace, name string) {
 			Name:       name,
 		}
 		chanCondSet.Manage(cs).MarkTrue(ChannelConditionSubscribable)
+		cs.recordReady()
 	} else {
 		cs.Subscribable.Channelable = corev1.ObjectReference{}
 		chanCondSet.Manage(cs).MarkFalse(ChannelConditionSubscribable, "notSubscribable", "not Subscribable")
@@ -160,14 +619,319 @@ func (cs *ChannelStatus) SetSubscribable(namespace, name string) {
 
 }
 
+// SetSubscribableTargets makes this Channel Subscribable through each of refs, for a Channel that
+// fans out to several backing Channelables. Subscribable points at the first of them. If refs is
+// empty, the Channel is not Subscribable.
+func (cs *ChannelStatus) SetSubscribableTargets(refs []corev1.ObjectReference) {
+	if len(refs) == 0 {
+		cs.SetSubscribable("", "")
+		return
+	}
+	cs.InitializeConditions()
+	cs.Subscribable.Channelable = refs[0]
+	cs.SubscribableTargets = append([]corev1.ObjectReference(nil), refs...)
+	chanCondSet.Manage(cs).MarkTrue(ChannelConditionSubscribable)
+	cs.recordReady()
+}
+
+// GetSubscribableReferences returns every Channelable the Channel can be subscribed through: its
+// SubscribableTargets if set, otherwise its Subscribable reference, if any.
+func (cs *ChannelStatus) GetSubscribableReferences() []corev1.ObjectReference {
+	if len(cs.SubscribableTargets) > 0 {
+		return append([]corev1.ObjectReference(nil), cs.SubscribableTargets...)
+	}
+	if isChannelableEmpty(cs.Subscribable.Channelable) {
+		return nil
+	}
+	return []corev1.ObjectReference{cs.Subscribable.Channelable}
+}
+
+// MarkSelfSubscribable makes the Channel Subscribable by pointing its status at the Channel itself,
+// as SetSubscribable does with the Channel's own namespace and name. It returns an error if the
+// Channel has no name yet.
+func (c *Channel) MarkSelfSubscribable() error {
+	if c.Name == "" {
+		return errors.New("the Channel has no name")
+	}
+	c.Status.SetSubscribable(c.Namespace, c.Name)
+	return nil
+}
+
 // SetSinkable makes this Channel sinkable by setting the domainInternal. It also sets the
-// ChannelConditionSinkable to true.
+// ChannelConditionSinkable to true, unless the Channel's ingress is not ready. It is safe to call
+// on a zero-value ChannelStatus.
 func (cs *ChannelStatus) SetSinkable(domainInternal string) {
 	cs.Sinkable.DomainInternal = domainInternal
-	if domainInternal != "" {
-		chanCondSet.Manage(cs).MarkTrue(ChannelConditionSinkable)
-	} else {
+	cs.DomainExternal = ""
+	cs.syncSinkable()
+}
+
+// SetSinkableExternal is SetSinkable for Channels that also accept events from outside the
+// cluster at domainExternal. Whether the Channel is Sinkable depends on domainInternal only.
+func (cs *ChannelStatus) SetSinkableExternal(domainInternal, domainExternal string) {
+	cs.SetSinkable(domainInternal)
+	cs.DomainExternal = domainExternal
+}
+
+// ChannelAddress is where a Channel accepts events.
+type ChannelAddress struct {
+	// Internal is the domain for callers inside the cluster.
+	Internal string
+	// External is the publicly routable domain, if any.
+	External string
+}
+
+// Hostname returns the domain callers inside the cluster should use: the internal one, unless
+// there is none.
+func (a ChannelAddress) Hostname() string {
+	if a.Internal != "" {
+		return a.Internal
+	}
+	return a.External
+}
+
+// Address returns the domains at which the Channel accepts events.
+func (cs *ChannelStatus) Address() ChannelAddress {
+	return ChannelAddress{
+		Internal: cs.Sinkable.DomainInternal,
+		External: cs.DomainExternal,
+	}
+}
+
+// syncSinkable sets ChannelConditionSinkable from the Channel's address and the readiness of its
+// ingress.
+func (cs *ChannelStatus) syncSinkable() {
+	cs.InitializeConditions()
+	if cs.Sinkable.DomainInternal == "" {
 		chanCondSet.Manage(cs).MarkFalse(ChannelConditionSinkable, "emptyDomainInternal", "domainInternal is the empty string")
+		return
+	}
+	if ingress := cs.GetCondition(ChannelConditionIngressReady); ingress != nil && !ingress.IsTrue() {
+		chanCondSet.Manage(cs).MarkFalse(ChannelConditionSinkable, "IngressNotReady", "the Channel's ingress is not accepting traffic")
+		return
+	}
+	chanCondSet.Manage(cs).MarkTrue(ChannelConditionSinkable)
+	cs.recordReady()
+}
+
+// SetMetricsAddress records the address of the Channel's metrics endpoint.
+func (cs *ChannelStatus) SetMetricsAddress(host string) {
+	cs.MetricsAddress = host
+}
+
+// ChannelDeliveryStats is the backlog of events in a Channel, as observed by its Provisioner.
+type ChannelDeliveryStats struct {
+	// OldestUnacknowledgedTime is when the oldest event not yet acknowledged by every subscriber
+	// was received.
+	// +optional
+	OldestUnacknowledgedTime *metav1.Time `json:"oldestUnacknowledgedTime,omitempty"`
+
+	// ApproximateBacklog is the approximate number of events not yet acknowledged by every
+	// subscriber.
+	// +optional
+	ApproximateBacklog *int64 `json:"approximateBacklog,omitempty"`
+}
+
+// SetDeliveryStats records the backlog the Provisioner observed in the Channel.
+func (cs *ChannelStatus) SetDeliveryStats(stats ChannelDeliveryStats) {
+	cs.DeliveryStats = &stats
+}
+
+// SubscriberLag is how far behind a subscriber of a Channel is.
+type SubscriberLag struct {
+	// URI identifies the subscriber.
+	URI string `json:"uri"`
+
+	// Events is the number of events the subscriber has yet to acknowledge.
+	// +optional
+	Events int64 `json:"events,omitempty"`
+
+	// Duration is how long ago the oldest of those events was received, e.g. "1m30s".
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+}
+
+// SetSubscriberLag records the lag the Provisioner observed for the subscriber at uri, replacing
+// any lag previously recorded for it.
+func (cs *ChannelStatus) SetSubscriberLag(uri string, events int64, d time.Duration) {
+	lag := SubscriberLag{URI: uri, Events: events, Duration: metav1.Duration{Duration: d}}
+	i := sort.Search(len(cs.SubscriberLags), func(i int) bool { return cs.SubscriberLags[i].URI >= uri })
+	if i < len(cs.SubscriberLags) && cs.SubscriberLags[i].URI == uri {
+		cs.SubscriberLags[i] = lag
+		return
+	}
+	cs.SubscriberLags = append(cs.SubscriberLags, SubscriberLag{})
+	copy(cs.SubscriberLags[i+1:], cs.SubscriberLags[i:])
+	cs.SubscriberLags[i] = lag
+}
+
+// RemoveSubscriberLag forgets the lag recorded for the subscriber at uri, e.g. once it is no
+// longer subscribed.
+func (cs *ChannelStatus) RemoveSubscriberLag(uri string) {
+	for i, l := range cs.SubscriberLags {
+		if l.URI == uri {
+			cs.SubscriberLags = append(cs.SubscriberLags[:i], cs.SubscriberLags[i+1:]...)
+			break
+		}
+	}
+	if len(cs.SubscriberLags) == 0 {
+		cs.SubscriberLags = nil
+	}
+}
+
+// SetBackend records the concrete backend serving the Channel.
+func (cs *ChannelStatus) SetBackend(backend string) {
+	cs.Backend = backend
+}
+
+// SetEffectiveRetention records the retention the Provisioner applied to the Channel.
+func (cs *ChannelStatus) SetEffectiveRetention(d time.Duration) {
+	cs.EffectiveRetention = &metav1.Duration{Duration: d}
+}
+
+// Merge merges other into this ChannelStatus. Conditions are merged by type, keeping whichever
+// condition has the newer LastTransitionTime. Non-empty Sinkable (with DomainExternal), Subscribable, MetricsAddress,
+// EffectiveRetention, Backend, DeliveryStats and SubscriberLags fields in other replace those in
+// this ChannelStatus.
+// ObservedGeneration and LastReadyTime are the later of the two, and ConditionTransitions the larger
+// count of each condition. ConditionSeverities follow the merged conditions. Ready is then
+// recomputed from the merged conditions it depends on.
+func (cs *ChannelStatus) Merge(other *ChannelStatus) {
+	if other == nil {
+		return
+	}
+	if other.ObservedGeneration > cs.ObservedGeneration {
+		cs.ObservedGeneration = other.ObservedGeneration
+	}
+	if other.Sinkable.DomainInternal != "" {
+		cs.Sinkable = other.Sinkable
+		cs.DomainExternal = other.DomainExternal
+	}
+	if !isChannelableEmpty(other.Subscribable.Channelable) {
+		cs.Subscribable = other.Subscribable
+		cs.SubscribableTargets = append([]corev1.ObjectReference(nil), other.SubscribableTargets...)
+	}
+	if other.MetricsAddress != "" {
+		cs.MetricsAddress = other.MetricsAddress
+	}
+	if other.EffectiveRetention != nil {
+		cs.EffectiveRetention = other.EffectiveRetention.DeepCopy()
+	}
+	if other.Backend != "" {
+		cs.Backend = other.Backend
+	}
+	if other.DeliveryStats != nil {
+		cs.DeliveryStats = other.DeliveryStats.DeepCopy()
+	}
+	if len(other.SubscriberLags) > 0 {
+		cs.SubscriberLags = append([]SubscriberLag(nil), other.SubscriberLags...)
+	}
+	if other.LastReadyTime != nil && (cs.LastReadyTime == nil || cs.LastReadyTime.Inner.Before(&other.LastReadyTime.Inner)) {
+		cs.LastReadyTime = other.LastReadyTime.DeepCopy()
+	}
+
+	for t, n := range other.ConditionTransitions {
+		if n > cs.ConditionTransitions[t] {
+			if cs.ConditionTransitions == nil {
+				cs.ConditionTransitions = make(map[duckv1alpha1.ConditionType]int64)
+			}
+			cs.ConditionTransitions[t] = n
+		}
+	}
+
+	merged := make(map[duckv1alpha1.ConditionType]duckv1alpha1.Condition, len(cs.Conditions)+len(other.Conditions))
+	for _, c := range cs.Conditions {
+		merged[c.Type] = c
+	}
+	for _, c := range other.Conditions {
+		if existing, ok := merged[c.Type]; ok && !existing.LastTransitionTime.Inner.Before(&c.LastTransitionTime.Inner) {
+			continue
+		}
+		merged[c.Type] = c
+		if s, ok := other.ConditionSeverities[c.Type]; ok {
+			if cs.ConditionSeverities == nil {
+				cs.ConditionSeverities = make(map[duckv1alpha1.ConditionType]ConditionSeverity)
+			}
+			cs.ConditionSeverities[c.Type] = s
+		} else {
+			delete(cs.ConditionSeverities, c.Type)
+		}
+	}
+	if len(merged) == 0 {
+		return
+	}
+	conditions := make(duckv1alpha1.Conditions, 0, len(merged))
+	for _, c := range merged {
+		conditions = append(conditions, c)
+	}
+	// Sorted for convenience of the consumer, i.e. kubectl, matching duck's ConditionManager.
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Type < conditions[j].Type })
+	cs.Conditions = conditions
+	cs.recomputeReady()
+}
+
+// recomputeReady sets ChannelConditionReady from the conditions it depends on, by setting each of
+// them again. Nothing is done while Ready is unset and some of those conditions are missing, since
+// Ready can't be known yet.
+func (cs *ChannelStatus) recomputeReady() {
+	mgr := chanCondSet.Manage(cs)
+	if mgr.GetCondition(ChannelConditionReady) == nil {
+		for _, t := range chanDependentConditionTypes {
+			if mgr.GetCondition(t) == nil {
+				return
+			}
+		}
+	}
+	mgr.InitializeConditions()
+	for _, t := range chanDependentConditionTypes {
+		c := mgr.GetCondition(t)
+		switch {
+		case cs.GetConditionSeverity(t) != ConditionSeverityError:
+			// It doesn't affect Ready, which syncReady sets below.
+		case c.IsTrue():
+			mgr.MarkTrue(t)
+		case c.IsFalse():
+			mgr.MarkFalse(t, c.Reason, "%s", c.Message)
+		default:
+			mgr.MarkUnknown(t, c.Reason, "%s", c.Message)
+		}
+	}
+	cs.syncReady()
+}
+
+// ChannelDuckStatus bundles the duck typed portions of a Channel's status, for consumers that
+// resolve Channels generically via duck typing.
+type ChannelDuckStatus struct {
+	// Sinkable is the Channel's Sinkable status.
+	Sinkable duckv1alpha1.Sinkable `json:"sinkable,omitempty"`
+
+	// Subscribable is the Channel's Subscribable status.
+	Subscribable duckv1alpha1.Subscribable `json:"subscribable,omitempty"`
+}
+
+// DuckStatus returns the Sinkable and Subscribable portions of this ChannelStatus.
+func (cs *ChannelStatus) DuckStatus() ChannelDuckStatus {
+	return ChannelDuckStatus{
+		Sinkable:     cs.Sinkable,
+		Subscribable: cs.Subscribable,
+	}
+}
+
+// PrinterColumns returns the values of the Channel's additional printer columns, keyed by column
+// name. It must be kept in sync with the additionalPrinterColumns of the Channel CRD.
+func (c *Channel) PrinterColumns() map[string]string {
+	ready := string(corev1.ConditionUnknown)
+	if cond := c.Status.GetCondition(ChannelConditionReady); cond != nil && cond.Status != "" {
+		ready = string(cond.Status)
+	}
+	provisioner := ""
+	if c.Spec.Provisioner != nil && c.Spec.Provisioner.Ref != nil {
+		provisioner = c.Spec.Provisioner.Ref.Name
+	}
+	return map[string]string{
+		"Ready":       ready,
+		"Address":     c.Status.Sinkable.DomainInternal,
+		"Provisioner": provisioner,
 	}
 }
 
@@ -180,3 +944,108 @@ type ChannelList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []Channel `json:"items"`
 }
+
+// WithinQuota returns true if the number of Channels in the list that are in the given namespace
+// does not exceed max.
+func (l *ChannelList) WithinQuota(namespace string, max int) bool {
+	count := 0
+	for _, c := range l.Items {
+		if c.Namespace == namespace {
+			count++
+		}
+	}
+	return count <= max
+}
+
+// NotReadyChannel is a Channel that is not Ready, with the reason and message of the condition
+// blocking it.
+// +k8s:deepcopy-gen=false
+type NotReadyChannel struct {
+	Channel Channel
+	Reason  string
+	Message string
+}
+
+// NotReady returns every Channel in the list that is not Ready, e.g. to summarize the health of a
+// cluster's Channels.
+func (l *ChannelList) NotReady() []NotReadyChannel {
+	var notReady []NotReadyChannel
+	for _, c := range l.Items {
+		if c.Status.IsReady() {
+			continue
+		}
+		reason, message := c.Status.blockingReason()
+		notReady = append(notReady, NotReadyChannel{Channel: c, Reason: reason, Message: message})
+	}
+	return notReady
+}
+
+// blockingReason returns the reason and message of the condition keeping the Channel from being
+// Ready: the first of the conditions Ready depends on that is False, or failing that, the first
+// that is not True. If there is none, those of the Ready condition itself are returned.
+func (cs *ChannelStatus) blockingReason() (string, string) {
+	var pending *duckv1alpha1.Condition
+	for _, t := range chanDependentConditionTypes {
+		c := cs.GetCondition(t)
+		switch {
+		case c == nil:
+			if pending == nil {
+				pending = &duckv1alpha1.Condition{Type: t}
+			}
+		case cs.GetConditionSeverity(t) != ConditionSeverityError:
+		case c.IsFalse():
+			return c.Reason, c.Message
+		case !c.IsTrue() && pending == nil:
+			pending = c
+		}
+	}
+	if pending == nil {
+		pending = cs.GetCondition(ChannelConditionReady)
+	}
+	if pending == nil {
+		return "", ""
+	}
+	return pending.Reason, pending.Message
+}
+
+// FilterChannelsByProvisioner returns the Channels in the list whose provisioner reference matches
+// ref by name and kind. Channels without a provisioner never match.
+func FilterChannelsByProvisioner(list *ChannelList, ref ProvisionerReference) []Channel {
+	if list == nil || ref.Ref == nil {
+		return nil
+	}
+	var matched []Channel
+	for _, c := range list.Items {
+		p := c.Spec.Provisioner
+		if p == nil || p.Ref == nil {
+			continue
+		}
+		if p.Ref.Name == ref.Ref.Name && p.Ref.Kind == ref.Ref.Kind {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// ChannelBecameReady returns true if the Channel was not Ready in old but is in new, e.g. to filter
+// the update events of a watch down to those of Channels becoming Ready. old may be nil, for a
+// Channel that was just created.
+func ChannelBecameReady(old, new *Channel) bool {
+	if new == nil || !new.Status.IsReady() {
+		return false
+	}
+	return old == nil || !old.Status.IsReady()
+}
+
+// IsOrphaned returns true if the Channel's provisioner can't be found by provisionerExists, or if the
+// Channel has no provisioner at all. Errors from provisionerExists are returned unchanged.
+func (c *Channel) IsOrphaned(provisionerExists func(ProvisionerReference) (bool, error)) (bool, error) {
+	if c.Spec.Provisioner == nil || c.Spec.Provisioner.Ref == nil {
+		return true, nil
+	}
+	exists, err := provisionerExists(*c.Spec.Provisioner)
+	if err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
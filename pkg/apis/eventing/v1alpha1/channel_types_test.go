@@ -17,12 +17,18 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var condReady = duckv1alpha1.Condition{
@@ -94,6 +100,9 @@ func TestChannelInitializeConditions(t *testing.T) {
 		cs:   &ChannelStatus{},
 		want: &ChannelStatus{
 			Conditions: []duckv1alpha1.Condition{{
+				Type:   ChannelConditionCompatible,
+				Status: corev1.ConditionUnknown,
+			}, {
 				Type:   ChannelConditionProvisioned,
 				Status: corev1.ConditionUnknown,
 			}, {
@@ -117,6 +126,9 @@ func TestChannelInitializeConditions(t *testing.T) {
 		},
 		want: &ChannelStatus{
 			Conditions: []duckv1alpha1.Condition{{
+				Type:   ChannelConditionCompatible,
+				Status: corev1.ConditionUnknown,
+			}, {
 				Type:   ChannelConditionProvisioned,
 				Status: corev1.ConditionFalse,
 			}, {
@@ -140,6 +152,9 @@ func TestChannelInitializeConditions(t *testing.T) {
 		},
 		want: &ChannelStatus{
 			Conditions: []duckv1alpha1.Condition{{
+				Type:   ChannelConditionCompatible,
+				Status: corev1.ConditionUnknown,
+			}, {
 				Type:   ChannelConditionProvisioned,
 				Status: corev1.ConditionTrue,
 			}, {
@@ -191,6 +206,7 @@ func TestChannelIsReady(t *testing.T) {
 			cs := &ChannelStatus{}
 			if test.markProvisioned {
 				cs.MarkProvisioned()
+				cs.MarkCompatible()
 			}
 			if test.setSubscribable {
 				cs.SetSubscribable("foo", "bar")
@@ -206,6 +222,97 @@ func TestChannelIsReady(t *testing.T) {
 	}
 }
 
+func TestChannelStatus_ExitCode(t *testing.T) {
+	testCases := map[string]struct {
+		status func() *ChannelStatus
+		want   int
+	}{
+		"no conditions": {
+			status: func() *ChannelStatus { return &ChannelStatus{} },
+			want:   2,
+		},
+		"reconciling": {
+			status: func() *ChannelStatus {
+				cs := &ChannelStatus{}
+				cs.InitializeConditions()
+				cs.MarkProvisioned()
+				return cs
+			},
+			want: 2,
+		},
+		"failed": {
+			status: func() *ChannelStatus {
+				cs := &ChannelStatus{}
+				cs.MarkProvisioned()
+				cs.SetSinkable("")
+				return cs
+			},
+			want: 1,
+		},
+		"ready": {
+			status: func() *ChannelStatus {
+				cs := &ChannelStatus{}
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSubscribable("foo", "bar")
+				cs.SetSinkable("foo.bar")
+				return cs
+			},
+			want: 0,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.status().ExitCode(); got != tc.want {
+				t.Errorf("unexpected exit code: want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestChannelIsReadyForGeneration(t *testing.T) {
+	tests := []struct {
+		name               string
+		ready              bool
+		observedGeneration int64
+		generation         int64
+		want               bool
+	}{{
+		name:               "generation matches, ready",
+		ready:              true,
+		observedGeneration: 2,
+		generation:         2,
+		want:               true,
+	}, {
+		name:               "generation matches, not ready",
+		ready:              false,
+		observedGeneration: 2,
+		generation:         2,
+		want:               false,
+	}, {
+		name:               "generation lags, ready",
+		ready:              true,
+		observedGeneration: 1,
+		generation:         2,
+		want:               false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cs := &ChannelStatus{ObservedGeneration: test.observedGeneration}
+			cs.InitializeConditions()
+			if test.ready {
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSubscribable("foo", "bar")
+				cs.SetSinkable("foo.bar")
+			}
+			if got := cs.IsReadyForGeneration(test.generation); test.want != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
 func TestChannelStatus_SetSubscribable(t *testing.T) {
 	testCases := map[string]struct {
 		namespace string
@@ -215,12 +322,22 @@ func TestChannelStatus_SetSubscribable(t *testing.T) {
 		"empty namespace and name": {
 			want: &ChannelStatus{
 				Conditions: []duckv1alpha1.Condition{
-					// Note that Ready is here because when the condition is marked False, duck
-					// automatically sets Ready to false.
+					{
+						Type:   ChannelConditionCompatible,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionProvisioned,
+						Status: corev1.ConditionUnknown,
+					},
 					{
 						Type:   ChannelConditionReady,
 						Status: corev1.ConditionFalse,
 					},
+					{
+						Type:   ChannelConditionSinkable,
+						Status: corev1.ConditionUnknown,
+					},
 					{
 						Type:   ChannelConditionSubscribable,
 						Status: corev1.ConditionFalse,
@@ -239,6 +356,22 @@ func TestChannelStatus_SetSubscribable(t *testing.T) {
 					},
 				},
 				Conditions: []duckv1alpha1.Condition{
+					{
+						Type:   ChannelConditionCompatible,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionProvisioned,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionReady,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionSinkable,
+						Status: corev1.ConditionUnknown,
+					},
 					{
 						Type:   ChannelConditionSubscribable,
 						Status: corev1.ConditionTrue,
@@ -259,6 +392,22 @@ func TestChannelStatus_SetSubscribable(t *testing.T) {
 					},
 				},
 				Conditions: []duckv1alpha1.Condition{
+					{
+						Type:   ChannelConditionCompatible,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionProvisioned,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionReady,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionSinkable,
+						Status: corev1.ConditionUnknown,
+					},
 					{
 						Type:   ChannelConditionSubscribable,
 						Status: corev1.ConditionTrue,
@@ -278,6 +427,87 @@ func TestChannelStatus_SetSubscribable(t *testing.T) {
 	}
 }
 
+func TestChannelStatus_SetSubscribableTargets(t *testing.T) {
+	target := func(name string) corev1.ObjectReference {
+		return corev1.ObjectReference{
+			APIVersion: "messaging.example.dev/v1alpha1",
+			Kind:       "KafkaTopic",
+			Namespace:  "test-namespace",
+			Name:       name,
+		}
+	}
+	targets := []corev1.ObjectReference{target("east"), target("west")}
+
+	cs := &ChannelStatus{}
+	cs.SetSubscribableTargets(targets)
+	if diff := cmp.Diff(targets, cs.GetSubscribableReferences()); diff != "" {
+		t.Errorf("unexpected references (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(target("east"), cs.Subscribable.Channelable); diff != "" {
+		t.Errorf("unexpected Subscribable reference (-want, +got) = %v", diff)
+	}
+	if c := cs.GetCondition(ChannelConditionSubscribable); c == nil || !c.IsTrue() {
+		t.Errorf("expected the Channel to be Subscribable, got %v", c)
+	}
+
+	cs.SetSubscribable("test-namespace", "test-name")
+	want := []corev1.ObjectReference{{
+		APIVersion: SchemeGroupVersion.String(),
+		Kind:       "Channel",
+		Namespace:  "test-namespace",
+		Name:       "test-name",
+	}}
+	if diff := cmp.Diff(want, cs.GetSubscribableReferences()); diff != "" {
+		t.Errorf("unexpected references after SetSubscribable (-want, +got) = %v", diff)
+	}
+
+	cs.SetSubscribableTargets(nil)
+	if got := cs.GetSubscribableReferences(); got != nil {
+		t.Errorf("expected no references, got %v", got)
+	}
+	if c := cs.GetCondition(ChannelConditionSubscribable); c == nil || !c.IsFalse() {
+		t.Errorf("expected the Channel not to be Subscribable, got %v", c)
+	}
+}
+
+func TestChannel_MarkSelfSubscribable(t *testing.T) {
+	testCases := map[string]struct {
+		meta    metav1.ObjectMeta
+		want    corev1.ObjectReference
+		wantErr bool
+	}{
+		"named": {
+			meta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-name"},
+			want: corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Namespace:  "test-namespace",
+				Name:       "test-name",
+			},
+		},
+		"unnamed": {
+			meta:    metav1.ObjectMeta{Namespace: "test-namespace", GenerateName: "test-"},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{ObjectMeta: tc.meta}
+			err := c.MarkSelfSubscribable()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("unexpected error. want error %v, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, c.Status.Subscribable.Channelable); diff != "" {
+				t.Errorf("unexpected self-reference (-want, +got) = %v", diff)
+			}
+			cond := c.Status.GetCondition(ChannelConditionSubscribable)
+			if subscribable := cond != nil && cond.IsTrue(); subscribable == tc.wantErr {
+				t.Errorf("unexpected Subscribable condition %v", cond)
+			}
+		})
+	}
+}
+
 func TestChannelStatus_SetSinkable(t *testing.T) {
 	testCases := map[string]struct {
 		domainInternal string
@@ -286,8 +516,14 @@ func TestChannelStatus_SetSinkable(t *testing.T) {
 		"empty string": {
 			want: &ChannelStatus{
 				Conditions: []duckv1alpha1.Condition{
-					// Note that Ready is here because when the condition is marked False, duck
-					// automatically sets Ready to false.
+					{
+						Type:   ChannelConditionCompatible,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionProvisioned,
+						Status: corev1.ConditionUnknown,
+					},
 					{
 						Type:   ChannelConditionReady,
 						Status: corev1.ConditionFalse,
@@ -296,6 +532,10 @@ func TestChannelStatus_SetSinkable(t *testing.T) {
 						Type:   ChannelConditionSinkable,
 						Status: corev1.ConditionFalse,
 					},
+					{
+						Type:   ChannelConditionSubscribable,
+						Status: corev1.ConditionUnknown,
+					},
 				},
 			},
 		},
@@ -306,10 +546,26 @@ func TestChannelStatus_SetSinkable(t *testing.T) {
 					DomainInternal: "test-domain",
 				},
 				Conditions: []duckv1alpha1.Condition{
+					{
+						Type:   ChannelConditionCompatible,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionProvisioned,
+						Status: corev1.ConditionUnknown,
+					},
+					{
+						Type:   ChannelConditionReady,
+						Status: corev1.ConditionUnknown,
+					},
 					{
 						Type:   ChannelConditionSinkable,
 						Status: corev1.ConditionTrue,
 					},
+					{
+						Type:   ChannelConditionSubscribable,
+						Status: corev1.ConditionUnknown,
+					},
 				},
 			},
 		},
@@ -324,3 +580,1488 @@ func TestChannelStatus_SetSinkable(t *testing.T) {
 		})
 	}
 }
+
+func TestChannelStatus_SetSinkableExternal(t *testing.T) {
+	testCases := map[string]struct {
+		domainInternal string
+		domainExternal string
+		wantHostname   string
+	}{
+		"internal only": {
+			domainInternal: "test-channel.test-namespace.svc.cluster.local",
+			wantHostname:   "test-channel.test-namespace.svc.cluster.local",
+		},
+		"internal and external": {
+			domainInternal: "test-channel.test-namespace.svc.cluster.local",
+			domainExternal: "test-channel.example.com",
+			wantHostname:   "test-channel.test-namespace.svc.cluster.local",
+		},
+		"external only": {
+			domainExternal: "test-channel.example.com",
+			wantHostname:   "test-channel.example.com",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{}
+			cs.InitializeConditions()
+			cs.SetSinkableExternal(tc.domainInternal, tc.domainExternal)
+
+			wantSinkable := tc.domainInternal != ""
+			if got := cs.GetCondition(ChannelConditionSinkable).IsTrue(); got != wantSinkable {
+				t.Errorf("expected Sinkable to be %v, got %v", wantSinkable, got)
+			}
+			want := ChannelAddress{Internal: tc.domainInternal, External: tc.domainExternal}
+			if diff := cmp.Diff(want, cs.Address()); diff != "" {
+				t.Errorf("unexpected address (-want, +got) = %v", diff)
+			}
+			if got := cs.Address().Hostname(); got != tc.wantHostname {
+				t.Errorf("expected hostname %q, got %q", tc.wantHostname, got)
+			}
+
+			b, err := json.Marshal(cs)
+			if err != nil {
+				t.Fatalf("Unexpected error marshaling the status: %v", err)
+			}
+			got := map[string]interface{}{}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("Unexpected error unmarshaling the status: %v", err)
+			}
+			if external, ok := got["domainExternal"]; tc.domainExternal == "" && ok {
+				t.Errorf("expected an unset external domain to be omitted, got %s", b)
+			} else if tc.domainExternal != "" && external != tc.domainExternal {
+				t.Errorf("expected domainExternal %q, got %s", tc.domainExternal, b)
+			}
+		})
+	}
+
+	cs := &ChannelStatus{}
+	cs.SetSinkableExternal("test-channel.test-namespace.svc.cluster.local", "test-channel.example.com")
+	cs.SetSinkable("other.test-namespace.svc.cluster.local")
+	if cs.DomainExternal != "" {
+		t.Errorf("expected SetSinkable to clear the external domain, got %q", cs.DomainExternal)
+	}
+}
+
+func TestChannelStatus_ZeroValueBecomesReady(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.SetSinkable("test-domain")
+	cs.SetSubscribable("test-namespace", "test-name")
+	cs.MarkCompatible()
+	if cs.IsReady() {
+		t.Errorf("expected a Channel that is not provisioned not to be ready")
+	}
+	cs.MarkProvisioned()
+	if !cs.IsReady() {
+		t.Errorf("expected the Channel to be ready, got conditions %+v", cs.Conditions)
+	}
+}
+
+func TestChannelStatus_DuckStatus(t *testing.T) {
+	testCases := map[string]struct {
+		cs   *ChannelStatus
+		want ChannelDuckStatus
+	}{
+		"empty": {
+			cs:   &ChannelStatus{},
+			want: ChannelDuckStatus{},
+		},
+		"sinkable and subscribable": {
+			cs: &ChannelStatus{
+				Sinkable: duckv1alpha1.Sinkable{
+					DomainInternal: "test-domain",
+				},
+				Subscribable: duckv1alpha1.Subscribable{
+					Channelable: corev1.ObjectReference{
+						APIVersion: SchemeGroupVersion.String(),
+						Kind:       "Channel",
+						Namespace:  "test-namespace",
+						Name:       "test-name",
+					},
+				},
+				Conditions: []duckv1alpha1.Condition{
+					condReady,
+				},
+			},
+			want: ChannelDuckStatus{
+				Sinkable: duckv1alpha1.Sinkable{
+					DomainInternal: "test-domain",
+				},
+				Subscribable: duckv1alpha1.Subscribable{
+					Channelable: corev1.ObjectReference{
+						APIVersion: SchemeGroupVersion.String(),
+						Kind:       "Channel",
+						Namespace:  "test-namespace",
+						Name:       "test-name",
+					},
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.cs.DuckStatus()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected duck status (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_ResetConditions(t *testing.T) {
+	testCases := map[string]struct {
+		markProvisioned bool
+		setSinkable     bool
+		setSubscribable bool
+		transitions     map[duckv1alpha1.ConditionType]int64
+	}{
+		"empty": {},
+		"all true": {
+			markProvisioned: true,
+			setSinkable:     true,
+			setSubscribable: true,
+			transitions: map[duckv1alpha1.ConditionType]int64{
+				ChannelConditionCompatible:   1,
+				ChannelConditionProvisioned:  1,
+				ChannelConditionReady:        1,
+				ChannelConditionSinkable:     1,
+				ChannelConditionSubscribable: 1,
+			},
+		},
+		"one true": {
+			markProvisioned: true,
+			transitions: map[duckv1alpha1.ConditionType]int64{
+				ChannelConditionCompatible:  1,
+				ChannelConditionProvisioned: 1,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{}
+			if tc.markProvisioned {
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+			}
+			if tc.setSinkable {
+				cs.SetSinkable("foo.bar")
+			}
+			if tc.setSubscribable {
+				cs.SetSubscribable("foo", "bar")
+			}
+			cs.ResetConditions()
+			want := &ChannelStatus{
+				Sinkable:     cs.Sinkable,
+				Subscribable: cs.Subscribable,
+				// Resetting the conditions doesn't forget that the Channel was Ready.
+				LastReadyTime:        cs.LastReadyTime,
+				ConditionTransitions: tc.transitions,
+				Conditions: []duckv1alpha1.Condition{{
+					Type:   ChannelConditionCompatible,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   ChannelConditionProvisioned,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   ChannelConditionReady,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   ChannelConditionSinkable,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   ChannelConditionSubscribable,
+					Status: corev1.ConditionUnknown,
+				}},
+			}
+			if diff := cmp.Diff(want, cs, ignoreTransitionTimeMessageAndReason); diff != "" {
+				t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+			}
+			if cs.IsReady() {
+				t.Errorf("unexpected readiness after reset: want false, got true")
+			}
+		})
+	}
+}
+
+func TestChannelStatus_Merge(t *testing.T) {
+	older := apis.VolatileTime{Inner: metav1.NewTime(time.Unix(100, 0))}
+	newer := apis.VolatileTime{Inner: metav1.NewTime(time.Unix(200, 0))}
+	subscribable := duckv1alpha1.Subscribable{
+		Channelable: corev1.ObjectReference{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "Channel",
+			Namespace:  "test-namespace",
+			Name:       "test-name",
+		},
+	}
+	testCases := map[string]struct {
+		cs    *ChannelStatus
+		other *ChannelStatus
+		want  *ChannelStatus
+	}{
+		"nil other": {
+			cs: &ChannelStatus{
+				Sinkable: duckv1alpha1.Sinkable{DomainInternal: "test-domain"},
+			},
+			want: &ChannelStatus{
+				Sinkable: duckv1alpha1.Sinkable{DomainInternal: "test-domain"},
+			},
+		},
+		"disjoint conditions": {
+			cs: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: older,
+				}},
+			},
+			other: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSubscribable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: older,
+				}},
+			},
+			want: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: older,
+				}, {
+					Type:               ChannelConditionSubscribable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: older,
+				}},
+			},
+		},
+		"overlapping conditions, other newer": {
+			cs: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionUnknown,
+					LastTransitionTime: older,
+				}},
+			},
+			other: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: newer,
+				}},
+			},
+			want: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: newer,
+				}},
+			},
+		},
+		"overlapping conditions, other older": {
+			cs: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: newer,
+				}},
+			},
+			other: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionUnknown,
+					LastTransitionTime: older,
+				}},
+			},
+			want: &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{{
+					Type:               ChannelConditionSinkable,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: newer,
+				}},
+			},
+		},
+		"other has addresses": {
+			cs: &ChannelStatus{},
+			other: &ChannelStatus{
+				Sinkable:     duckv1alpha1.Sinkable{DomainInternal: "test-domain"},
+				Subscribable: subscribable,
+			},
+			want: &ChannelStatus{
+				Sinkable:     duckv1alpha1.Sinkable{DomainInternal: "test-domain"},
+				Subscribable: subscribable,
+			},
+		},
+		"other became ready later": {
+			cs:    &ChannelStatus{LastReadyTime: &older},
+			other: &ChannelStatus{LastReadyTime: &newer},
+			want:  &ChannelStatus{LastReadyTime: &newer},
+		},
+		"other became ready earlier": {
+			cs:    &ChannelStatus{LastReadyTime: &newer},
+			other: &ChannelStatus{LastReadyTime: &older},
+			want:  &ChannelStatus{LastReadyTime: &newer},
+		},
+		"condition transitions": {
+			cs: &ChannelStatus{ConditionTransitions: map[duckv1alpha1.ConditionType]int64{
+				ChannelConditionReady:       3,
+				ChannelConditionProvisioned: 1,
+			}},
+			other: &ChannelStatus{ConditionTransitions: map[duckv1alpha1.ConditionType]int64{
+				ChannelConditionReady:    2,
+				ChannelConditionSinkable: 4,
+			}},
+			want: &ChannelStatus{ConditionTransitions: map[duckv1alpha1.ConditionType]int64{
+				ChannelConditionReady:       3,
+				ChannelConditionProvisioned: 1,
+				ChannelConditionSinkable:    4,
+			}},
+		},
+		"other has empty addresses": {
+			cs: &ChannelStatus{
+				Sinkable:     duckv1alpha1.Sinkable{DomainInternal: "test-domain"},
+				Subscribable: subscribable,
+			},
+			other: &ChannelStatus{},
+			want: &ChannelStatus{
+				Sinkable:     duckv1alpha1.Sinkable{DomainInternal: "test-domain"},
+				Subscribable: subscribable,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			tc.cs.Merge(tc.other)
+			if diff := cmp.Diff(tc.want, tc.cs); diff != "" {
+				t.Errorf("unexpected status (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_MergeRecomputesReady(t *testing.T) {
+	older := apis.VolatileTime{Inner: metav1.NewTime(time.Unix(100, 0))}
+	newer := apis.VolatileTime{Inner: metav1.NewTime(time.Unix(200, 0))}
+	condition := func(ct duckv1alpha1.ConditionType, status corev1.ConditionStatus, ltt apis.VolatileTime) duckv1alpha1.Condition {
+		return duckv1alpha1.Condition{Type: ct, Status: status, LastTransitionTime: ltt}
+	}
+	testCases := map[string]struct {
+		cs        duckv1alpha1.Conditions
+		other     duckv1alpha1.Conditions
+		wantReady corev1.ConditionStatus
+	}{
+		"every dependent becomes True": {
+			cs: duckv1alpha1.Conditions{
+				condition(ChannelConditionReady, corev1.ConditionUnknown, older),
+				condition(ChannelConditionProvisioned, corev1.ConditionTrue, older),
+				condition(ChannelConditionSinkable, corev1.ConditionTrue, older),
+				condition(ChannelConditionSubscribable, corev1.ConditionUnknown, older),
+				condition(ChannelConditionCompatible, corev1.ConditionUnknown, older),
+			},
+			other: duckv1alpha1.Conditions{
+				condition(ChannelConditionSubscribable, corev1.ConditionTrue, newer),
+				condition(ChannelConditionCompatible, corev1.ConditionTrue, newer),
+			},
+			wantReady: corev1.ConditionTrue,
+		},
+		"Ready set without its dependents": {
+			cs: duckv1alpha1.Conditions{
+				condition(ChannelConditionProvisioned, corev1.ConditionTrue, older),
+				condition(ChannelConditionSinkable, corev1.ConditionTrue, older),
+			},
+			other: duckv1alpha1.Conditions{
+				condition(ChannelConditionReady, corev1.ConditionFalse, newer),
+				condition(ChannelConditionSubscribable, corev1.ConditionTrue, newer),
+				condition(ChannelConditionCompatible, corev1.ConditionTrue, newer),
+			},
+			wantReady: corev1.ConditionTrue,
+		},
+		"a dependent becomes False": {
+			cs: duckv1alpha1.Conditions{
+				condition(ChannelConditionReady, corev1.ConditionTrue, older),
+				condition(ChannelConditionProvisioned, corev1.ConditionTrue, older),
+				condition(ChannelConditionSinkable, corev1.ConditionTrue, older),
+				condition(ChannelConditionSubscribable, corev1.ConditionTrue, older),
+				condition(ChannelConditionCompatible, corev1.ConditionTrue, older),
+			},
+			other: duckv1alpha1.Conditions{
+				condition(ChannelConditionProvisioned, corev1.ConditionFalse, newer),
+			},
+			wantReady: corev1.ConditionFalse,
+		},
+		"a dependent becomes Unknown": {
+			cs: duckv1alpha1.Conditions{
+				condition(ChannelConditionReady, corev1.ConditionTrue, older),
+				condition(ChannelConditionProvisioned, corev1.ConditionTrue, older),
+				condition(ChannelConditionSinkable, corev1.ConditionTrue, older),
+				condition(ChannelConditionSubscribable, corev1.ConditionTrue, older),
+				condition(ChannelConditionCompatible, corev1.ConditionTrue, older),
+			},
+			other: duckv1alpha1.Conditions{
+				condition(ChannelConditionSinkable, corev1.ConditionUnknown, newer),
+			},
+			wantReady: corev1.ConditionUnknown,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{Conditions: tc.cs}
+			cs.Merge(&ChannelStatus{Conditions: tc.other})
+			got := cs.GetCondition(ChannelConditionReady)
+			if got == nil || got.Status != tc.wantReady {
+				t.Errorf("expected the Ready condition to be %v, got %v", tc.wantReady, got)
+			}
+			if got, want := cs.IsReady(), tc.wantReady == corev1.ConditionTrue; got != want {
+				t.Errorf("unexpected IsReady. Expected %v, actually %v", want, got)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_ClearStaleConditions(t *testing.T) {
+	dispatcherReady := duckv1alpha1.Condition{
+		Type:   "DispatcherReady",
+		Status: corev1.ConditionTrue,
+	}
+	testCases := map[string]struct {
+		applicable []duckv1alpha1.ConditionType
+		want       duckv1alpha1.Conditions
+	}{
+		"mode no longer uses the condition": {
+			want: duckv1alpha1.Conditions{condReady, condUnprovisioned},
+		},
+		"mode still uses the condition": {
+			applicable: []duckv1alpha1.ConditionType{"DispatcherReady"},
+			want:       duckv1alpha1.Conditions{dispatcherReady, condReady, condUnprovisioned},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{
+				Conditions: duckv1alpha1.Conditions{dispatcherReady, condReady, condUnprovisioned},
+			}
+			cs.ClearStaleConditions(tc.applicable...)
+			if diff := cmp.Diff(tc.want, cs.Conditions); diff != "" {
+				t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_Quarantine(t *testing.T) {
+	testCases := map[string]struct {
+		ready bool
+	}{
+		"ready": {
+			ready: true,
+		},
+		"not ready": {
+			ready: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{}
+			cs.InitializeConditions()
+			if tc.ready {
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSinkable("foo.bar")
+				cs.SetSubscribable("foo", "bar")
+			}
+
+			cs.MarkQuarantined("DeliveryFailures")
+			want := &duckv1alpha1.Condition{
+				Type:    ChannelConditionQuarantined,
+				Status:  corev1.ConditionTrue,
+				Reason:  "DeliveryFailures",
+				Message: "persistent downstream delivery failures were detected",
+			}
+			got := cs.GetCondition(ChannelConditionQuarantined)
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("unexpected condition (-want, +got) = %v", diff)
+			}
+			if cs.IsReady() != tc.ready {
+				t.Errorf("unexpected readiness after quarantine: want %v, got %v", tc.ready, cs.IsReady())
+			}
+
+			cs.ClearQuarantine()
+			if got := cs.GetCondition(ChannelConditionQuarantined); got != nil {
+				t.Errorf("unexpected condition after clearing quarantine: %v", got)
+			}
+			if cs.IsReady() != tc.ready {
+				t.Errorf("unexpected readiness after clearing quarantine: want %v, got %v", tc.ready, cs.IsReady())
+			}
+		})
+	}
+}
+
+func TestChannelStatus_MarkWarning(t *testing.T) {
+	ready := func() *ChannelStatus {
+		cs := &ChannelStatus{}
+		cs.InitializeConditions()
+		cs.MarkProvisioned()
+		cs.MarkCompatible()
+		cs.SetSinkable("foo.bar")
+		cs.SetSubscribable("foo", "bar")
+		return cs
+	}
+
+	cs := ready()
+	cs.MarkWarning("DeprecatedProvisioner", "Deprecated", "the provisioner will be removed")
+	want := &duckv1alpha1.Condition{
+		Type:    "DeprecatedProvisioner",
+		Status:  corev1.ConditionTrue,
+		Reason:  "Deprecated",
+		Message: "the provisioner will be removed",
+	}
+	got := cs.GetCondition("DeprecatedProvisioner")
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("unexpected warning condition (-want, +got) = %v", diff)
+	}
+	if !cs.IsReady() {
+		t.Errorf("expected a warning not to affect readiness")
+	}
+
+	if s := cs.GetConditionSeverity("DeprecatedProvisioner"); s != ConditionSeverityWarning {
+		t.Errorf("unexpected severity: want %v, got %v", ConditionSeverityWarning, s)
+	}
+
+	// A warning on a condition Ready depends on shows in the status, but doesn't affect readiness.
+	cs = ready()
+	cs.MarkWarning(ChannelConditionCompatible, "SoftIncompatible", "the argument foo is deprecated")
+	cs.MarkWarning(ChannelConditionReady, "Deprecated", "the provisioner will be removed")
+	want = &duckv1alpha1.Condition{
+		Type:    ChannelConditionCompatible,
+		Status:  corev1.ConditionFalse,
+		Reason:  "SoftIncompatible",
+		Message: "the argument foo is deprecated",
+	}
+	got = cs.GetCondition(ChannelConditionCompatible)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("unexpected warning condition (-want, +got) = %v", diff)
+	}
+	if !cs.IsReady() || !cs.GetCondition(ChannelConditionReady).IsTrue() {
+		t.Errorf("expected a warning not to affect readiness, got %v", cs.GetCondition(ChannelConditionReady))
+	}
+	if cs.IsDegraded() {
+		t.Errorf("expected a warning not to degrade the Channel")
+	}
+
+	// Other conditions Ready depends on still block readiness.
+	cs.PropagateProvisionerStatus(false, "Failed", "the provisioner failed")
+	if cs.IsReady() {
+		t.Errorf("expected a condition Ready depends on to block readiness")
+	}
+	cs.PropagateProvisionerStatus(true, "", "")
+	if !cs.IsReady() || !cs.GetCondition(ChannelConditionReady).IsTrue() {
+		t.Errorf("expected a warning not to affect readiness once the others recovered, got %v", cs.GetCondition(ChannelConditionReady))
+	}
+
+	// Setting the condition again reverts it to Error severity.
+	cs = ready()
+	cs.MarkWarning(ChannelConditionCompatible, "SoftIncompatible", "the argument foo is deprecated")
+	cs.MarkIncompatible("Incompatible", "the Channel is not compatible with the provisioner")
+	if s := cs.GetConditionSeverity(ChannelConditionCompatible); s != ConditionSeverityError {
+		t.Errorf("unexpected severity: want %v, got %v", ConditionSeverityError, s)
+	}
+	if cs.IsReady() {
+		t.Errorf("expected an Error condition to block readiness")
+	}
+}
+
+func TestChannelStatus_MarkInfo(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkProvisioned()
+	cs.MarkCompatible()
+	cs.SetSinkable("foo.bar")
+	cs.SetSubscribable("foo", "bar")
+
+	cs.MarkInfo(ChannelConditionProvisioned, "Migrating", "the Channel is being migrated")
+	if s := cs.GetConditionSeverity(ChannelConditionProvisioned); s != ConditionSeverityInfo {
+		t.Errorf("unexpected severity: want %v, got %v", ConditionSeverityInfo, s)
+	}
+	if !cs.IsReady() {
+		t.Errorf("expected an Info condition not to affect readiness")
+	}
+}
+
+func TestChannelStatus_MergeSeverities(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkProvisioned()
+	cs.MarkCompatible()
+	cs.SetSinkable("foo.bar")
+	cs.SetSubscribable("foo", "bar")
+
+	other := cs.DeepCopy()
+	other.MarkWarning(ChannelConditionCompatible, "SoftIncompatible", "the argument foo is deprecated")
+	for i := range other.Conditions {
+		other.Conditions[i].LastTransitionTime = apis.VolatileTime{Inner: metav1.NewTime(time.Now().Add(time.Hour))}
+	}
+
+	cs.Merge(other)
+	if s := cs.GetConditionSeverity(ChannelConditionCompatible); s != ConditionSeverityWarning {
+		t.Errorf("unexpected severity: want %v, got %v", ConditionSeverityWarning, s)
+	}
+	if !cs.IsReady() || !cs.GetCondition(ChannelConditionReady).IsTrue() {
+		t.Errorf("expected a merged warning not to affect readiness, got %v", cs.GetCondition(ChannelConditionReady))
+	}
+}
+
+func TestChannelStatus_ObserveGeneration(t *testing.T) {
+	cs := &ChannelStatus{}
+	for _, s := range []struct {
+		gen  int64
+		want int64
+	}{
+		{gen: 1, want: 1},
+		{gen: 3, want: 3},
+		// A stale controller can't move the observed generation back.
+		{gen: 2, want: 3},
+		{gen: 3, want: 3},
+		{gen: 4, want: 4},
+	} {
+		cs.ObserveGeneration(s.gen)
+		if cs.ObservedGeneration != s.want {
+			t.Errorf("after observing %d: unexpected observed generation: want %d, got %d", s.gen, s.want, cs.ObservedGeneration)
+		}
+	}
+}
+
+func TestCountConditionTransitions(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.InitializeConditions()
+	if got := CountConditionTransitions(cs, ChannelConditionProvisioned); got != 0 {
+		t.Errorf("unexpected transitions after initialization: want 0, got %d", got)
+	}
+	steps := []struct {
+		mark func()
+		want int
+	}{
+		// Leaving Unknown state is not a transition.
+		{mark: cs.MarkProvisioned, want: 0},
+		{mark: cs.MarkProvisioned, want: 0},
+		{mark: func() { cs.PropagateProvisionerStatus(false, "", "down") }, want: 1},
+		// Only the message changes.
+		{mark: func() { cs.PropagateProvisionerStatus(false, "", "still down") }, want: 1},
+		{mark: cs.MarkProvisioned, want: 2},
+		{mark: func() { cs.PropagateProvisionerStatus(false, "", "down") }, want: 3},
+		{mark: cs.ResetConditions, want: 4},
+		{mark: cs.MarkProvisioned, want: 4},
+	}
+	for i, s := range steps {
+		s.mark()
+		if got := CountConditionTransitions(cs, ChannelConditionProvisioned); got != s.want {
+			t.Errorf("step %d: unexpected transitions: want %d, got %d", i, s.want, got)
+		}
+	}
+	if got := CountConditionTransitions(cs, ChannelConditionSinkable); got != 0 {
+		t.Errorf("unexpected transitions of an untouched condition: want 0, got %d", got)
+	}
+}
+
+func TestChannelStatus_IsDegraded(t *testing.T) {
+	testCases := map[string]struct {
+		mark func(cs *ChannelStatus)
+		want bool
+	}{
+		"never ready": {
+			mark: func(cs *ChannelStatus) {
+				cs.MarkProvisioned()
+				cs.SetSinkable("")
+			},
+			want: false,
+		},
+		"ready": {
+			mark: func(cs *ChannelStatus) {
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSinkable("foo.bar")
+				cs.SetSubscribable("foo", "bar")
+			},
+			want: false,
+		},
+		"became ready, then failed": {
+			mark: func(cs *ChannelStatus) {
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSinkable("foo.bar")
+				cs.SetSubscribable("foo", "bar")
+				cs.SetSinkable("")
+			},
+			want: true,
+		},
+		"became ready, failed, then recovered": {
+			mark: func(cs *ChannelStatus) {
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSinkable("foo.bar")
+				cs.SetSubscribable("foo", "bar")
+				cs.SetSinkable("")
+				cs.SetSinkable("foo.bar")
+			},
+			want: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{}
+			tc.mark(cs)
+			if got := cs.IsDegraded(); got != tc.want {
+				t.Errorf("unexpected IsDegraded: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_PropagateProvisionerStatus(t *testing.T) {
+	testCases := map[string]struct {
+		ready   bool
+		reason  string
+		message string
+		want    *duckv1alpha1.Condition
+	}{
+		"ready": {
+			ready: true,
+			want: &duckv1alpha1.Condition{
+				Type:   ChannelConditionProvisioned,
+				Status: corev1.ConditionTrue,
+			},
+		},
+		"not ready, with reason": {
+			reason:  "DeploymentUnavailable",
+			message: "0 of 1 replicas are available",
+			want: &duckv1alpha1.Condition{
+				Type:    ChannelConditionProvisioned,
+				Status:  corev1.ConditionFalse,
+				Reason:  "DeploymentUnavailable",
+				Message: "0 of 1 replicas are available",
+			},
+		},
+		"not ready, empty reason": {
+			message: "the Service has no endpoints",
+			want: &duckv1alpha1.Condition{
+				Type:    ChannelConditionProvisioned,
+				Status:  corev1.ConditionFalse,
+				Reason:  BackingResourceNotReadyReason,
+				Message: "the Service has no endpoints",
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{}
+			cs.PropagateProvisionerStatus(tc.ready, tc.reason, tc.message)
+			got := cs.GetCondition(ChannelConditionProvisioned)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("unexpected condition (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_MarkProvisioningFailed(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkCompatible()
+	cs.MarkProvisioned()
+	cs.SetSinkable("foo.bar")
+	cs.SetSubscribable("foo", "bar")
+
+	cs.MarkProvisioningFailed("ProvisionerUnreachable", "the provisioner %q can't be reached", "kafka")
+	for _, ct := range []duckv1alpha1.ConditionType{ChannelConditionProvisioned, ChannelConditionSinkable, ChannelConditionSubscribable, ChannelConditionCompatible} {
+		want := &duckv1alpha1.Condition{
+			Type:    ct,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ProvisionerUnreachable",
+			Message: `the provisioner "kafka" can't be reached`,
+		}
+		got := cs.GetCondition(ct)
+		if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+			t.Errorf("unexpected %s condition (-want, +got) = %v", ct, diff)
+		}
+	}
+	if cs.Sinkable.DomainInternal != "" {
+		t.Errorf("expected the domain to be cleared, got %q", cs.Sinkable.DomainInternal)
+	}
+	if !isChannelableEmpty(cs.Subscribable.Channelable) {
+		t.Errorf("expected the self-reference to be cleared, got %v", cs.Subscribable.Channelable)
+	}
+	if cs.IsReady() {
+		t.Errorf("expected the Channel not to be ready")
+	}
+}
+
+func TestChannelStatus_Compatible(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.InitializeConditions()
+	if got := cs.GetCondition(ChannelConditionCompatible); got == nil || !got.IsUnknown() {
+		t.Errorf("expected InitializeConditions to set the Compatible condition to Unknown, got %v", got)
+	}
+
+	cs.MarkProvisioned()
+	cs.SetSinkable("foo.bar")
+	cs.SetSubscribable("foo", "bar")
+	cs.MarkCompatible()
+	if !cs.GetCondition(ChannelConditionCompatible).IsTrue() {
+		t.Errorf("expected the Compatible condition to be True, got %v", cs.GetCondition(ChannelConditionCompatible))
+	}
+	if !cs.IsReady() {
+		t.Errorf("expected a compatible Channel to be ready")
+	}
+
+	cs.MarkIncompatible("ArgumentsRejected", "unknown argument \"retention\"")
+	want := &duckv1alpha1.Condition{
+		Type:    ChannelConditionCompatible,
+		Status:  corev1.ConditionFalse,
+		Reason:  "ArgumentsRejected",
+		Message: "unknown argument \"retention\"",
+	}
+	got := cs.GetCondition(ChannelConditionCompatible)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("unexpected condition (-want, +got) = %v", diff)
+	}
+	if cs.IsReady() {
+		t.Errorf("expected an incompatible Channel not to be ready")
+	}
+	if !cs.GetCondition(ChannelConditionProvisioned).IsTrue() {
+		t.Errorf("expected the other conditions to be unaffected, got %v", cs.GetCondition(ChannelConditionProvisioned))
+	}
+}
+
+func TestChannelStatus_IngressReady(t *testing.T) {
+	testCases := map[string]struct {
+		mark          func(cs *ChannelStatus)
+		wantSinkable  corev1.ConditionStatus
+		wantReady     bool
+		wantCondition *duckv1alpha1.Condition
+	}{
+		"ingress not reported": {
+			mark: func(cs *ChannelStatus) {
+				cs.SetSinkable("foo.bar")
+			},
+			wantSinkable: corev1.ConditionTrue,
+		},
+		"ingress not ready": {
+			mark: func(cs *ChannelStatus) {
+				cs.MarkIngressNotReady("Starting", "the ingress is starting")
+				cs.SetSinkable("foo.bar")
+				cs.MarkProvisioned()
+			},
+			wantSinkable: corev1.ConditionFalse,
+			wantCondition: &duckv1alpha1.Condition{
+				Type:    ChannelConditionIngressReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Starting",
+				Message: "the ingress is starting",
+			},
+		},
+		"ingress ready, storage not": {
+			mark: func(cs *ChannelStatus) {
+				cs.SetSinkable("foo.bar")
+				cs.MarkIngressNotReady("Starting", "the ingress is starting")
+				cs.MarkIngressReady()
+			},
+			wantSinkable: corev1.ConditionTrue,
+			wantCondition: &duckv1alpha1.Condition{
+				Type:   ChannelConditionIngressReady,
+				Status: corev1.ConditionTrue,
+			},
+		},
+		"ingress and storage ready": {
+			mark: func(cs *ChannelStatus) {
+				cs.MarkIngressReady()
+				cs.SetSinkable("foo.bar")
+				cs.MarkProvisioned()
+			},
+			wantSinkable: corev1.ConditionTrue,
+			wantReady:    true,
+			wantCondition: &duckv1alpha1.Condition{
+				Type:   ChannelConditionIngressReady,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs := &ChannelStatus{}
+			cs.MarkCompatible()
+			cs.SetSubscribable("foo", "bar")
+			tc.mark(cs)
+			if got := cs.GetCondition(ChannelConditionSinkable).Status; got != tc.wantSinkable {
+				t.Errorf("unexpected Sinkable status: want %v, got %v", tc.wantSinkable, got)
+			}
+			if got := cs.IsReady(); got != tc.wantReady {
+				t.Errorf("unexpected readiness: want %v, got %v", tc.wantReady, got)
+			}
+			got := cs.GetCondition(ChannelConditionIngressReady)
+			if diff := cmp.Diff(tc.wantCondition, got, cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("unexpected condition (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_Paused(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.MarkProvisioned()
+	cs.MarkCompatible()
+	cs.SetSinkable("foo.bar")
+	cs.SetSubscribable("foo", "bar")
+	if got := cs.GetCondition(ChannelConditionPaused); got != nil {
+		t.Errorf("unexpected Paused condition before pausing: %v", got)
+	}
+
+	cs.MarkPaused()
+	if !cs.GetCondition(ChannelConditionPaused).IsTrue() {
+		t.Errorf("expected the Paused condition to be True, got %v", cs.GetCondition(ChannelConditionPaused))
+	}
+	if !cs.IsReady() {
+		t.Errorf("expected a paused Channel to still be ready")
+	}
+
+	cs.MarkUnpaused()
+	if !cs.GetCondition(ChannelConditionPaused).IsFalse() {
+		t.Errorf("expected the Paused condition to be False, got %v", cs.GetCondition(ChannelConditionPaused))
+	}
+	if !cs.IsReady() {
+		t.Errorf("expected an unpaused Channel to be ready")
+	}
+}
+
+func TestChannelList_WithinQuota(t *testing.T) {
+	l := &ChannelList{
+		Items: []Channel{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "c1"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "c2"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "other-namespace", Name: "c3"}},
+		},
+	}
+	testCases := map[string]struct {
+		max  int
+		want bool
+	}{
+		"under": {
+			max:  3,
+			want: true,
+		},
+		"at": {
+			max:  2,
+			want: true,
+		},
+		"over": {
+			max:  1,
+			want: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := l.WithinQuota("test-namespace", tc.max); got != tc.want {
+				t.Errorf("unexpected quota result: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestChannelList_NotReady(t *testing.T) {
+	channel := func(name string, mark func(*ChannelStatus)) Channel {
+		c := Channel{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		c.Status.InitializeConditions()
+		c.Status.MarkCompatible()
+		c.Status.MarkProvisioned()
+		c.Status.SetSinkable("foo.bar")
+		c.Status.SetSubscribable("foo", name)
+		mark(&c.Status)
+		return c
+	}
+	ready := channel("ready", func(*ChannelStatus) {})
+	unprovisioned := channel("unprovisioned", func(cs *ChannelStatus) {
+		cs.PropagateProvisionerStatus(false, "DeploymentUnavailable", "0 of 1 replicas are available")
+	})
+	incompatible := channel("incompatible", func(cs *ChannelStatus) {
+		cs.MarkIncompatible("UnsupportedArguments", "the provisioner doesn't support partitioning")
+	})
+	reconciling := Channel{ObjectMeta: metav1.ObjectMeta{Name: "reconciling"}}
+	reconciling.Status.InitializeConditions()
+	reconciling.Status.MarkCompatible()
+	reconciling.Status.ResetConditions()
+	reconciling.Status.MarkIncompatible("UnsupportedArguments", "the provisioner doesn't support partitioning")
+
+	l := &ChannelList{Items: []Channel{ready, unprovisioned, incompatible, reconciling, {ObjectMeta: metav1.ObjectMeta{Name: "new"}}}}
+	got := l.NotReady()
+	var names, reasons, messages []string
+	for _, c := range got {
+		names = append(names, c.Channel.Name)
+		reasons = append(reasons, c.Reason)
+		messages = append(messages, c.Message)
+	}
+	if diff := cmp.Diff([]string{"unprovisioned", "incompatible", "reconciling", "new"}, names); diff != "" {
+		t.Errorf("unexpected not ready Channels (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff([]string{"DeploymentUnavailable", "UnsupportedArguments", "UnsupportedArguments", ""}, reasons); diff != "" {
+		t.Errorf("unexpected reasons (-want, +got) = %v", diff)
+	}
+	wantMessages := []string{"0 of 1 replicas are available", "the provisioner doesn't support partitioning", "the provisioner doesn't support partitioning", ""}
+	if diff := cmp.Diff(wantMessages, messages); diff != "" {
+		t.Errorf("unexpected messages (-want, +got) = %v", diff)
+	}
+}
+
+func TestFilterChannelsByProvisioner(t *testing.T) {
+	provisioned := func(name, kind, provisioner string) Channel {
+		c := Channel{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: name}}
+		if provisioner != "" {
+			c.Spec.Provisioner = &ProvisionerReference{
+				Ref: &corev1.ObjectReference{Name: provisioner, Kind: kind},
+			}
+		}
+		return c
+	}
+	l := &ChannelList{
+		Items: []Channel{
+			provisioned("c1", "ClusterProvisioner", "in-memory-channel"),
+			provisioned("c2", "ClusterProvisioner", "kafka"),
+			provisioned("c3", "", ""),
+			provisioned("c4", "ClusterProvisioner", "in-memory-channel"),
+			provisioned("c5", "Provisioner", "in-memory-channel"),
+		},
+	}
+	testCases := map[string]struct {
+		list *ChannelList
+		ref  ProvisionerReference
+		want []string
+	}{
+		"matching": {
+			list: l,
+			ref: ProvisionerReference{
+				Ref: &corev1.ObjectReference{Name: "in-memory-channel", Kind: "ClusterProvisioner"},
+			},
+			want: []string{"c1", "c4"},
+		},
+		"no match": {
+			list: l,
+			ref: ProvisionerReference{
+				Ref: &corev1.ObjectReference{Name: "gcp-pubsub", Kind: "ClusterProvisioner"},
+			},
+		},
+		"nil provisioner": {
+			list: l,
+			ref:  ProvisionerReference{},
+		},
+		"nil list": {
+			ref: ProvisionerReference{
+				Ref: &corev1.ObjectReference{Name: "in-memory-channel", Kind: "ClusterProvisioner"},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var got []string
+			for _, c := range FilterChannelsByProvisioner(tc.list, tc.ref) {
+				got = append(got, c.Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected Channels (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelBecameReady(t *testing.T) {
+	ready := &Channel{}
+	ready.Status.InitializeConditions()
+	ready.Status.MarkCompatible()
+	ready.Status.MarkProvisioned()
+	ready.Status.SetSinkable("foo.bar")
+	ready.Status.SetSubscribable("foo", "bar")
+	unknown := &Channel{}
+	unknown.Status.InitializeConditions()
+	notReady := ready.DeepCopy()
+	notReady.Status.MarkIncompatible("Incompatible", "testing")
+
+	testCases := map[string]struct {
+		old  *Channel
+		new  *Channel
+		want bool
+	}{
+		"unknown to ready": {
+			old:  unknown,
+			new:  ready,
+			want: true,
+		},
+		"not ready to ready": {
+			old:  notReady,
+			new:  ready,
+			want: true,
+		},
+		"created ready": {
+			new:  ready,
+			want: true,
+		},
+		"ready to not ready": {
+			old: ready,
+			new: notReady,
+		},
+		"still ready": {
+			old: ready,
+			new: ready.DeepCopy(),
+		},
+		"still not ready": {
+			old: unknown,
+			new: notReady,
+		},
+		"deleted": {
+			old: ready,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := ChannelBecameReady(tc.old, tc.new); got != tc.want {
+				t.Errorf("unexpected result. want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestChannel_IsOrphaned(t *testing.T) {
+	lookupErr := errors.New("lookup failed")
+	provisioner := &ProvisionerReference{
+		Ref: &corev1.ObjectReference{Name: "in-memory-channel", Kind: "ClusterProvisioner"},
+	}
+	testCases := map[string]struct {
+		provisioner *ProvisionerReference
+		exists      bool
+		err         error
+		want        bool
+		wantErr     error
+	}{
+		"existing provisioner": {
+			provisioner: provisioner,
+			exists:      true,
+			want:        false,
+		},
+		"missing provisioner": {
+			provisioner: provisioner,
+			exists:      false,
+			want:        true,
+		},
+		"nil provisioner": {
+			want: true,
+		},
+		"lookup error": {
+			provisioner: provisioner,
+			err:         lookupErr,
+			wantErr:     lookupErr,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{Spec: ChannelSpec{Provisioner: tc.provisioner}}
+			got, err := c.IsOrphaned(func(ref ProvisionerReference) (bool, error) {
+				if ref.Ref.Name != provisioner.Ref.Name {
+					t.Errorf("unexpected provisioner looked up: %v", ref.Ref.Name)
+				}
+				return tc.exists, tc.err
+			})
+			if err != tc.wantErr {
+				t.Errorf("unexpected error: want %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected orphaned result: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestChannel_PrinterColumns(t *testing.T) {
+	provisioner := &ProvisionerReference{
+		Ref: &corev1.ObjectReference{
+			Name: "in-memory-channel",
+		},
+	}
+	testCases := map[string]struct {
+		c    *Channel
+		want map[string]string
+	}{
+		"ready": {
+			c: &Channel{
+				Spec: ChannelSpec{Provisioner: provisioner},
+				Status: ChannelStatus{
+					Sinkable:   duckv1alpha1.Sinkable{DomainInternal: "c.default.svc.cluster.local"},
+					Conditions: []duckv1alpha1.Condition{condReady},
+				},
+			},
+			want: map[string]string{
+				"Ready":       "True",
+				"Address":     "c.default.svc.cluster.local",
+				"Provisioner": "in-memory-channel",
+			},
+		},
+		"not ready": {
+			c: &Channel{
+				Spec: ChannelSpec{Provisioner: provisioner},
+				Status: ChannelStatus{
+					Conditions: []duckv1alpha1.Condition{{
+						Type:   ChannelConditionReady,
+						Status: corev1.ConditionFalse,
+					}},
+				},
+			},
+			want: map[string]string{
+				"Ready":       "False",
+				"Address":     "",
+				"Provisioner": "in-memory-channel",
+			},
+		},
+		"no conditions": {
+			c: &Channel{},
+			want: map[string]string{
+				"Ready":       "Unknown",
+				"Address":     "",
+				"Provisioner": "",
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.c.PrinterColumns()); diff != "" {
+				t.Errorf("unexpected printer columns (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelStatus_SetMetricsAddress(t *testing.T) {
+	cs := &ChannelStatus{}
+	if cs.MetricsAddress != "" {
+		t.Errorf("expected an empty metrics address by default, got %q", cs.MetricsAddress)
+	}
+	cs.SetMetricsAddress("kafka-channel-dispatcher.knative-eventing:9090")
+	if want := "kafka-channel-dispatcher.knative-eventing:9090"; cs.MetricsAddress != want {
+		t.Errorf("unexpected metrics address: want %q, got %q", want, cs.MetricsAddress)
+	}
+}
+
+func TestChannelStatus_SetEffectiveRetention(t *testing.T) {
+	cs := &ChannelStatus{}
+	b, err := json.Marshal(cs)
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling the status: %v", err)
+	}
+	if strings.Contains(string(b), "effectiveRetention") {
+		t.Errorf("expected an unset effective retention to be omitted, got %s", b)
+	}
+
+	cs.InitializeConditions()
+	cs.MarkProvisioned()
+	cs.MarkCompatible()
+	cs.SetSinkable("foo.bar")
+	cs.SetSubscribable("foo", "bar")
+	cs.SetEffectiveRetention(7 * 24 * time.Hour)
+	if want := 7 * 24 * time.Hour; cs.EffectiveRetention == nil || cs.EffectiveRetention.Duration != want {
+		t.Errorf("unexpected effective retention: want %v, got %v", want, cs.EffectiveRetention)
+	}
+	if !cs.IsReady() {
+		t.Errorf("expected the effective retention not to affect readiness")
+	}
+}
+
+func TestChannelStatus_SetBackend(t *testing.T) {
+	cs := &ChannelStatus{}
+	b, err := json.Marshal(cs)
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling the status: %v", err)
+	}
+	if strings.Contains(string(b), "backend") {
+		t.Errorf("expected an unset backend to be omitted, got %s", b)
+	}
+
+	cs.InitializeConditions()
+	before := append(duckv1alpha1.Conditions(nil), cs.Conditions...)
+	cs.SetBackend("kafka-east.kafka.svc.cluster.local:9092")
+	if want := "kafka-east.kafka.svc.cluster.local:9092"; cs.Backend != want {
+		t.Errorf("unexpected backend: want %q, got %q", want, cs.Backend)
+	}
+	if diff := cmp.Diff(before, cs.Conditions); diff != "" {
+		t.Errorf("expected the backend not to affect conditions (-want, +got) = %v", diff)
+	}
+
+	b, err = json.Marshal(cs)
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling the status: %v", err)
+	}
+	if !strings.Contains(string(b), `"backend":"kafka-east.kafka.svc.cluster.local:9092"`) {
+		t.Errorf("expected the backend to be serialized, got %s", b)
+	}
+}
+
+func TestChannelStatus_SetDeliveryStats(t *testing.T) {
+	cs := &ChannelStatus{}
+	b, err := json.Marshal(cs)
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling the status: %v", err)
+	}
+	if strings.Contains(string(b), "deliveryStats") {
+		t.Errorf("expected unset delivery stats to be omitted, got %s", b)
+	}
+
+	cs.InitializeConditions()
+	cs.MarkProvisioned()
+	cs.MarkCompatible()
+	cs.SetSinkable("foo.bar")
+	cs.SetSubscribable("foo", "bar")
+	oldest := metav1.NewTime(time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC))
+	backlog := int64(42)
+	cs.SetDeliveryStats(ChannelDeliveryStats{
+		OldestUnacknowledgedTime: &oldest,
+		ApproximateBacklog:       &backlog,
+	})
+	want := &ChannelDeliveryStats{
+		OldestUnacknowledgedTime: &oldest,
+		ApproximateBacklog:       &backlog,
+	}
+	if diff := cmp.Diff(want, cs.DeliveryStats); diff != "" {
+		t.Errorf("unexpected delivery stats (-want, +got) = %v", diff)
+	}
+	if !cs.IsReady() {
+		t.Errorf("expected the delivery stats not to affect readiness")
+	}
+
+	b, err = json.Marshal(&ChannelStatus{DeliveryStats: &ChannelDeliveryStats{}})
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling the status: %v", err)
+	}
+	if want := `{"sinkable":{},"subscribable":{"channelable":{}},"deliveryStats":{}}`; string(b) != want {
+		t.Errorf("expected unset delivery stats fields to be omitted: want %s, got %s", want, b)
+	}
+}
+
+func TestChannelStatus_SubscriberLags(t *testing.T) {
+	cs := &ChannelStatus{}
+	cs.SetSubscriberLag("http://b.default.svc.cluster.local/", 10, 30*time.Second)
+	cs.SetSubscriberLag("http://a.default.svc.cluster.local/", 3, time.Second)
+	want := []SubscriberLag{
+		{URI: "http://a.default.svc.cluster.local/", Events: 3, Duration: metav1.Duration{Duration: time.Second}},
+		{URI: "http://b.default.svc.cluster.local/", Events: 10, Duration: metav1.Duration{Duration: 30 * time.Second}},
+	}
+	if diff := cmp.Diff(want, cs.SubscriberLags); diff != "" {
+		t.Errorf("unexpected lags after setting (-want, +got) = %v", diff)
+	}
+
+	cs.SetSubscriberLag("http://b.default.svc.cluster.local/", 0, 0)
+	want[1] = SubscriberLag{URI: "http://b.default.svc.cluster.local/"}
+	if diff := cmp.Diff(want, cs.SubscriberLags); diff != "" {
+		t.Errorf("unexpected lags after updating (-want, +got) = %v", diff)
+	}
+
+	cs.RemoveSubscriberLag("http://a.default.svc.cluster.local/")
+	cs.RemoveSubscriberLag("http://unknown.default.svc.cluster.local/")
+	want = want[1:]
+	if diff := cmp.Diff(want, cs.SubscriberLags); diff != "" {
+		t.Errorf("unexpected lags after removing (-want, +got) = %v", diff)
+	}
+
+	cs.RemoveSubscriberLag("http://b.default.svc.cluster.local/")
+	if cs.SubscriberLags != nil {
+		t.Errorf("expected no lags after removing all of them, got %v", cs.SubscriberLags)
+	}
+	b, err := json.Marshal(cs)
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling the status: %v", err)
+	}
+	if strings.Contains(string(b), "subscriberLags") {
+		t.Errorf("expected unset subscriber lags to be omitted, got %s", b)
+	}
+}
+
+func TestChannelStatus_SortedConditions(t *testing.T) {
+	scrambled := duckv1alpha1.Conditions{
+		{Type: ChannelConditionSubscribable, Status: corev1.ConditionTrue},
+		{Type: "DispatcherReady", Status: corev1.ConditionTrue},
+		{Type: ChannelConditionReady, Status: corev1.ConditionTrue},
+		{Type: ChannelConditionCompatible, Status: corev1.ConditionTrue},
+		{Type: ChannelConditionSinkable, Status: corev1.ConditionTrue},
+		{Type: ChannelConditionProvisioned, Status: corev1.ConditionTrue},
+	}
+	cs := &ChannelStatus{Conditions: append(duckv1alpha1.Conditions(nil), scrambled...)}
+
+	var got []duckv1alpha1.ConditionType
+	for _, c := range cs.SortedConditions() {
+		got = append(got, c.Type)
+	}
+	want := []duckv1alpha1.ConditionType{
+		ChannelConditionCompatible,
+		"DispatcherReady",
+		ChannelConditionProvisioned,
+		ChannelConditionReady,
+		ChannelConditionSinkable,
+		ChannelConditionSubscribable,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected order (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(scrambled, cs.Conditions); diff != "" {
+		t.Errorf("expected the conditions to be untouched (-want, +got) = %v", diff)
+	}
+	if (&ChannelStatus{}).SortedConditions() != nil {
+		t.Errorf("expected no conditions for an empty status")
+	}
+}
+
+func TestChannelStatus_CompactConditions(t *testing.T) {
+	testCases := map[string]struct {
+		status func() *ChannelStatus
+		want   []duckv1alpha1.ConditionType
+	}{
+		"all ready": {
+			status: func() *ChannelStatus {
+				cs := &ChannelStatus{}
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSinkable("foo.bar")
+				cs.SetSubscribable("foo", "bar")
+				return cs
+			},
+			want: []duckv1alpha1.ConditionType{ChannelConditionReady},
+		},
+		"ready and quarantined": {
+			status: func() *ChannelStatus {
+				cs := &ChannelStatus{}
+				cs.MarkProvisioned()
+				cs.MarkCompatible()
+				cs.SetSinkable("foo.bar")
+				cs.SetSubscribable("foo", "bar")
+				cs.MarkQuarantined("DeliveryFailures")
+				return cs
+			},
+			want: []duckv1alpha1.ConditionType{ChannelConditionQuarantined, ChannelConditionReady},
+		},
+		"partially ready": {
+			status: func() *ChannelStatus {
+				cs := &ChannelStatus{}
+				cs.MarkProvisioned()
+				cs.SetSinkable("")
+				return cs
+			},
+			want: []duckv1alpha1.ConditionType{
+				ChannelConditionCompatible,
+				ChannelConditionProvisioned,
+				ChannelConditionReady,
+				ChannelConditionSinkable,
+				ChannelConditionSubscribable,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var got []duckv1alpha1.ConditionType
+			for _, c := range tc.status().CompactConditions() {
+				got = append(got, c.Type)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ToUnstructured converts the Channel, including its status, to an Unstructured object for
+// dynamic clients. The apiVersion and kind are set even if the Channel's TypeMeta is empty.
+func (c *Channel) ToUnstructured() (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetGroupVersionKind(SchemeGroupVersion.WithKind("Channel"))
+	return u, nil
+}
+
+// FromUnstructured converts an Unstructured object, such as one returned by a dynamic client, to a
+// Channel, including its status. It returns an error if the object has a group, version or kind
+// other than this package's Channel's.
+func FromUnstructured(u *unstructured.Unstructured) (*Channel, error) {
+	if gvk, want := u.GroupVersionKind(), SchemeGroupVersion.WithKind("Channel"); !gvk.Empty() && gvk != want {
+		return nil, fmt.Errorf("the object is a %s, not a %s", gvk.String(), want.String())
+	}
+	c := &Channel{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
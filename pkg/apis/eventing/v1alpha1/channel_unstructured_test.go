@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestChannel_UnstructuredRoundTrip(t *testing.T) {
+	c := &Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-channel",
+		},
+		Spec: ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					APIVersion: "eventing.knative.dev/v1alpha1",
+					Kind:       "ClusterProvisioner",
+					Name:       "kafka",
+				},
+			},
+			Arguments: &runtime.RawExtension{Raw: []byte(`{"partitionKey":"id"}`)},
+		},
+	}
+	c.Status.InitializeConditions()
+	c.Status.MarkCompatible()
+	c.Status.MarkProvisioned()
+	c.Status.SetSinkable("test-channel.test-namespace.svc.cluster.local")
+	c.Status.SetSubscribable("test-namespace", "test-channel")
+	c.Status.MarkQuarantined("DeliveryFailures")
+	// Serialized times have a resolution of a second.
+	for i := range c.Status.Conditions {
+		c.Status.Conditions[i].LastTransitionTime = apis.VolatileTime{Inner: metav1.Unix(1538000000, 0)}
+	}
+	c.Status.LastReadyTime = &apis.VolatileTime{Inner: metav1.Unix(1538000000, 0)}
+
+	u, err := c.ToUnstructured()
+	if err != nil {
+		t.Fatalf("Unexpected error converting to unstructured: %v", err)
+	}
+	if got, want := u.GetAPIVersion(), "eventing.knative.dev/v1alpha1"; got != want {
+		t.Errorf("Unexpected apiVersion. Expected %q, got %q", want, got)
+	}
+	if got, want := u.GetKind(), "Channel"; got != want {
+		t.Errorf("Unexpected kind. Expected %q, got %q", want, got)
+	}
+
+	got, err := FromUnstructured(u)
+	if err != nil {
+		t.Fatalf("Unexpected error converting from unstructured: %v", err)
+	}
+	want := c.DeepCopy()
+	want.TypeMeta = metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1alpha1", Kind: "Channel"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected Channel after round trip (-want, +got) = %v", diff)
+	}
+	if !got.Status.IsReady() {
+		t.Errorf("Expected the round-tripped Channel to be ready")
+	}
+	if got, want := string(got.Spec.Arguments.Raw), `{"partitionKey":"id"}`; got != want {
+		t.Errorf("Unexpected arguments. Expected %s, got %s", want, got)
+	}
+}
+
+func TestFromUnstructured_GroupVersionKind(t *testing.T) {
+	testCases := map[string]struct {
+		apiVersion string
+		kind       string
+		wantErr    bool
+	}{
+		"channel": {
+			apiVersion: "eventing.knative.dev/v1alpha1",
+			kind:       "Channel",
+		},
+		"no type meta": {},
+		"other kind": {
+			apiVersion: "eventing.knative.dev/v1alpha1",
+			kind:       "Subscription",
+			wantErr:    true,
+		},
+		"other group": {
+			apiVersion: "channels.knative.dev/v1alpha1",
+			kind:       "Channel",
+			wantErr:    true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "test-channel"},
+				"spec": map[string]interface{}{
+					"arguments": map[string]interface{}{"partitionKey": "id"},
+				},
+			}}
+			if tc.apiVersion != "" {
+				u.SetAPIVersion(tc.apiVersion)
+				u.SetKind(tc.kind)
+			}
+			c, err := FromUnstructured(u)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected error %v, got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := string(c.Spec.Arguments.Raw), `{"partitionKey":"id"}`; got != want {
+				t.Errorf("Unexpected arguments. Expected %s, got %s", want, got)
+			}
+		})
+	}
+}
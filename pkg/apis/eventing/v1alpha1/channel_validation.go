@@ -18,20 +18,233 @@ package v1alpha1
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
-	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// ReservedMetadataPrefix is the prefix of the label and annotation keys Provisioners use for their
+// own bookkeeping on Channels. Only ReservedMetadataWriters may add, change or remove them.
+var ReservedMetadataPrefix = "internal.eventing.knative.dev/"
+
+// ReservedMetadataWriters are the users allowed to change the keys under ReservedMetadataPrefix: the
+// service accounts of the controller and of the Provisioners' controllers.
+var ReservedMetadataWriters = sets.NewString(
+	"system:serviceaccount:knative-eventing:eventing-controller",
+	"system:serviceaccount:knative-eventing:in-memory-channel-controller",
+	"system:serviceaccount:knative-eventing:kafka-channel-controller",
+)
+
+// ProvisionersOwningChannelable are the names of the Provisioners that manage their Channels'
+// subscribers themselves. Channels they provision may not also set spec.channelable.
+var ProvisionersOwningChannelable = sets.NewString()
+
+// MaxSubscribers is the maximum number of subscribers a Channel's spec.channelable may list, to
+// protect Provisioners from a runaway fan-out. Zero means unlimited.
+var MaxSubscribers = 1000
+
+// MaxArgumentsSize is the maximum size in bytes of a Channel's spec.arguments, as serialized JSON,
+// so that Provisioners don't parse huge blobs on every reconcile.
+var MaxArgumentsSize = 64 * 1024
+
+// RequiredLabels are the label keys every Channel must have, e.g. for cost attribution. It is
+// empty unless cluster policy requires some.
+var RequiredLabels []string
+
+// RestrictProvisionerNamespaces enables rejecting Channels that reference a Provisioner in another
+// namespace, unless that namespace is one of AllowedProvisionerNamespaces.
+var RestrictProvisionerNamespaces = false
+
+// AllowedProvisionerNamespaces are the namespaces whose Provisioners Channels in any namespace may
+// reference, when RestrictProvisionerNamespaces is enabled.
+var AllowedProvisionerNamespaces []string
+
+// now returns the current time. It is a variable for tests.
+var now = time.Now
+
+// MaxClockSkew is how far in the future a condition's lastTransitionTime may be, since the clocks of
+// the controllers setting it and of the webhook may differ.
+var MaxClockSkew = time.Minute
+
+// ValidateChannel runs every check the admission webhook runs on a new Channel, so that Channels can
+// be validated offline, e.g. in CI, without a webhook. It is ValidateCreate and Validate together,
+// so it returns the same errors as Validate for Channels that also pass the checks of creation
+// only, and more for those that don't, e.g. Channels missing RequiredLabels.
+func ValidateChannel(c *Channel) *apis.FieldError {
+	return c.ValidateCreate().Also(c.Validate())
+}
+
+// ValidateCreate runs the checks only new Channels must pass, which the webhook runs on creation
+// only so that existing Channels can still be updated and deleted after cluster policy changed, or
+// after their subscribers' leases expired. Templates in the arguments must not reference metadata
+// the API server only assigns on creation, since SetDefaults can't render them. Channels owned by
+// another resource, such as a Broker's, are exempt from RequiredLabels, since they are accounted to
+// their owner.
+func (c *Channel) ValidateCreate() *apis.FieldError {
+	errs := c.Spec.validateLeaseExpiry(now()).ViaField("spec").
+		Also(c.validateArgumentsTemplatesRendered().ViaField("spec"))
+	if metav1.GetControllerOf(c) != nil {
+		return errs
+	}
+	return errs.Also(c.ValidateRequiredLabels(RequiredLabels))
+}
+
+// Validate implements apis.Validatable. The webhook calls it on both creation and update, see
+// ValidateCreate for the checks of creation only.
 func (c *Channel) Validate() *apis.FieldError {
-	return c.Spec.Validate().ViaField("spec")
+	errs := c.validateDomainName()
+	if RestrictProvisionerNamespaces {
+		errs = errs.Also(c.ValidateProvisionerNamespace(AllowedProvisionerNamespaces))
+	}
+	return errs.Also(c.Spec.Validate().ViaField("spec")).
+		Also(c.Status.Validate().ViaField("status")).
+		Also(c.validateObservedGeneration())
+}
+
+// validateObservedGeneration returns an error if the status claims to reflect a generation of the
+// spec that doesn't exist yet, which only an inconsistent controller can cause.
+func (c *Channel) validateObservedGeneration() *apis.FieldError {
+	if c.Status.ObservedGeneration <= c.Spec.Generation {
+		return nil
+	}
+	return &apis.FieldError{
+		Message: fmt.Sprintf("Internal consistency error: observed generation %d is ahead of the spec", c.Status.ObservedGeneration),
+		Paths:   []string{"status.observedGeneration"},
+		Details: fmt.Sprintf("must not be greater than spec.generation, %d", c.Spec.Generation),
+	}
+}
+
+// ValidateProvisionerNamespace returns an error if the Channel references a namespaced Provisioner
+// outside its own namespace, unless the Provisioner's namespace is one of allowed.
+func (c *Channel) ValidateProvisionerNamespace(allowed []string) *apis.FieldError {
+	if c.Spec.Provisioner == nil || c.Spec.Provisioner.Ref == nil {
+		return nil
+	}
+	namespace := c.Spec.Provisioner.Ref.Namespace
+	if namespace == "" || namespace == c.Namespace {
+		return nil
+	}
+	for _, a := range allowed {
+		if namespace == a {
+			return nil
+		}
+	}
+	fe := apis.ErrInvalidValue(namespace, "spec.provisioner.ref.namespace")
+	fe.Details = fmt.Sprintf("Channels may only reference Provisioners in their own namespace or in one of %v", allowed)
+	return fe
+}
+
+// ValidateNameWithSuffix returns an error if the Channel's name would no longer be a valid DNS-1123
+// label once a Provisioner appends a suffix of up to maxSuffix characters to it when naming the
+// Channel's child resources.
+func (c *Channel) ValidateNameWithSuffix(maxSuffix int) *apis.FieldError {
+	if len(c.Name)+maxSuffix <= validation.DNS1123LabelMaxLength {
+		return nil
+	}
+	fe := apis.ErrInvalidValue(c.Name, "metadata.name")
+	fe.Details = fmt.Sprintf("must be no more than %d characters to leave room for a %d character suffix",
+		validation.DNS1123LabelMaxLength-maxSuffix, maxSuffix)
+	return fe
+}
+
+// validateDomainName returns an error if the Channel's address, {name}.{namespace}.svc.cluster.local,
+// would not be a resolvable DNS name.
+func (c *Channel) validateDomainName() *apis.FieldError {
+	if c.Name == "" {
+		return nil
+	}
+	var details string
+	domain := fmt.Sprintf("%s.%s.svc.cluster.local", c.Name, c.Namespace)
+	if len(c.Name) > validation.DNS1123LabelMaxLength {
+		details = fmt.Sprintf("must be no more than %d characters to be a label of the Channel's address", validation.DNS1123LabelMaxLength)
+	} else if len(domain) > validation.DNS1123SubdomainMaxLength {
+		details = fmt.Sprintf("the Channel's address %q would be longer than %d characters", domain, validation.DNS1123SubdomainMaxLength)
+	} else {
+		return nil
+	}
+	fe := apis.ErrInvalidValue(c.Name, "metadata.name")
+	fe.Details = details
+	return fe
+}
+
+// ValidateRequiredLabels returns an error naming every label in required that the Channel doesn't
+// have.
+func (c *Channel) ValidateRequiredLabels(required []string) *apis.FieldError {
+	var missing []string
+	for _, l := range required {
+		if _, ok := c.Labels[l]; !ok {
+			missing = append(missing, fmt.Sprintf("metadata.labels[%s]", l))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	fe := apis.ErrMissingField(missing...)
+	fe.Details = "the labels are required by cluster policy"
+	return fe
+}
+
+// Validate rejects a status whose conditions transitioned in the future, or that lists a
+// subscribable target more than once.
+func (cs *ChannelStatus) Validate() *apis.FieldError {
+	errs := validateConditionTimes(cs.Conditions, now().Add(MaxClockSkew))
+	for i, ref := range cs.SubscribableTargets {
+		for _, prev := range cs.SubscribableTargets[:i] {
+			if prev == ref {
+				fe := apis.ErrInvalidValue(fmt.Sprintf("%s/%s", ref.Namespace, ref.Name), apis.CurrentField)
+				fe.Details = "duplicate subscribable target"
+				errs = errs.Also(fe.ViaFieldIndex("subscribableTargets", i))
+				break
+			}
+		}
+	}
+	return errs
 }
 
 func (cs *ChannelSpec) Validate() *apis.FieldError {
 	var errs *apis.FieldError
 	if cs.Provisioner == nil {
 		errs = errs.Also(apis.ErrMissingField("provisioner"))
+	} else {
+		errs = errs.Also(cs.Provisioner.Validate().ViaField("provisioner"))
+	}
+
+	if cs.Arguments != nil && len(cs.Arguments.Raw) > MaxArgumentsSize {
+		fe := apis.ErrInvalidValue(fmt.Sprintf("%d bytes", len(cs.Arguments.Raw)), "arguments")
+		fe.Details = fmt.Sprintf("must be no more than %d bytes", MaxArgumentsSize)
+		// Don't parse the arguments any further.
+		return errs.Also(fe)
+	}
+
+	errs = errs.Also(validateArgumentsTemplates(cs.Arguments))
+	if cs.Provisioner != nil && cs.Provisioner.Ref != nil {
+		if allowed, ok := ProvisionerArgumentKeys[cs.Provisioner.Ref.Name]; ok {
+			errs = errs.Also(validateArgumentKeys(cs.Arguments, allowed))
+		}
+		errs = errs.Also(validateArgumentGroups(cs.Arguments, ProvisionerArgumentGroups[cs.Provisioner.Ref.Name]))
+	}
+	ca, fe := decodeChannelArguments(cs.Arguments)
+	errs = errs.Also(fe)
+	if ca != nil {
+		errs = errs.Also(ca.Validate().ViaField("arguments"))
+	}
+
+	if cs.ownsChannelable() && cs.Channelable != nil && len(cs.Channelable.Subscribers) > 0 {
+		errs = errs.Also(apis.ErrMultipleOneOf("provisioner", "channelable"))
+	}
+
+	if cs.Channelable != nil && MaxSubscribers > 0 && len(cs.Channelable.Subscribers) > MaxSubscribers {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("Too many subscribers: %d", len(cs.Channelable.Subscribers)),
+			Paths:   []string{"channelable"},
+			Details: fmt.Sprintf("at most %d subscribers are allowed", MaxSubscribers),
+		})
 	}
 
 	if cs.Channelable != nil {
@@ -41,12 +254,85 @@ func (cs *ChannelSpec) Validate() *apis.FieldError {
 				fe.Details = "expected at least one of, got none"
 				errs = errs.Also(fe.ViaField(fmt.Sprintf("subscriber[%d]", i)).ViaField("channelable"))
 			}
+			if subscriber.Filter != nil {
+				errs = errs.Also(subscriber.Filter.Validate().ViaField("filter").ViaField(fmt.Sprintf("subscriber[%d]", i)).ViaField("channelable"))
+			}
 		}
 	}
 
 	return errs
 }
 
+// Validate rejects a filter on anything but the CloudEvents context attributes Triggers can filter
+// by, TriggerFilterAttributes.
+func (f *SubscriberFilter) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	for k := range f.Attributes {
+		if !TriggerFilterAttributes.Has(k) {
+			fe := apis.ErrInvalidKeyName(k, "attributes")
+			fe.Details = fmt.Sprintf("only the CloudEvents attributes %v can be filtered by", TriggerFilterAttributes.List())
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
+
+// validateLeaseExpiry returns an error for every subscriber whose lease expired before now.
+func (cs *ChannelSpec) validateLeaseExpiry(now time.Time) *apis.FieldError {
+	if cs.Channelable == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	for i, subscriber := range cs.Channelable.Subscribers {
+		if subscriber.IsExpired(now) {
+			fe := apis.ErrInvalidValue(subscriber.LeaseExpiry.UTC().Format(time.RFC3339), "leaseExpiry")
+			fe.Details = "must not be in the past"
+			errs = errs.Also(fe.ViaField(fmt.Sprintf("subscriber[%d]", i)).ViaField("channelable"))
+		}
+	}
+	return errs
+}
+
+// ownsChannelable returns true if the spec's Provisioner is one of ProvisionersOwningChannelable.
+func (cs *ChannelSpec) ownsChannelable() bool {
+	return cs.Provisioner != nil && cs.Provisioner.Ref != nil && ProvisionersOwningChannelable.Has(cs.Provisioner.Ref.Name)
+}
+
+// ValidateProvisionerForChannel returns an error if the provisioner referenced by ref, as loaded by
+// the caller, does not exist or does not reconcile Channels.
+func ValidateProvisionerForChannel(ref ProvisionerReference, provisioner *ClusterProvisioner) *apis.FieldError {
+	name := ""
+	if ref.Ref != nil {
+		name = ref.Ref.Name
+	}
+	if provisioner == nil {
+		fe := apis.ErrInvalidValue(name, "ref.name")
+		fe.Details = "the provisioner does not exist"
+		return fe
+	}
+	want := metav1.GroupKind{Group: SchemeGroupVersion.Group, Kind: "Channel"}
+	if provisioner.Spec.Reconciles != want {
+		fe := apis.ErrInvalidValue(name, "ref.name")
+		fe.Details = fmt.Sprintf("the provisioner reconciles %s, not %s", provisioner.Spec.Reconciles.String(), want.String())
+		return fe
+	}
+	return nil
+}
+
+// validateConditionTimes returns an error for every condition whose LastTransitionTime is after
+// latest.
+func validateConditionTimes(conditions duckv1alpha1.Conditions, latest time.Time) *apis.FieldError {
+	var errs *apis.FieldError
+	for i, c := range conditions {
+		if t := c.LastTransitionTime.Inner; t.After(latest) {
+			fe := apis.ErrInvalidValue(t.UTC().Format(time.RFC3339), "lastTransitionTime")
+			fe.Details = "must not be in the future"
+			errs = errs.Also(fe.ViaFieldIndex("conditions", i))
+		}
+	}
+	return errs
+}
+
 func (current *Channel) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
 	if og == nil {
 		return nil
@@ -55,12 +341,71 @@ func (current *Channel) CheckImmutableFields(og apis.Immutable) *apis.FieldError
 	if !ok {
 		return &apis.FieldError{Message: "The provided resource was not a Channel"}
 	}
-	ignoreArguments := cmpopts.IgnoreFields(ChannelSpec{}, "Arguments", "Channelable")
-	if diff := cmp.Diff(original.Spec, current.Spec, ignoreArguments); diff != "" {
-		return &apis.FieldError{
-			Message: "Immutable fields changed",
-			Paths:   []string{"spec.provisioner"},
-		}
+	// Channels stored before their Provisioner reference was normalized must not appear to change
+	// it when they are next updated, and defaulted, so both references are compared normalized.
+	original, current = original.withNormalizedProvisioner(), current.withNormalizedProvisioner()
+	if fe := checkImmutableFields(&original.Spec, &current.Spec); fe != nil {
+		return fe.ViaField("spec")
+	}
+	var mutable []string
+	if p := original.Spec.Provisioner; p != nil && p.Ref != nil {
+		mutable = ProvisionerMutableArguments[p.Ref.Name]
+	}
+	if fe := checkImmutableArguments(original.Spec.Arguments, current.Spec.Arguments, mutable); fe != nil {
+		return fe.ViaField("spec")
 	}
 	return nil
 }
+
+// withNormalizedProvisioner returns a copy of the Channel whose Provisioner reference is normalized,
+// see ProvisionerReference.SetDefaults, or the Channel itself if it has none.
+func (c *Channel) withNormalizedProvisioner() *Channel {
+	if c.Spec.Provisioner == nil {
+		return c
+	}
+	c = c.DeepCopy()
+	c.Spec.Provisioner.SetDefaults()
+	return c
+}
+
+// CheckReservedMetadata returns an error if user, unless it is one of ReservedMetadataWriters,
+// added, changed or removed a label or annotation of the Channel under ReservedMetadataPrefix. It is
+// not part of CheckImmutableFields, which does not know who made the change, and is called by the
+// webhook with the user of the admission request instead.
+func (current *Channel) CheckReservedMetadata(original *Channel, user string) *apis.FieldError {
+	if original == nil || ReservedMetadataWriters.Has(user) {
+		return nil
+	}
+	var errs *apis.FieldError
+	errs = errs.Also(checkReservedKeys("metadata.labels", original.Labels, current.Labels))
+	errs = errs.Also(checkReservedKeys("metadata.annotations", original.Annotations, current.Annotations))
+	return errs
+}
+
+// checkReservedKeys returns an error naming every key of field under ReservedMetadataPrefix that was
+// added, changed or removed between original and current.
+func checkReservedKeys(field string, original, current map[string]string) *apis.FieldError {
+	if ReservedMetadataPrefix == "" {
+		return nil
+	}
+	var changed []string
+	for k, v := range original {
+		if cv, ok := current[k]; strings.HasPrefix(k, ReservedMetadataPrefix) && (!ok || cv != v) {
+			changed = append(changed, fmt.Sprintf("%s[%s]", field, k))
+		}
+	}
+	for k := range current {
+		if _, ok := original[k]; strings.HasPrefix(k, ReservedMetadataPrefix) && !ok {
+			changed = append(changed, fmt.Sprintf("%s[%s]", field, k))
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+	return &apis.FieldError{
+		Message: "Reserved keys changed",
+		Paths:   changed,
+		Details: fmt.Sprintf("keys prefixed with %q are managed by the provisioner", ReservedMetadataPrefix),
+	}
+}
@@ -17,13 +17,18 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 var targetURI = "https://example.com"
@@ -56,8 +61,8 @@ func TestChannelValidation(t *testing.T) {
 						Name: "foo",
 					},
 				},
-				Channelable: &duckv1alpha1.Channelable{
-					Subscribers: []duckv1alpha1.ChannelSubscriberSpec{{
+				Channelable: &Channelable{
+					Subscribers: []ChannelSubscriberSpec{{
 						CallableDomain: "callableendpoint",
 						SinkableDomain: "resultendpoint",
 					}},
@@ -73,8 +78,8 @@ func TestChannelValidation(t *testing.T) {
 						Name: "foo",
 					},
 				},
-				Channelable: &duckv1alpha1.Channelable{
-					Subscribers: []duckv1alpha1.ChannelSubscriberSpec{{
+				Channelable: &Channelable{
+					Subscribers: []ChannelSubscriberSpec{{
 						CallableDomain: "callableendpoint",
 						SinkableDomain: "callableendpoint",
 					}, {}},
@@ -94,8 +99,8 @@ func TestChannelValidation(t *testing.T) {
 						Name: "foo",
 					},
 				},
-				Channelable: &duckv1alpha1.Channelable{
-					Subscribers: []duckv1alpha1.ChannelSubscriberSpec{{}, {}},
+				Channelable: &Channelable{
+					Subscribers: []ChannelSubscriberSpec{{}, {}},
 				},
 			},
 		},
@@ -109,6 +114,40 @@ func TestChannelValidation(t *testing.T) {
 			errs = errs.Also(fe)
 			return errs
 		}(),
+	}, {
+		name: "templated arguments",
+		cr: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: "foo",
+					},
+				},
+				Arguments: &runtime.RawExtension{
+					Raw: []byte(`{"topic":"{{.Namespace}}-{{.Name}}"}`),
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "unknown template key in arguments",
+		cr: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: "foo",
+					},
+				},
+				Arguments: &runtime.RawExtension{
+					Raw: []byte(`{"topic":"{{.Bogus}}"}`),
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("{{.Bogus}}", "spec.arguments")
+			fe.Details = "only {{.Name}}, {{.Namespace}} and {{.UID}} may be referenced"
+			return fe
+		}(),
 	}}
 
 	doValidateTest(t, tests)
@@ -155,7 +194,7 @@ func TestChannelImmutableFields(t *testing.T) {
 		},
 		want: nil,
 	}, {
-		name: "good (arguments change)",
+		name: "bad (arguments change)",
 		new: &Channel{
 			Spec: ChannelSpec{
 				Provisioner: &ProvisionerReference{
@@ -180,6 +219,31 @@ func TestChannelImmutableFields(t *testing.T) {
 				},
 			},
 		},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.arguments"},
+		},
+	}, {
+		name: "good (paused toggles)",
+		new: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: "foo",
+					},
+				},
+				Paused: true,
+			},
+		},
+		old: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: "foo",
+					},
+				},
+			},
+		},
 		want: nil,
 	}, {
 		name: "bad (not channel)",
@@ -196,6 +260,31 @@ func TestChannelImmutableFields(t *testing.T) {
 		want: &apis.FieldError{
 			Message: "The provided resource was not a Channel",
 		},
+	}, {
+		name: "good (provisioner stored before it was normalized)",
+		new: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "eventing.knative.dev/v1alpha1",
+						Kind:       "ClusterProvisioner",
+						Name:       "foo",
+					},
+				},
+			},
+		},
+		old: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "v1alpha1",
+						Kind:       "clusterprovisioner",
+						Name:       " foo",
+					},
+				},
+			},
+		},
+		want: nil,
 	}, {
 		name: "bad (provisioner changes)",
 		new: &Channel{
@@ -231,3 +320,1088 @@ func TestChannelImmutableFields(t *testing.T) {
 		})
 	}
 }
+
+func TestChannelCheckReservedMetadata(t *testing.T) {
+	const user = "system:serviceaccount:default:someone"
+	reserved := func(path string) *apis.FieldError {
+		return &apis.FieldError{
+			Message: "Reserved keys changed",
+			Paths:   []string{path},
+			Details: `keys prefixed with "internal.eventing.knative.dev/" are managed by the provisioner`,
+		}
+	}
+	channel := func(labels, annotations map[string]string) *Channel {
+		return &Channel{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      labels,
+				Annotations: annotations,
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		new  *Channel
+		old  *Channel
+		user string
+		want *apis.FieldError
+	}{{
+		name: "good (unrelated label change)",
+		new:  channel(map[string]string{"app": "new", "internal.eventing.knative.dev/topic": "t"}, nil),
+		old:  channel(map[string]string{"app": "old", "internal.eventing.knative.dev/topic": "t"}, nil),
+		user: user,
+	}, {
+		name: "good (created)",
+		new:  channel(map[string]string{"internal.eventing.knative.dev/topic": "t"}, nil),
+		old:  nil,
+		user: user,
+	}, {
+		name: "bad (reserved label added)",
+		new:  channel(map[string]string{"internal.eventing.knative.dev/topic": "t"}, nil),
+		old:  channel(nil, nil),
+		user: user,
+		want: reserved("metadata.labels[internal.eventing.knative.dev/topic]"),
+	}, {
+		name: "bad (reserved label modified)",
+		new:  channel(map[string]string{"internal.eventing.knative.dev/topic": "t2"}, nil),
+		old:  channel(map[string]string{"internal.eventing.knative.dev/topic": "t"}, nil),
+		user: user,
+		want: reserved("metadata.labels[internal.eventing.knative.dev/topic]"),
+	}, {
+		name: "bad (reserved label deleted)",
+		new:  channel(map[string]string{"app": "a"}, nil),
+		old:  channel(map[string]string{"app": "a", "internal.eventing.knative.dev/topic": "t"}, nil),
+		user: user,
+		want: reserved("metadata.labels[internal.eventing.knative.dev/topic]"),
+	}, {
+		name: "bad (reserved annotation modified)",
+		new:  channel(nil, map[string]string{"internal.eventing.knative.dev/offset": "2"}),
+		old:  channel(nil, map[string]string{"internal.eventing.knative.dev/offset": "1"}),
+		user: user,
+		want: reserved("metadata.annotations[internal.eventing.knative.dev/offset]"),
+	}, {
+		name: "good (reserved label modified by the controller)",
+		new:  channel(map[string]string{"internal.eventing.knative.dev/topic": "t2"}, nil),
+		old:  channel(map[string]string{"internal.eventing.knative.dev/topic": "t"}, nil),
+		user: "system:serviceaccount:knative-eventing:eventing-controller",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckReservedMetadata(test.old, test.user)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("CheckReservedMetadata (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelImmutableFields_MutableArguments(t *testing.T) {
+	defer func(mutable map[string][]string) { ProvisionerMutableArguments = mutable }(ProvisionerMutableArguments)
+	ProvisionerMutableArguments = map[string][]string{
+		"kafka": {"retention", "consumer.maxBatch"},
+	}
+
+	channel := func(provisioner, args string) *Channel {
+		return &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: provisioner,
+					},
+				},
+				Arguments: &runtime.RawExtension{Raw: []byte(args)},
+			},
+		}
+	}
+	const original = `{"topic":"events","retention":{"hours":24},"consumer":{"group":"a","maxBatch":10}}`
+	testCases := map[string]struct {
+		old  *Channel
+		new  *Channel
+		want *apis.FieldError
+	}{
+		"mutable key changes": {
+			old: channel("kafka", original),
+			new: channel("kafka", `{"topic":"events","retention":{"hours":48},"consumer":{"group":"a","maxBatch":20}}`),
+		},
+		"mutable key removed": {
+			old: channel("kafka", original),
+			new: channel("kafka", `{"topic":"events","consumer":{"group":"a","maxBatch":10}}`),
+		},
+		"only formatting changes": {
+			old: channel("kafka", original),
+			new: channel("kafka", `{"consumer": {"maxBatch": 10, "group": "a"}, "retention": {"hours": 24}, "topic": "events"}`),
+		},
+		"immutable keys change": {
+			old: channel("kafka", original),
+			new: channel("kafka", `{"topic":"orders","retention":{"hours":48},"consumer":{"group":"b","maxBatch":10},"replicas":3}`),
+			want: &apis.FieldError{
+				Message: "Immutable fields changed",
+				Paths:   []string{"spec.arguments.consumer.group", "spec.arguments.replicas", "spec.arguments.topic"},
+			},
+		},
+		"unregistered provisioner": {
+			old: channel("in-memory-channel", original),
+			new: channel("in-memory-channel", `{"topic":"events","retention":{"hours":48},"consumer":{"group":"a","maxBatch":10}}`),
+			want: &apis.FieldError{
+				Message: "Immutable fields changed",
+				Paths:   []string{"spec.arguments.retention.hours"},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.new.CheckImmutableFields(tc.old)
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_ProvisionerOwningChannelable(t *testing.T) {
+	defer func(owners sets.String) { ProvisionersOwningChannelable = owners }(ProvisionersOwningChannelable)
+	ProvisionersOwningChannelable = sets.NewString("kafka")
+
+	channelable := &Channelable{
+		Subscribers: []ChannelSubscriberSpec{{
+			CallableDomain: "callable",
+		}},
+	}
+	provisioner := func(name string) *ProvisionerReference {
+		return &ProvisionerReference{
+			Ref: &corev1.ObjectReference{
+				Name: name,
+			},
+		}
+	}
+	testCases := map[string]struct {
+		spec ChannelSpec
+		want *apis.FieldError
+	}{
+		"conflict": {
+			spec: ChannelSpec{
+				Provisioner: provisioner("kafka"),
+				Channelable: channelable,
+			},
+			want: apis.ErrMultipleOneOf("provisioner", "channelable").ViaField("spec"),
+		},
+		"provisioner only": {
+			spec: ChannelSpec{
+				Provisioner: provisioner("kafka"),
+				Channelable: &Channelable{},
+			},
+		},
+		"channelable with another provisioner": {
+			spec: ChannelSpec{
+				Provisioner: provisioner("in-memory-channel"),
+				Channelable: channelable,
+			},
+		},
+		"channelable only": {
+			spec: ChannelSpec{
+				Channelable: channelable,
+			},
+			want: apis.ErrMissingField("spec.provisioner"),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{Spec: tc.spec}
+			got := c.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_ProvisionerArgumentKeys(t *testing.T) {
+	defer func(keys map[string]sets.String) { ProvisionerArgumentKeys = keys }(ProvisionerArgumentKeys)
+	ProvisionerArgumentKeys = map[string]sets.String{
+		"kafka": sets.NewString("partitionKey", "numPartitions"),
+	}
+
+	spec := func(provisioner, args string) ChannelSpec {
+		return ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Name: provisioner,
+				},
+			},
+			Arguments: &runtime.RawExtension{Raw: []byte(args)},
+		}
+	}
+	testCases := map[string]struct {
+		spec ChannelSpec
+		want *apis.FieldError
+	}{
+		"allowed keys": {
+			spec: spec("kafka", `{"partitionKey": "id", "numPartitions": 3}`),
+		},
+		"extra key": {
+			spec: spec("kafka", `{"partitionKey": "id", "retention": "1h", "replicas": 3}`),
+			want: func() *apis.FieldError {
+				fe := apis.ErrDisallowedFields("spec.arguments.replicas", "spec.arguments.retention")
+				fe.Details = "the provisioner only accepts the arguments [numPartitions partitionKey]"
+				return fe
+			}(),
+		},
+		"extra key with another provisioner": {
+			spec: spec("in-memory-channel", `{"retention": "1h"}`),
+		},
+		"no arguments": {
+			spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: "kafka",
+					},
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{Spec: tc.spec}
+			got := c.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_ProvisionerArgumentGroups(t *testing.T) {
+	defer func(groups map[string][][]string) { ProvisionerArgumentGroups = groups }(ProvisionerArgumentGroups)
+	ProvisionerArgumentGroups = map[string][][]string{
+		"kafka": {{"topic", "numPartitions"}, {"saslUser", "saslPassword"}},
+	}
+
+	spec := func(provisioner, args string) ChannelSpec {
+		return ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Name: provisioner,
+				},
+			},
+			Arguments: &runtime.RawExtension{Raw: []byte(args)},
+		}
+	}
+	testCases := map[string]struct {
+		spec ChannelSpec
+		want *apis.FieldError
+	}{
+		"complete group": {
+			spec: spec("kafka", `{"topic": "events", "numPartitions": 3}`),
+		},
+		"no group": {
+			spec: spec("kafka", `{"retention": "1h"}`),
+		},
+		"partial group": {
+			spec: spec("kafka", `{"numPartitions": 3}`),
+			want: func() *apis.FieldError {
+				fe := apis.ErrMissingField("spec.arguments.topic")
+				fe.Details = "the arguments [topic numPartitions] must be set together"
+				return fe
+			}(),
+		},
+		"partial groups": {
+			spec: spec("kafka", `{"topic": "events", "saslUser": "admin"}`),
+			want: func() *apis.FieldError {
+				fe := apis.ErrMissingField("spec.arguments.numPartitions")
+				fe.Details = "the arguments [topic numPartitions] must be set together"
+				fe2 := apis.ErrMissingField("spec.arguments.saslPassword")
+				fe2.Details = "the arguments [saslUser saslPassword] must be set together"
+				return fe.Also(fe2)
+			}(),
+		},
+		"partial group with another provisioner": {
+			spec: spec("in-memory-channel", `{"numPartitions": 3}`),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{Spec: tc.spec}
+			got := c.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidateProvisionerNamespace(t *testing.T) {
+	allowed := []string{"knative-eventing"}
+	testCases := map[string]struct {
+		namespace string
+		want      *apis.FieldError
+	}{
+		"cluster-scoped": {},
+		"same namespace": {
+			namespace: "test-namespace",
+		},
+		"cross-namespace allowed": {
+			namespace: "knative-eventing",
+		},
+		"cross-namespace denied": {
+			namespace: "other-namespace",
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("other-namespace", "spec.provisioner.ref.namespace")
+				fe.Details = "Channels may only reference Provisioners in their own namespace or in one of [knative-eventing]"
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-namespace",
+				},
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Namespace: tc.namespace,
+							Name:      "kafka",
+						},
+					},
+				},
+			}
+			got := c.ValidateProvisionerNamespace(allowed)
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateChannel(t *testing.T) {
+	testCases := map[string]*Channel{
+		"valid": {
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{Name: "foo"},
+				},
+				Arguments: &runtime.RawExtension{Raw: []byte(`{"defaultConcurrency":2}`)},
+				Channelable: &Channelable{
+					Subscribers: []ChannelSubscriberSpec{{
+						CallableDomain: "callable",
+					}},
+				},
+			},
+		},
+		"missing provisioner": {
+			Spec: ChannelSpec{},
+		},
+		"invalid arguments": {
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{Name: "foo"},
+				},
+				Arguments: &runtime.RawExtension{Raw: []byte(`{"defaultConcurrency":0}`)},
+			},
+		},
+		"invalid channelable": {
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{Name: "foo"},
+				},
+				Channelable: &Channelable{
+					Subscribers: []ChannelSubscriberSpec{{}},
+				},
+			},
+		},
+	}
+	for n, c := range testCases {
+		t.Run(n, func(t *testing.T) {
+			standalone := ValidateChannel(c)
+			if wantErr := n != "valid"; wantErr != (standalone != nil) {
+				t.Errorf("unexpected ValidateChannel result: want error %v, got %v", wantErr, standalone)
+			}
+			if diff := cmp.Diff(c.Validate().Error(), standalone.Error()); diff != "" {
+				t.Errorf("ValidateChannel differs from Validate (-validate, +standalone) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateChannel_CreateOnlyChecks(t *testing.T) {
+	defer func(required []string) { RequiredLabels = required }(RequiredLabels)
+	RequiredLabels = []string{"team"}
+	c := &Channel{
+		Spec: ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{Name: "foo"},
+			},
+		},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error from Validate: %v", err)
+	}
+	// Unlike Validate, ValidateChannel also runs the checks of creation only.
+	if diff := cmp.Diff(c.ValidateCreate().Error(), ValidateChannel(c).Error()); diff != "" {
+		t.Errorf("unexpected ValidateChannel error (-want, +got) = %v", diff)
+	}
+	if ValidateChannel(c) == nil {
+		t.Errorf("expected ValidateChannel to reject a Channel missing a required label")
+	}
+}
+
+func TestChannelValidateCreate_RequiredLabels(t *testing.T) {
+	defer func(required []string) { RequiredLabels = required }(RequiredLabels)
+	RequiredLabels = []string{"team"}
+	unlabelled := &Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-channel",
+			Namespace: "test-namespace",
+		},
+		Spec: ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{Name: "foo"},
+			},
+		},
+	}
+
+	if err := unlabelled.ValidateCreate(); err == nil {
+		t.Errorf("expected creating an unlabelled Channel to fail")
+	}
+	if err := ValidateChannel(unlabelled); err == nil {
+		t.Errorf("expected ValidateChannel to reject an unlabelled Channel")
+	}
+
+	// A pre-existing unlabelled Channel must still accept status updates and finalizer removal.
+	unlabelled.Finalizers = []string{"test-finalizer"}
+	updated := unlabelled.DeepCopy()
+	updated.Finalizers = nil
+	updated.Status.MarkProvisioned()
+	if err := updated.CheckImmutableFields(unlabelled); err != nil {
+		t.Errorf("unexpected error updating an unlabelled Channel: %v", err)
+	}
+	if err := updated.Validate(); err != nil {
+		t.Errorf("unexpected error updating an unlabelled Channel: %v", err)
+	}
+
+	owned := unlabelled.DeepCopy()
+	owned.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(&Broker{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, SchemeGroupVersion.WithKind("Broker")),
+	}
+	if err := owned.ValidateCreate(); err != nil {
+		t.Errorf("unexpected error creating a Channel owned by a Broker: %v", err)
+	}
+}
+
+func TestChannelValidateNameWithSuffix(t *testing.T) {
+	const suffix = 10
+	testCases := map[string]struct {
+		name string
+		want *apis.FieldError
+	}{
+		"short": {
+			name: "orders",
+		},
+		"at the boundary": {
+			name: strings.Repeat("a", 53),
+		},
+		"one over the boundary": {
+			name: strings.Repeat("a", 54),
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue(strings.Repeat("a", 54), "metadata.name")
+				fe.Details = "must be no more than 53 characters to leave room for a 10 character suffix"
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: tc.name,
+				},
+			}
+			got := c.ValidateNameWithSuffix(suffix)
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_MaxArgumentsSize(t *testing.T) {
+	defer func(max int) { MaxArgumentsSize = max }(MaxArgumentsSize)
+	MaxArgumentsSize = 64
+
+	args := func(size int) *runtime.RawExtension {
+		// {"blob":"..."} is 11 bytes plus the blob.
+		return &runtime.RawExtension{Raw: []byte(`{"blob":"` + strings.Repeat("x", size-11) + `"}`)}
+	}
+	testCases := map[string]struct {
+		args *runtime.RawExtension
+		want *apis.FieldError
+	}{
+		"under the limit": {
+			args: args(32),
+		},
+		"at the limit": {
+			args: args(64),
+		},
+		"over the limit": {
+			args: args(65),
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("65 bytes", "spec.arguments")
+				fe.Details = "must be no more than 64 bytes"
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+					Arguments: tc.args,
+				},
+			}
+			got := c.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_SubscribableTargets(t *testing.T) {
+	target := func(name string) corev1.ObjectReference {
+		return corev1.ObjectReference{
+			APIVersion: "messaging.example.dev/v1alpha1",
+			Kind:       "KafkaTopic",
+			Namespace:  "test-namespace",
+			Name:       name,
+		}
+	}
+	testCases := map[string]struct {
+		targets []corev1.ObjectReference
+		want    *apis.FieldError
+	}{
+		"distinct": {
+			targets: []corev1.ObjectReference{target("east"), target("west")},
+		},
+		"duplicate": {
+			targets: []corev1.ObjectReference{target("east"), target("west"), target("east")},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("test-namespace/east", "status.subscribableTargets[2]")
+				fe.Details = "duplicate subscribable target"
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+				},
+			}
+			c.Status.SetSubscribableTargets(tc.targets)
+			got := c.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_ObservedGeneration(t *testing.T) {
+	testCases := map[string]struct {
+		generation         int64
+		observedGeneration int64
+		want               *apis.FieldError
+	}{
+		"not observed": {
+			generation: 2,
+		},
+		"behind": {
+			generation:         2,
+			observedGeneration: 1,
+		},
+		"current": {
+			generation:         2,
+			observedGeneration: 2,
+		},
+		"ahead": {
+			generation:         2,
+			observedGeneration: 3,
+			want: &apis.FieldError{
+				Message: "Internal consistency error: observed generation 3 is ahead of the spec",
+				Paths:   []string{"status.observedGeneration"},
+				Details: "must not be greater than spec.generation, 2",
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				Spec: ChannelSpec{
+					Generation: tc.generation,
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+				},
+				Status: ChannelStatus{
+					ObservedGeneration: tc.observedGeneration,
+				},
+			}
+			got := c.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_DomainName(t *testing.T) {
+	longNamespace := strings.Repeat("n", 190)
+	testCases := map[string]struct {
+		name      string
+		namespace string
+		want      *apis.FieldError
+	}{
+		"normal": {
+			name:      "orders",
+			namespace: "default",
+		},
+		"at the label boundary": {
+			name:      strings.Repeat("a", 63),
+			namespace: "default",
+		},
+		"over the label boundary": {
+			name:      strings.Repeat("a", 64),
+			namespace: "default",
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue(strings.Repeat("a", 64), "metadata.name")
+				fe.Details = "must be no more than 63 characters to be a label of the Channel's address"
+				return fe
+			}(),
+		},
+		"overflowing the address": {
+			name:      strings.Repeat("a", 50),
+			namespace: longNamespace,
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue(strings.Repeat("a", 50), "metadata.name")
+				fe.Details = fmt.Sprintf("the Channel's address %q would be longer than 253 characters",
+					strings.Repeat("a", 50)+"."+longNamespace+".svc.cluster.local")
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: tc.namespace,
+					Name:      tc.name,
+				},
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+				},
+			}
+			got := c.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_RestrictProvisionerNamespaces(t *testing.T) {
+	defer func(restrict bool) { RestrictProvisionerNamespaces = restrict }(RestrictProvisionerNamespaces)
+	c := &Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+		},
+		Spec: ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Namespace: "other-namespace",
+					Name:      "kafka",
+				},
+			},
+		},
+	}
+
+	RestrictProvisionerNamespaces = false
+	if err := c.Validate(); err != nil {
+		t.Errorf("expected cross-namespace Provisioners to be allowed by default, got %v", err)
+	}
+	RestrictProvisionerNamespaces = true
+	if err := c.Validate(); err == nil {
+		t.Errorf("expected a cross-namespace Provisioner to be rejected when restricted")
+	}
+}
+
+func TestChannelValidation_MaxSubscribers(t *testing.T) {
+	defer func(max int) { MaxSubscribers = max }(MaxSubscribers)
+	MaxSubscribers = 3
+
+	channel := func(subscribers int) *Channel {
+		c := &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: "foo",
+					},
+				},
+				Channelable: &Channelable{},
+			},
+		}
+		for i := 0; i < subscribers; i++ {
+			c.Spec.Channelable.Subscribers = append(c.Spec.Channelable.Subscribers, ChannelSubscriberSpec{
+				CallableDomain: "callable",
+			})
+		}
+		return c
+	}
+
+	if err := channel(3).Validate(); err != nil {
+		t.Errorf("expected the maximum number of subscribers to be allowed, got %v", err)
+	}
+	want := &apis.FieldError{
+		Message: "Too many subscribers: 4",
+		Paths:   []string{"spec.channelable"},
+		Details: "at most 3 subscribers are allowed",
+	}
+	if diff := cmp.Diff(want.Error(), channel(4).Validate().Error()); diff != "" {
+		t.Errorf("unexpected error (-want, +got) = %v", diff)
+	}
+
+	MaxSubscribers = 0
+	if err := channel(4).Validate(); err != nil {
+		t.Errorf("expected no limit when MaxSubscribers is zero, got %v", err)
+	}
+}
+
+func TestChannelValidateCreate_LeaseExpiry(t *testing.T) {
+	defer func(n func() time.Time) { now = n }(now)
+	current := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return current }
+
+	testCases := map[string]struct {
+		leaseExpiry *metav1.Time
+		want        *apis.FieldError
+	}{
+		"future expiry": {
+			leaseExpiry: &metav1.Time{Time: current.Add(time.Hour)},
+		},
+		"past expiry": {
+			leaseExpiry: &metav1.Time{Time: current.Add(-time.Hour)},
+			want: &apis.FieldError{
+				Message: "invalid value \"2019-01-02T02:04:05Z\"",
+				Paths:   []string{"spec.channelable.subscriber[0].leaseExpiry"},
+				Details: "must not be in the past",
+			},
+		},
+		"no expiry": {},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+					Channelable: &Channelable{
+						Subscribers: []ChannelSubscriberSpec{{
+							CallableDomain: "callable",
+							LeaseExpiry:    tc.leaseExpiry,
+						}},
+					},
+				},
+			}
+			if diff := cmp.Diff(tc.want.Error(), c.ValidateCreate().Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+			// Channels whose leases expired since they were created can still be updated.
+			if err := c.Validate(); err != nil {
+				t.Errorf("unexpected error validating an update: %v", err)
+			}
+		})
+	}
+}
+
+func TestChannelValidateCreate_ArgumentsTemplates(t *testing.T) {
+	testCases := map[string]struct {
+		meta metav1.ObjectMeta
+		args string
+		want *apis.FieldError
+	}{
+		"metadata set": {
+			meta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-name", UID: "test-uid"},
+			args: `{"group":"{{.UID}}","topic":"{{.Name}}"}`,
+		},
+		"no uid": {
+			meta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-name"},
+			args: `{"group":"{{.UID}}","topic":"{{.Name}}"}`,
+			want: &apis.FieldError{
+				Message: `invalid value "{{.UID}}"`,
+				Paths:   []string{"spec.arguments"},
+				Details: "references metadata that isn't set when the Channel is created, such as {{.UID}}, or {{.Name}} with generateName",
+			},
+		},
+		"generated name": {
+			meta: metav1.ObjectMeta{Namespace: "test-namespace", GenerateName: "test-"},
+			args: `{"topic":"{{.Namespace}}-{{.Name}}"}`,
+			want: &apis.FieldError{
+				Message: `invalid value "{{.Namespace}}-{{.Name}}"`,
+				Paths:   []string{"spec.arguments"},
+				Details: "references metadata that isn't set when the Channel is created, such as {{.UID}}, or {{.Name}} with generateName",
+			},
+		},
+		"unknown key is reported by Validate only": {
+			meta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-name"},
+			args: `{"group":"{{.UID}}","topic":"{{.Bogus}}"}`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: tc.meta,
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+					Arguments: &runtime.RawExtension{Raw: []byte(tc.args)},
+				},
+			}
+			c.SetDefaults()
+			if diff := cmp.Diff(tc.want.Error(), c.ValidateCreate().Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidation_SubscriberFilter(t *testing.T) {
+	testCases := map[string]struct {
+		filter *SubscriberFilter
+		want   *apis.FieldError
+	}{
+		"valid attribute names": {
+			filter: &SubscriberFilter{Attributes: map[string]string{"eventType": "dev.knative.foo", "source": "bar"}},
+		},
+		"reserved attribute name": {
+			filter: &SubscriberFilter{Attributes: map[string]string{"eventID": "1234"}},
+			want: &apis.FieldError{
+				Message: "invalid key name \"eventID\"",
+				Paths:   []string{"spec.channelable.subscriber[0].filter.attributes"},
+				Details: "only the CloudEvents attributes [cloudEventsVersion contentType eventType eventTypeVersion schemaURL source] can be filtered by",
+			},
+		},
+		"invalid attribute name": {
+			filter: &SubscriberFilter{Attributes: map[string]string{"event-type": "dev.knative.foo"}},
+			want: &apis.FieldError{
+				Message: "invalid key name \"event-type\"",
+				Paths:   []string{"spec.channelable.subscriber[0].filter.attributes"},
+				Details: "only the CloudEvents attributes [cloudEventsVersion contentType eventType eventTypeVersion schemaURL source] can be filtered by",
+			},
+		},
+		"empty filter": {
+			filter: &SubscriberFilter{},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				Spec: ChannelSpec{
+					Provisioner: &ProvisionerReference{
+						Ref: &corev1.ObjectReference{
+							Name: "foo",
+						},
+					},
+					Channelable: &Channelable{
+						Subscribers: []ChannelSubscriberSpec{{
+							CallableDomain: "callable",
+							Filter:         tc.filter,
+						}},
+					},
+				},
+			}
+			if diff := cmp.Diff(tc.want.Error(), c.Validate().Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelValidateRequiredLabels(t *testing.T) {
+	required := []string{"team", "cost-center"}
+	testCases := map[string]struct {
+		labels map[string]string
+		want   *apis.FieldError
+	}{
+		"all present": {
+			labels: map[string]string{"team": "payments", "cost-center": "1234", "app": "orders"},
+		},
+		"one missing": {
+			labels: map[string]string{"team": "payments"},
+			want: func() *apis.FieldError {
+				fe := apis.ErrMissingField("metadata.labels[cost-center]")
+				fe.Details = "the labels are required by cluster policy"
+				return fe
+			}(),
+		},
+		"no labels": {
+			want: func() *apis.FieldError {
+				fe := apis.ErrMissingField("metadata.labels[team]", "metadata.labels[cost-center]")
+				fe.Details = "the labels are required by cluster policy"
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &Channel{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: tc.labels,
+				},
+			}
+			got := c.ValidateRequiredLabels(required)
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestChannelStatusValidation(t *testing.T) {
+	defer func(n func() time.Time) { now = n }(now)
+	current := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return current }
+
+	past := current.Add(-time.Hour)
+	skewed := current.Add(MaxClockSkew / 2)
+	future := current.Add(time.Hour)
+	tests := []struct {
+		name string
+		cs   *ChannelStatus
+		want *apis.FieldError
+	}{{
+		name: "past transition",
+		cs: &ChannelStatus{
+			Conditions: []duckv1alpha1.Condition{{
+				Type:               ChannelConditionReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(past)},
+			}},
+		},
+		want: nil,
+	}, {
+		name: "transition within the clock skew",
+		cs: &ChannelStatus{
+			Conditions: []duckv1alpha1.Condition{{
+				Type:               ChannelConditionReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(skewed)},
+			}},
+		},
+		want: nil,
+	}, {
+		name: "future transition",
+		cs: &ChannelStatus{
+			Conditions: []duckv1alpha1.Condition{{
+				Type:               ChannelConditionReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(past)},
+			}, {
+				Type:               ChannelConditionProvisioned,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(future)},
+			}},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue(future.UTC().Format(time.RFC3339), "conditions[1].lastTransitionTime")
+			fe.Details = "must not be in the future"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.cs.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateProvisionerForChannel(t *testing.T) {
+	ref := ProvisionerReference{
+		Ref: &corev1.ObjectReference{
+			Name: "kafka",
+		},
+	}
+	tests := []struct {
+		name        string
+		provisioner *ClusterProvisioner
+		want        *apis.FieldError
+	}{{
+		name: "reconciles channels",
+		provisioner: &ClusterProvisioner{
+			Spec: ClusterProvisionerSpec{
+				Reconciles: metav1.GroupKind{
+					Group: "eventing.knative.dev",
+					Kind:  "Channel",
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "reconciles sources",
+		provisioner: &ClusterProvisioner{
+			Spec: ClusterProvisionerSpec{
+				Reconciles: metav1.GroupKind{
+					Group: "eventing.knative.dev",
+					Kind:  "Source",
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("kafka", "ref.name")
+			fe.Details = "the provisioner reconciles Source.eventing.knative.dev, not Channel.eventing.knative.dev"
+			return fe
+		}(),
+	}, {
+		name:        "nil provisioner",
+		provisioner: nil,
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("kafka", "ref.name")
+			fe.Details = "the provisioner does not exist"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ValidateProvisionerForChannel(ref, test.provisioner)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: ValidateProvisionerForChannel (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// serverManagedMetadata are the metadata fields populated by the API server or the controllers,
+// e.g. the finalizers of the Provisioners' controllers, rather than the user.
+var serverManagedMetadata = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"finalizers",
+	"generation",
+	"initializers",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// managedAnnotations are the annotations written by tooling or the controllers rather than the
+// user. Labels and annotations under ReservedMetadataPrefix are stripped too.
+var managedAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	SpecHashAnnotation,
+}
+
+// CanonicalYAML serializes the Channel to YAML suitable for detecting drift between the desired and
+// the live state. The status, the spec's generation and the metadata managed by the API server,
+// tooling or the controllers are stripped, and map keys are sorted, so that Channels with identical
+// user-specified content produce byte-identical output.
+func (c *Channel) CanonicalYAML() ([]byte, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	delete(obj, "status")
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		// The webhook bumps the generation on every spec change.
+		delete(spec, "generation")
+	}
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, f := range serverManagedMetadata {
+			delete(metadata, f)
+		}
+		for _, field := range []string{"annotations", "labels"} {
+			m, ok := metadata[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for k := range m {
+				if strings.HasPrefix(k, ReservedMetadataPrefix) {
+					delete(m, k)
+				}
+			}
+			if field == "annotations" {
+				for _, a := range managedAnnotations {
+					delete(m, a)
+				}
+			}
+			if len(m) == 0 {
+				delete(metadata, field)
+			}
+		}
+	}
+	// yaml.Marshal sorts map keys.
+	return yaml.Marshal(obj)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestChannel_CanonicalYAML(t *testing.T) {
+	desired := &Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-channel",
+			Namespace:   "test-namespace",
+			Labels:      map[string]string{"b": "2", "a": "1"},
+			Annotations: map[string]string{"team": "eventing"},
+		},
+		Spec: ChannelSpec{
+			Provisioner: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Name: "in-memory-channel",
+				},
+			},
+			Arguments: &runtime.RawExtension{Raw: []byte(`{"z":1,"a":{"y":true,"b":"x"}}`)},
+		},
+	}
+	live := desired.DeepCopy()
+	live.UID = "test-uid"
+	live.ResourceVersion = "42"
+	live.Generation = 3
+	live.CreationTimestamp = metav1.Now()
+	live.DeletionTimestamp = &live.CreationTimestamp
+	live.DeletionGracePeriodSeconds = func() *int64 { s := int64(30); return &s }()
+	live.Annotations = map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": `{"kind":"Channel"}`,
+		"team": "eventing",
+	}
+	live.Spec.Generation = 2
+	live.Finalizers = []string{"in-memory-channel-controller"}
+	live.Annotations[SpecHashAnnotation] = "test-hash"
+	live.Labels = map[string]string{"a": "1", "b": "2"}
+	live.Labels[ReservedMetadataPrefix+"topic"] = "test-topic"
+	live.Spec.Arguments = &runtime.RawExtension{Raw: []byte(`{"a":{"b":"x","y":true},"z":1}`)}
+	live.Status.Sinkable = duckv1alpha1.Sinkable{DomainInternal: "test-channel.test-namespace.svc.cluster.local"}
+	live.Status.MarkProvisioned()
+
+	want, err := desired.CanonicalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error serializing desired Channel: %v", err)
+	}
+	got, err := live.CanonicalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error serializing live Channel: %v", err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("unexpected canonical YAML (-want, +got) = %v", diff)
+	}
+
+	changed := desired.DeepCopy()
+	changed.Spec.Arguments = &runtime.RawExtension{Raw: []byte(`{"z":2}`)}
+	other, err := changed.CanonicalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error serializing changed Channel: %v", err)
+	}
+	if string(other) == string(want) {
+		t.Errorf("expected different canonical YAML for different specs, got %q", other)
+	}
+}
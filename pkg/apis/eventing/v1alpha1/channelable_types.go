@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Channelable is the list of subscribers of a Channel. It serializes as a superset of the
+// Channelable duck type, so that Channels still implement it, while letting each subscriber carry
+// delivery options the duck type doesn't have.
+type Channelable struct {
+	// +optional
+	Subscribers []ChannelSubscriberSpec `json:"subscribers,omitempty"`
+}
+
+// ChannelSubscriberSpec describes a single subscriber of a Channel.
+type ChannelSubscriberSpec struct {
+	// Ref is the Subscription this subscriber was created from. Provisioners that keep state per
+	// Subscription, such as a consumer group, key it by Ref.
+	// +optional
+	Ref *corev1.ObjectReference `json:"ref,omitempty"`
+
+	// CallableDomain is where events are delivered to.
+	// +optional
+	CallableDomain string `json:"callableDomain,omitempty"`
+
+	// SinkableDomain is where the replies of the CallableDomain, or the events themselves if
+	// there is no CallableDomain, are sent to.
+	// +optional
+	SinkableDomain string `json:"sinkableDomain,omitempty"`
+
+	// DeadLetterSinkDomain is where events are sent to when they could not be delivered to the
+	// CallableDomain, or their reply could not be sent to the SinkableDomain. If it is empty,
+	// such events are dropped.
+	// +optional
+	DeadLetterSinkDomain string `json:"deadLetterSinkDomain,omitempty"`
+
+	// Delivery specifies how failed deliveries are retried. If it is nil, each delivery is
+	// attempted once.
+	// +optional
+	Delivery *DeliverySpec `json:"delivery,omitempty"`
+
+	// LeaseExpiry is when the subscriber expires. Once it has passed, reconcilers may prune the
+	// subscriber, e.g. because it has gone away. If it is nil, the subscriber never expires.
+	// +optional
+	LeaseExpiry *metav1.Time `json:"leaseExpiry,omitempty"`
+
+	// Filter selects the events that are delivered to the subscriber. It is advisory: Provisioners
+	// that can filter events in their data plane may honor it, others deliver every event.
+	// +optional
+	Filter *SubscriberFilter `json:"filter,omitempty"`
+}
+
+// SubscriberFilter selects events by their CloudEvents context attributes.
+type SubscriberFilter struct {
+	// Attributes maps CloudEvents context attribute names, e.g. eventType or source, to the value
+	// the attribute must have. An event is selected if all of them match exactly.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// IsExpired returns true if the subscriber's lease expired before now.
+func (s *ChannelSubscriberSpec) IsExpired(now time.Time) bool {
+	return s.LeaseExpiry != nil && s.LeaseExpiry.Time.Before(now)
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validateDuration rejects negative durations. Durations are metav1.Durations, encoded in JSON as
+// strings in the format accepted by time.ParseDuration, e.g. "30s" or "5m".
+func validateDuration(d metav1.Duration) *apis.FieldError {
+	if d.Duration >= 0 {
+		return nil
+	}
+	fe := apis.ErrInvalidValue(d.Duration.String(), apis.CurrentField)
+	fe.Details = "must not be negative"
+	return fe
+}
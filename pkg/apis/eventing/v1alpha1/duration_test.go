@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDuration_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(DeliverySpec{BackoffDelay: &metav1.Duration{Duration: 90 * time.Second}})
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling: %v", err)
+	}
+	if want := `{"backoffDelay":"1m30s"}`; string(b) != want {
+		t.Errorf("Unexpected JSON. Expected %s, got %s", want, b)
+	}
+}
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	testCases := map[string]struct {
+		json    string
+		want    time.Duration
+		wantErr bool
+	}{
+		"seconds": {
+			json: `"30s"`,
+			want: 30 * time.Second,
+		},
+		"minutes": {
+			json: `"5m"`,
+			want: 5 * time.Minute,
+		},
+		"invalid string": {
+			json:    `"five minutes"`,
+			wantErr: true,
+		},
+		"number": {
+			json:    `300`,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var ds DeliverySpec
+			err := json.Unmarshal([]byte(`{"backoffDelay":`+tc.json+`}`), &ds)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected error %v, got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if ds.BackoffDelay.Duration != tc.want {
+				t.Errorf("Unexpected duration. Expected %v, got %v", tc.want, ds.BackoffDelay.Duration)
+			}
+		})
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	testCases := map[string]struct {
+		d    time.Duration
+		want *apis.FieldError
+	}{
+		"positive": {
+			d: time.Minute,
+		},
+		"zero": {},
+		"negative": {
+			d: -time.Minute,
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("-1m0s", "retention")
+				fe.Details = "must not be negative"
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := validateDuration(metav1.Duration{Duration: tc.d}).ViaField("retention")
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
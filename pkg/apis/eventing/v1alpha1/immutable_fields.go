@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// checkImmutableFields returns an error naming every field tagged `immutable:"true"` that differs
+// between original and current, which must be structs, or pointers to structs, of the same type.
+// Fields are named by their JSON names, so the error can be placed with ViaField.
+func checkImmutableFields(original, current interface{}) *apis.FieldError {
+	ov := reflect.Indirect(reflect.ValueOf(original))
+	cv := reflect.Indirect(reflect.ValueOf(current))
+	t := ov.Type()
+	var changed []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("immutable") != "true" {
+			continue
+		}
+		if !immutableFieldEqual(ov.Field(i), cv.Field(i)) {
+			changed = append(changed, jsonFieldName(f))
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return &apis.FieldError{
+		Message: "Immutable fields changed",
+		Paths:   changed,
+	}
+}
+
+// immutableFieldEqual compares two values of an immutable field with the Equal method of the
+// field's type, if it has one taking the type itself, and semantically otherwise.
+func immutableFieldEqual(original, current reflect.Value) bool {
+	o, c := original, current
+	if o.Kind() == reflect.Ptr {
+		if o.IsNil() || c.IsNil() {
+			return o.IsNil() == c.IsNil()
+		}
+		o, c = o.Elem(), c.Elem()
+	}
+	if eq := o.MethodByName("Equal"); eq.IsValid() {
+		if t := eq.Type(); t.NumIn() == 1 && t.In(0) == c.Type() && t.NumOut() == 1 && t.Out(0).Kind() == reflect.Bool {
+			return eq.Call([]reflect.Value{c})[0].Bool()
+		}
+	}
+	return equality.Semantic.DeepEqual(original.Interface(), current.Interface())
+}
+
+// jsonFieldName returns the name of f when encoded as JSON.
+func jsonFieldName(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
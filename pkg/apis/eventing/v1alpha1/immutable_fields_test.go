@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type immutableFieldsTestSpec struct {
+	Name        string                `json:"name" immutable:"true"`
+	Replicas    *int32                `json:"replicas,omitempty" immutable:"true"`
+	Labels      map[string]string     `immutable:"true"`
+	Provisioner *ProvisionerReference `json:"provisioner,omitempty" immutable:"true"`
+	Comment     string                `json:"comment,omitempty"`
+}
+
+func TestCheckImmutableFields(t *testing.T) {
+	one, two := int32(1), int32(2)
+	original := immutableFieldsTestSpec{
+		Name:     "foo",
+		Replicas: &one,
+		Labels:   map[string]string{"app": "foo"},
+		Provisioner: &ProvisionerReference{
+			Ref: &corev1.ObjectReference{Kind: "ClusterProvisioner", Name: "kafka"},
+		},
+		Comment: "original",
+	}
+	testCases := map[string]struct {
+		mutate func(*immutableFieldsTestSpec)
+		want   *apis.FieldError
+	}{
+		"no change": {
+			mutate: func(*immutableFieldsTestSpec) {},
+		},
+		"untagged field changes": {
+			mutate: func(s *immutableFieldsTestSpec) {
+				s.Comment = "changed"
+			},
+		},
+		"equal pointer targets": {
+			mutate: func(s *immutableFieldsTestSpec) {
+				r := int32(1)
+				s.Replicas = &r
+			},
+		},
+		"equal by Equal method": {
+			mutate: func(s *immutableFieldsTestSpec) {
+				s.Provisioner = &ProvisionerReference{
+					Ref: &corev1.ObjectReference{Kind: "ClusterProvisioner", Name: "kafka", UID: "abc-123"},
+				}
+			},
+		},
+		"unequal by Equal method": {
+			mutate: func(s *immutableFieldsTestSpec) {
+				s.Provisioner = &ProvisionerReference{
+					Ref: &corev1.ObjectReference{Kind: "ClusterProvisioner", Name: "in-memory"},
+				}
+			},
+			want: &apis.FieldError{
+				Message: "Immutable fields changed",
+				Paths:   []string{"provisioner"},
+			},
+		},
+		"field with Equal method unset": {
+			mutate: func(s *immutableFieldsTestSpec) {
+				s.Provisioner = nil
+			},
+			want: &apis.FieldError{
+				Message: "Immutable fields changed",
+				Paths:   []string{"provisioner"},
+			},
+		},
+		"one tagged field changes": {
+			mutate: func(s *immutableFieldsTestSpec) {
+				s.Name = "bar"
+			},
+			want: &apis.FieldError{
+				Message: "Immutable fields changed",
+				Paths:   []string{"name"},
+			},
+		},
+		"several tagged fields change": {
+			mutate: func(s *immutableFieldsTestSpec) {
+				s.Name = "bar"
+				s.Replicas = &two
+				s.Labels = map[string]string{"app": "bar"}
+				s.Comment = "changed"
+			},
+			want: &apis.FieldError{
+				Message: "Immutable fields changed",
+				Paths:   []string{"name", "replicas", "Labels"},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			current := original
+			tc.mutate(&current)
+			got := checkImmutableFields(&original, &current)
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
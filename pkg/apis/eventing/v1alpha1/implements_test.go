@@ -25,6 +25,9 @@ func TestTypesImplements(t *testing.T) {
 		instance interface{}
 		iface    duck.Implementable
 	}{
+		// Broker
+		{instance: &Broker{}, iface: &duckv1alpha1.Conditions{}},
+		{instance: &Broker{}, iface: &duckv1alpha1.Sinkable{}},
 		// Channel
 		{instance: &Channel{}, iface: &duckv1alpha1.Conditions{}},
 		{instance: &Channel{}, iface: &duckv1alpha1.Channelable{}},
@@ -36,6 +39,8 @@ func TestTypesImplements(t *testing.T) {
 		{instance: &Subscription{}, iface: &duckv1alpha1.Conditions{}},
 		{instance: &Subscription{}, iface: &emptyGen},
 		{instance: &Subscription{}, iface: &duckv1alpha1.Subscribable{}},
+		// Trigger
+		{instance: &Trigger{}, iface: &duckv1alpha1.Conditions{}},
 	}
 	for _, tc := range testCases {
 		if err := duck.VerifyType(tc.instance, tc.iface); err != nil {
@@ -17,7 +17,12 @@
 package v1alpha1
 
 import (
+	"strings"
+
+	"github.com/knative/pkg/apis"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // ProvisionerReference defines the strategy for selecting a Provisioner for a
@@ -27,3 +32,95 @@ type ProvisionerReference struct {
 	//TODO: +optional add selector
 	Ref *corev1.ObjectReference `json:"ref,omitempty"`
 }
+
+// NewProvisionerReference returns a normalized reference to the named Provisioner, or an error if
+// kind, name or apiVersion is empty or apiVersion is not a valid group/version.
+func NewProvisionerReference(apiVersion, kind, name string) (*ProvisionerReference, error) {
+	p := &ProvisionerReference{
+		Ref: &corev1.ObjectReference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Name:       name,
+		},
+	}
+	p.SetDefaults()
+	var errs *apis.FieldError
+	if p.Ref.APIVersion == "" {
+		errs = errs.Also(apis.ErrMissingField("ref.apiVersion"))
+	}
+	if p.Ref.Kind == "" {
+		errs = errs.Also(apis.ErrMissingField("ref.kind"))
+	}
+	if p.Ref.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("ref.name"))
+	}
+	if errs = errs.Also(p.Validate()); errs != nil {
+		return nil, errs
+	}
+	return p, nil
+}
+
+// SetDefaults normalizes the reference, so that it can be compared as a string: whitespace is
+// trimmed, the apiVersion is lower-cased and a bare version of this API group is expanded to its
+// group/version form, and the kind of a ClusterProvisioner is given its canonical casing. Values
+// that are not merely malformed are left for Validate to reject.
+func (p *ProvisionerReference) SetDefaults() {
+	if p.Ref == nil {
+		return
+	}
+	p.Ref.Name = strings.TrimSpace(p.Ref.Name)
+	p.Ref.Kind = strings.TrimSpace(p.Ref.Kind)
+	if strings.EqualFold(p.Ref.Kind, "ClusterProvisioner") {
+		p.Ref.Kind = "ClusterProvisioner"
+	}
+	p.Ref.APIVersion = strings.ToLower(strings.TrimSpace(p.Ref.APIVersion))
+	if p.Ref.APIVersion == SchemeGroupVersion.Version {
+		p.Ref.APIVersion = SchemeGroupVersion.String()
+	}
+}
+
+// String returns the reference as "apiVersion/kind/name", or "kind/name" if it has no apiVersion,
+// for logging.
+func (p *ProvisionerReference) String() string {
+	if p == nil || p.Ref == nil {
+		return ""
+	}
+	if p.Ref.APIVersion == "" {
+		return p.Ref.Kind + "/" + p.Ref.Name
+	}
+	return p.Ref.APIVersion + "/" + p.Ref.Kind + "/" + p.Ref.Name
+}
+
+// Equal returns true if both references select the same Provisioner, i.e. their apiVersion, kind,
+// namespace and name are the same. Other fields of the references are ignored.
+func (p ProvisionerReference) Equal(other ProvisionerReference) bool {
+	if p.Ref == nil || other.Ref == nil {
+		return p.Ref == nil && other.Ref == nil
+	}
+	return p.Ref.APIVersion == other.Ref.APIVersion &&
+		p.Ref.Kind == other.Ref.Kind &&
+		p.Ref.Namespace == other.Ref.Namespace &&
+		p.Ref.Name == other.Ref.Name
+}
+
+// Validate rejects a reference whose apiVersion is not a valid group/version.
+func (p *ProvisionerReference) Validate() *apis.FieldError {
+	if p.Ref == nil || p.Ref.APIVersion == "" {
+		return nil
+	}
+	var msgs []string
+	if gv, err := schema.ParseGroupVersion(p.Ref.APIVersion); err != nil {
+		msgs = append(msgs, err.Error())
+	} else {
+		if gv.Group != "" {
+			msgs = append(msgs, validation.IsDNS1123Subdomain(gv.Group)...)
+		}
+		msgs = append(msgs, validation.IsDNS1123Label(gv.Version)...)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	fe := apis.ErrInvalidValue(p.Ref.APIVersion, "ref.apiVersion")
+	fe.Details = strings.Join(msgs, ", ")
+	return fe
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestProvisionerReferenceSetDefaults(t *testing.T) {
+	testCases := map[string]struct {
+		ref  *corev1.ObjectReference
+		want *corev1.ObjectReference
+	}{
+		"nil ref": {},
+		"trailing whitespace": {
+			ref: &corev1.ObjectReference{
+				APIVersion: " eventing.knative.dev/v1alpha1 ",
+				Kind:       "ClusterProvisioner\t",
+				Name:       "kafka\n",
+			},
+			want: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "ClusterProvisioner",
+				Name:       "kafka",
+			},
+		},
+		"short apiVersion and lower-case kind": {
+			ref: &corev1.ObjectReference{
+				APIVersion: "v1alpha1",
+				Kind:       "clusterprovisioner",
+				Name:       "kafka",
+			},
+			want: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "ClusterProvisioner",
+				Name:       "kafka",
+			},
+		},
+		"mixed-case apiVersion": {
+			ref: &corev1.ObjectReference{
+				APIVersion: "Eventing.Knative.dev/V1alpha1",
+				Name:       "kafka",
+			},
+			want: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Name:       "kafka",
+			},
+		},
+		"already canonical": {
+			ref: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "ClusterProvisioner",
+				Name:       "kafka",
+			},
+			want: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "ClusterProvisioner",
+				Name:       "kafka",
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			p := &ProvisionerReference{Ref: tc.ref}
+			p.SetDefaults()
+			if diff := cmp.Diff(tc.want, p.Ref); diff != "" {
+				t.Errorf("unexpected ref (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestProvisionerReferenceValidate(t *testing.T) {
+	testCases := map[string]struct {
+		apiVersion string
+		want       *apis.FieldError
+	}{
+		"omitted": {},
+		"canonical": {
+			apiVersion: "eventing.knative.dev/v1alpha1",
+		},
+		"too many slashes": {
+			apiVersion: "eventing.knative.dev/v1alpha1/extra",
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("eventing.knative.dev/v1alpha1/extra", "ref.apiVersion")
+				fe.Details = "unexpected GroupVersion string: eventing.knative.dev/v1alpha1/extra"
+				return fe
+			}(),
+		},
+		"invalid group": {
+			apiVersion: "eventing knative/v1alpha1",
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("eventing knative/v1alpha1", "ref.apiVersion")
+				fe.Details = "a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')"
+				return fe
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			p := &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					APIVersion: tc.apiVersion,
+					Name:       "kafka",
+				},
+			}
+			got := p.Validate()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestNewProvisionerReference(t *testing.T) {
+	testCases := map[string]struct {
+		apiVersion string
+		kind       string
+		name       string
+		want       *ProvisionerReference
+		wantErr    string
+	}{
+		"valid": {
+			apiVersion: "eventing.knative.dev/v1alpha1",
+			kind:       "ClusterProvisioner",
+			name:       "kafka",
+			want: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					APIVersion: "eventing.knative.dev/v1alpha1",
+					Kind:       "ClusterProvisioner",
+					Name:       "kafka",
+				},
+			},
+		},
+		"normalized": {
+			apiVersion: " v1alpha1 ",
+			kind:       "clusterprovisioner",
+			name:       "kafka ",
+			want: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					APIVersion: "eventing.knative.dev/v1alpha1",
+					Kind:       "ClusterProvisioner",
+					Name:       "kafka",
+				},
+			},
+		},
+		"empty kind": {
+			apiVersion: "eventing.knative.dev/v1alpha1",
+			name:       "kafka",
+			wantErr:    apis.ErrMissingField("ref.kind").Error(),
+		},
+		"empty name": {
+			apiVersion: "eventing.knative.dev/v1alpha1",
+			kind:       "ClusterProvisioner",
+			wantErr:    apis.ErrMissingField("ref.name").Error(),
+		},
+		"empty apiVersion": {
+			kind:    "ClusterProvisioner",
+			name:    "kafka",
+			wantErr: apis.ErrMissingField("ref.apiVersion").Error(),
+		},
+		"unparseable apiVersion": {
+			apiVersion: "eventing.knative.dev/v1alpha1/extra",
+			kind:       "ClusterProvisioner",
+			name:       "kafka",
+			wantErr: func() string {
+				fe := apis.ErrInvalidValue("eventing.knative.dev/v1alpha1/extra", "ref.apiVersion")
+				fe.Details = "unexpected GroupVersion string: eventing.knative.dev/v1alpha1/extra"
+				return fe.Error()
+			}(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewProvisionerReference(tc.apiVersion, tc.kind, tc.name)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+				t.Errorf("unexpected error (-want, +got) = %v", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected reference (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestProvisionerReferenceString(t *testing.T) {
+	testCases := map[string]struct {
+		ref  *ProvisionerReference
+		want string
+	}{
+		"with apiVersion": {
+			ref: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					APIVersion: "eventing.knative.dev/v1alpha1",
+					Kind:       "ClusterProvisioner",
+					Name:       "kafka",
+				},
+			},
+			want: "eventing.knative.dev/v1alpha1/ClusterProvisioner/kafka",
+		},
+		"without apiVersion": {
+			ref: &ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Kind: "ClusterProvisioner",
+					Name: "kafka",
+				},
+			},
+			want: "ClusterProvisioner/kafka",
+		},
+		"nil ref": {
+			ref:  &ProvisionerReference{},
+			want: "",
+		},
+		"nil": {
+			want: "",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.ref.String(); got != tc.want {
+				t.Errorf("unexpected string. want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestProvisionerReferenceEqual(t *testing.T) {
+	ref := func(mutate func(*corev1.ObjectReference)) ProvisionerReference {
+		r := &corev1.ObjectReference{
+			APIVersion: "eventing.knative.dev/v1alpha1",
+			Kind:       "ClusterProvisioner",
+			Namespace:  "knative-eventing",
+			Name:       "kafka",
+		}
+		mutate(r)
+		return ProvisionerReference{Ref: r}
+	}
+	original := ref(func(*corev1.ObjectReference) {})
+	testCases := map[string]struct {
+		other ProvisionerReference
+		want  bool
+	}{
+		"same": {
+			other: ref(func(*corev1.ObjectReference) {}),
+			want:  true,
+		},
+		"different apiVersion": {
+			other: ref(func(r *corev1.ObjectReference) { r.APIVersion = "eventing.knative.dev/v1beta1" }),
+		},
+		"different kind": {
+			other: ref(func(r *corev1.ObjectReference) { r.Kind = "Provisioner" }),
+		},
+		"different namespace": {
+			other: ref(func(r *corev1.ObjectReference) { r.Namespace = "default" }),
+		},
+		"different name": {
+			other: ref(func(r *corev1.ObjectReference) { r.Name = "in-memory" }),
+		},
+		"different uid and resourceVersion": {
+			other: ref(func(r *corev1.ObjectReference) {
+				r.UID = "abc-123"
+				r.ResourceVersion = "42"
+			}),
+			want: true,
+		},
+		"nil ref": {
+			other: ProvisionerReference{},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := original.Equal(tc.other); got != tc.want {
+				t.Errorf("unexpected Equal. want %v, got %v", tc.want, got)
+			}
+			if got := tc.other.Equal(original); got != tc.want {
+				t.Errorf("unexpected reversed Equal. want %v, got %v", tc.want, got)
+			}
+		})
+	}
+	if !(ProvisionerReference{}).Equal(ProvisionerReference{}) {
+		t.Errorf("expected references without a ref to be equal")
+	}
+}
@@ -45,12 +45,16 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&Broker{},
+		&BrokerList{},
 		&Channel{},
 		&ChannelList{},
 		&ClusterProvisioner{},
 		&ClusterProvisionerList{},
 		&Subscription{},
 		&SubscriptionList{},
+		&Trigger{},
+		&TriggerList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
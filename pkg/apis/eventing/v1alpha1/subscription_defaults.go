@@ -16,10 +16,34 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultBackoffDelay is the delay before the first retry of a Subscription
+// whose delivery doesn't specify one.
+var DefaultBackoffDelay = time.Second
+
 func (s *Subscription) SetDefaults() {
 	s.Spec.SetDefaults()
 }
 
 func (ss *SubscriptionSpec) SetDefaults() {
-	// TODO anything?
+	if ss.DeliveryOrder == "" {
+		ss.DeliveryOrder = DeliveryOrderUnordered
+	}
+	if ss.Delivery != nil {
+		ss.Delivery.SetDefaults()
+	}
+}
+
+func (ds *DeliverySpec) SetDefaults() {
+	if ds.BackoffPolicy == "" {
+		ds.BackoffPolicy = BackoffPolicyExponential
+	}
+	if ds.BackoffDelay == nil {
+		ds.BackoffDelay = &metav1.Duration{Duration: DefaultBackoffDelay}
+	}
 }
@@ -16,10 +16,82 @@ limitations under the License.
 
 package v1alpha1
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
-// No-op test because method does nothing.
 func TestSubscriptionDefaults(t *testing.T) {
-	s := Subscription{}
-	s.SetDefaults()
+	testCases := map[string]struct {
+		initial  DeliveryOrder
+		expected DeliveryOrder
+	}{
+		"unset": {
+			initial:  "",
+			expected: DeliveryOrderUnordered,
+		},
+		"unordered": {
+			initial:  DeliveryOrderUnordered,
+			expected: DeliveryOrderUnordered,
+		},
+		"ordered": {
+			initial:  DeliveryOrderOrdered,
+			expected: DeliveryOrderOrdered,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s := Subscription{
+				Spec: SubscriptionSpec{
+					DeliveryOrder: tc.initial,
+				},
+			}
+			s.SetDefaults()
+			if s.Spec.DeliveryOrder != tc.expected {
+				t.Errorf("unexpected DeliveryOrder: want %q, got %q", tc.expected, s.Spec.DeliveryOrder)
+			}
+		})
+	}
+}
+
+func TestDeliverySpecDefaults(t *testing.T) {
+	testCases := map[string]struct {
+		initial  *DeliverySpec
+		expected *DeliverySpec
+	}{
+		"nil": {},
+		"empty": {
+			initial: &DeliverySpec{},
+			expected: &DeliverySpec{
+				BackoffPolicy: BackoffPolicyExponential,
+				BackoffDelay:  &metav1.Duration{Duration: DefaultBackoffDelay},
+			},
+		},
+		"set": {
+			initial: &DeliverySpec{
+				BackoffPolicy: BackoffPolicyLinear,
+				BackoffDelay:  &metav1.Duration{Duration: 100 * time.Millisecond},
+			},
+			expected: &DeliverySpec{
+				BackoffPolicy: BackoffPolicyLinear,
+				BackoffDelay:  &metav1.Duration{Duration: 100 * time.Millisecond},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s := Subscription{
+				Spec: SubscriptionSpec{
+					Delivery: tc.initial,
+				},
+			}
+			s.SetDefaults()
+			if diff := cmp.Diff(tc.expected, s.Spec.Delivery); diff != "" {
+				t.Errorf("unexpected Delivery (-want, +got) = %v", diff)
+			}
+		})
+	}
 }
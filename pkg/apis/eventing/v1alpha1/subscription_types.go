@@ -17,6 +17,8 @@
 package v1alpha1
 
 import (
+	"strings"
+
 	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/webhook"
@@ -99,6 +101,90 @@ type SubscriptionSpec struct {
 	// the Call target.
 	// +optional
 	Result *ResultStrategy `json:"result,omitempty"`
+
+	// DeliveryOrder specifies whether events from the From channel must be
+	// delivered to the Call target in the order they were received. Defaults
+	// to DeliveryOrderUnordered.
+	// +optional
+	DeliveryOrder DeliveryOrder `json:"deliveryOrder,omitempty"`
+
+	// Concurrency is the maximum number of events delivered to the Call
+	// target at the same time. If unset, the From channel's
+	// defaultConcurrency argument applies.
+	// +optional
+	Concurrency *int32 `json:"concurrency,omitempty"`
+
+	// DeadLetterSink is where events are sent to when they could not be
+	// delivered to the Call target, or their result could not be sent to
+	// the Result target. If unset, such events are dropped.
+	// +optional
+	DeadLetterSink *DeadLetterSink `json:"deadLetterSink,omitempty"`
+
+	// Delivery specifies how failed deliveries to the Call target and the
+	// Result target are retried. If unset, each delivery is attempted once.
+	// +optional
+	Delivery *DeliverySpec `json:"delivery,omitempty"`
+}
+
+// DeliverySpec specifies how a Subscription retries failed deliveries.
+type DeliverySpec struct {
+	// Retry is the number of times a failed delivery is retried, before the
+	// event is sent to the DeadLetterSink or dropped.
+	// +optional
+	Retry *int32 `json:"retry,omitempty"`
+
+	// BackoffPolicy is how the delay before each retry grows. Defaults to
+	// BackoffPolicyExponential.
+	// +optional
+	BackoffPolicy BackoffPolicyType `json:"backoffPolicy,omitempty"`
+
+	// BackoffDelay is the delay before the first retry, e.g. "500ms".
+	// Defaults to DefaultBackoffDelay.
+	// +optional
+	BackoffDelay *metav1.Duration `json:"backoffDelay,omitempty"`
+}
+
+// BackoffPolicyType is how the delay before each retry of a delivery grows.
+type BackoffPolicyType string
+
+const (
+	// BackoffPolicyLinear waits BackoffDelay times the number of the retry,
+	// e.g. 1s, 2s, 3s.
+	BackoffPolicyLinear BackoffPolicyType = "linear"
+
+	// BackoffPolicyExponential doubles the delay for each retry, e.g. 1s,
+	// 2s, 4s.
+	BackoffPolicyExponential BackoffPolicyType = "exponential"
+)
+
+// DeliveryOrder is the order in which a Subscription delivers events.
+type DeliveryOrder string
+
+const (
+	// DeliveryOrderUnordered allows events to be delivered concurrently and in
+	// any order.
+	DeliveryOrderUnordered DeliveryOrder = "unordered"
+
+	// DeliveryOrderOrdered delivers events one at a time, in the order they
+	// were received by the From channel.
+	DeliveryOrderOrdered DeliveryOrder = "ordered"
+)
+
+// EffectiveConcurrency returns the Subscription's Concurrency if it is set, and
+// otherwise the defaultConcurrency argument of the Channel it is from. It
+// returns nil if neither is set, or if the Channel's arguments can't be decoded.
+func (s *Subscription) EffectiveConcurrency(from *Channel) *int32 {
+	if s.Spec.Concurrency != nil {
+		return s.Spec.Concurrency
+	}
+	if from == nil {
+		return nil
+	}
+	ca, fe := decodeChannelArguments(from.Spec.Arguments)
+	if fe != nil || ca == nil {
+		return nil
+	}
+	return ca.DefaultConcurrency
 }
 
 // Callable specifies the reference to an object that's expected to
@@ -143,6 +229,12 @@ type Callable struct {
 	// http://myexternalhandler.example.com/foo/bar
 	// +optional
 	TargetURI *string `json:"targetURI,omitempty"`
+
+	// Selector selects the Kubernetes Services in the Subscription's
+	// namespace that events are delivered to. Every matching Service
+	// becomes a subscriber, and the set is refreshed as Services change.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
 // ResultStrategy specifies the handling of the Callable's returned result. If
@@ -160,6 +252,25 @@ type ResultStrategy struct {
 	Target *corev1.ObjectReference `json:"target,omitempty"`
 }
 
+// DeadLetterSink specifies where a Subscription sends the events it failed to
+// deliver. Exactly one of Target and TargetURI must be set.
+type DeadLetterSink struct {
+	// Reference to an object that will be used to find the dead-letter
+	// endpoint. This object must fulfill the Sinkable contract.
+	//
+	// You can specify only the following fields of the ObjectReference:
+	//   - Kind
+	//   - APIVersion
+	//   - Name
+	// +optional
+	Target *corev1.ObjectReference `json:"target,omitempty"`
+
+	// Reference to a 'known' endpoint where no resolving is done.
+	// http://k8s-service for example
+	// +optional
+	TargetURI *string `json:"targetURI,omitempty"`
+}
+
 // subCondSet is a condition set with Ready as the happy condition and
 // ReferencesResolved and FromReady as the dependent conditions.
 var subCondSet = duckv1alpha1.NewLivingConditionSet(SubscriptionConditionReferencesResolved, SubscriptionConditionFromReady)
@@ -174,6 +285,31 @@ type SubscriptionStatus struct {
 	// Subscription might be Subscribable. This depends if there's a Result channel
 	// In that case, this points to that resource.
 	Subscribable duckv1alpha1.Subscribable `json:"subscribable,omitempty"`
+
+	// PhysicalSubscription is the fully resolved values that this Subscription represents.
+	// +optional
+	PhysicalSubscription SubscriptionStatusPhysicalSubscription `json:"physicalSubscription,omitempty"`
+}
+
+// SubscriptionStatusPhysicalSubscription represents the fully resolved values for this
+// Subscription.
+type SubscriptionStatusPhysicalSubscription struct {
+	// SubscriberURI is the fully resolved URI for spec.call.
+	// +optional
+	SubscriberURI string `json:"subscriberURI,omitempty"`
+
+	// SubscriberURIs are the fully resolved URIs of the Services matched by
+	// spec.call.selector.
+	// +optional
+	SubscriberURIs []string `json:"subscriberURIs,omitempty"`
+
+	// ReplyURI is the fully resolved URI for spec.result.
+	// +optional
+	ReplyURI string `json:"replyURI,omitempty"`
+
+	// DeadLetterSinkURI is the fully resolved URI for spec.deadLetterSink.
+	// +optional
+	DeadLetterSinkURI string `json:"deadLetterSinkURI,omitempty"`
 }
 
 const (
@@ -209,6 +345,56 @@ func (ss *SubscriptionStatus) MarkReferencesResolved() {
 	subCondSet.Manage(ss).MarkTrue(SubscriptionConditionReferencesResolved)
 }
 
+// SetPhysicalSubscription records the resolved URIs this Subscription dispatches to. The
+// ReferencesResolved condition is set to True only if every URI the spec requires resolved: the
+// subscriber's if it sets a call, and the reply's if it sets a result. Otherwise it is set to False.
+func (s *Subscription) SetPhysicalSubscription(subscriberURI, replyURI string) {
+	s.Status.PhysicalSubscription = SubscriptionStatusPhysicalSubscription{
+		SubscriberURI: subscriberURI,
+		ReplyURI:      replyURI,
+	}
+	var missing []string
+	if s.Spec.Call != nil && subscriberURI == "" {
+		missing = append(missing, "subscriber")
+	}
+	if s.Spec.Result != nil && replyURI == "" {
+		missing = append(missing, "reply")
+	}
+	switch {
+	case len(missing) > 0:
+		subCondSet.Manage(&s.Status).MarkFalse(SubscriptionConditionReferencesResolved, "emptyURIs", "the %s URI did not resolve", strings.Join(missing, " and "))
+	case subscriberURI == "" && replyURI == "":
+		subCondSet.Manage(&s.Status).MarkFalse(SubscriptionConditionReferencesResolved, "emptyURIs", "neither the subscriber nor the reply URI resolved")
+	default:
+		subCondSet.Manage(&s.Status).MarkTrue(SubscriptionConditionReferencesResolved)
+	}
+}
+
+// SetSelectedPhysicalSubscription records the resolved URIs of the Services matched by the call's
+// selector and of the result. A selector matching no Services is still resolved, so the
+// ReferencesResolved condition is set to True.
+func (ss *SubscriptionStatus) SetSelectedPhysicalSubscription(subscriberURIs []string, replyURI string) {
+	ss.PhysicalSubscription = SubscriptionStatusPhysicalSubscription{
+		SubscriberURIs: subscriberURIs,
+		ReplyURI:       replyURI,
+	}
+	subCondSet.Manage(ss).MarkTrue(SubscriptionConditionReferencesResolved)
+}
+
+// SetDeadLetterSinkURI records the resolved URI of the Subscription's dead-letter sink. Call it
+// after Subscription.SetPhysicalSubscription or SetSelectedPhysicalSubscription, as they reset the
+// URI.
+func (ss *SubscriptionStatus) SetDeadLetterSinkURI(uri string) {
+	ss.PhysicalSubscription.DeadLetterSinkURI = uri
+}
+
+// MarkReplyChainTooDeep sets the ReferencesResolved condition to False state, because following
+// the result leads to a reply chain deeper than allowed.
+func (ss *SubscriptionStatus) MarkReplyChainTooDeep(depth, max int) {
+	subCondSet.Manage(ss).MarkFalse(SubscriptionConditionReferencesResolved, "ReplyChainTooDeep",
+		"the reply chain is at least %d deep, more than the maximum of %d", depth, max)
+}
+
 // MarkFromReady sets the FromReady condition to True state.
 func (ss *SubscriptionStatus) MarkFromReady() {
 	subCondSet.Manage(ss).MarkTrue(SubscriptionConditionFromReady)
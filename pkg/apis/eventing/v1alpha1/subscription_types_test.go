@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 var subscriptionConditionReady = duckv1alpha1.Condition{
@@ -213,3 +214,187 @@ func TestSubscriptionIsReady(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscription_SetPhysicalSubscription(t *testing.T) {
+	call := &Callable{Target: &corev1.ObjectReference{Name: "subscriber"}}
+	result := &ResultStrategy{Target: &corev1.ObjectReference{Name: "reply-channel"}}
+	testCases := map[string]struct {
+		call          *Callable
+		result        *ResultStrategy
+		subscriberURI string
+		replyURI      string
+		want          *SubscriptionStatus
+	}{
+		"nothing resolved": {
+			call: call,
+			want: &SubscriptionStatus{
+				Conditions: []duckv1alpha1.Condition{{
+					Type:    SubscriptionConditionReady,
+					Status:  corev1.ConditionFalse,
+					Message: "the subscriber URI did not resolve",
+				}, {
+					Type:    SubscriptionConditionReferencesResolved,
+					Status:  corev1.ConditionFalse,
+					Message: "the subscriber URI did not resolve",
+				}},
+			},
+		},
+		"subscriber only": {
+			call:          call,
+			subscriberURI: "subscriber.test-namespace.svc.cluster.local",
+			want: &SubscriptionStatus{
+				PhysicalSubscription: SubscriptionStatusPhysicalSubscription{
+					SubscriberURI: "subscriber.test-namespace.svc.cluster.local",
+				},
+				Conditions: []duckv1alpha1.Condition{{
+					Type:   SubscriptionConditionReferencesResolved,
+					Status: corev1.ConditionTrue,
+				}},
+			},
+		},
+		"subscriber and reply": {
+			call:          call,
+			result:        result,
+			subscriberURI: "subscriber.test-namespace.svc.cluster.local",
+			replyURI:      "reply-channel.test-namespace.svc.cluster.local",
+			want: &SubscriptionStatus{
+				PhysicalSubscription: SubscriptionStatusPhysicalSubscription{
+					SubscriberURI: "subscriber.test-namespace.svc.cluster.local",
+					ReplyURI:      "reply-channel.test-namespace.svc.cluster.local",
+				},
+				Conditions: []duckv1alpha1.Condition{{
+					Type:   SubscriptionConditionReferencesResolved,
+					Status: corev1.ConditionTrue,
+				}},
+			},
+		},
+		"reply only": {
+			result:   result,
+			replyURI: "reply-channel.test-namespace.svc.cluster.local",
+			want: &SubscriptionStatus{
+				PhysicalSubscription: SubscriptionStatusPhysicalSubscription{
+					ReplyURI: "reply-channel.test-namespace.svc.cluster.local",
+				},
+				Conditions: []duckv1alpha1.Condition{{
+					Type:   SubscriptionConditionReferencesResolved,
+					Status: corev1.ConditionTrue,
+				}},
+			},
+		},
+		"subscriber not resolved": {
+			call:     call,
+			result:   result,
+			replyURI: "reply-channel.test-namespace.svc.cluster.local",
+			want: &SubscriptionStatus{
+				PhysicalSubscription: SubscriptionStatusPhysicalSubscription{
+					ReplyURI: "reply-channel.test-namespace.svc.cluster.local",
+				},
+				Conditions: []duckv1alpha1.Condition{{
+					Type:    SubscriptionConditionReady,
+					Status:  corev1.ConditionFalse,
+					Message: "the subscriber URI did not resolve",
+				}, {
+					Type:    SubscriptionConditionReferencesResolved,
+					Status:  corev1.ConditionFalse,
+					Message: "the subscriber URI did not resolve",
+				}},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s := &Subscription{Spec: SubscriptionSpec{Call: tc.call, Result: tc.result}}
+			s.SetPhysicalSubscription(tc.subscriberURI, tc.replyURI)
+			ignore := cmpopts.IgnoreFields(duckv1alpha1.Condition{}, "LastTransitionTime", "Reason")
+			if diff := cmp.Diff(tc.want, &s.Status, ignore); diff != "" {
+				t.Errorf("unexpected status (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionStatus_SetDeadLetterSinkURI(t *testing.T) {
+	s := &Subscription{}
+	ss := &s.Status
+	s.SetPhysicalSubscription("subscriber.test-namespace.svc.cluster.local", "")
+	ss.SetDeadLetterSinkURI("dead-letter.test-namespace.svc.cluster.local")
+	want := SubscriptionStatusPhysicalSubscription{
+		SubscriberURI:     "subscriber.test-namespace.svc.cluster.local",
+		DeadLetterSinkURI: "dead-letter.test-namespace.svc.cluster.local",
+	}
+	if diff := cmp.Diff(want, ss.PhysicalSubscription); diff != "" {
+		t.Errorf("unexpected physical subscription (-want, +got) = %v", diff)
+	}
+
+	// Setting the physical subscription again resets the dead-letter sink.
+	s.SetPhysicalSubscription("subscriber.test-namespace.svc.cluster.local", "")
+	if ss.PhysicalSubscription.DeadLetterSinkURI != "" {
+		t.Errorf("expected the dead-letter sink URI to be reset, got %q", ss.PhysicalSubscription.DeadLetterSinkURI)
+	}
+}
+
+func TestSubscriptionStatus_MarkReplyChainTooDeep(t *testing.T) {
+	ss := &SubscriptionStatus{}
+	ss.InitializeConditions()
+	ss.MarkReplyChainTooDeep(11, 10)
+	c := ss.GetCondition(SubscriptionConditionReferencesResolved)
+	if c == nil || c.Status != corev1.ConditionFalse || c.Reason != "ReplyChainTooDeep" {
+		t.Fatalf("unexpected ReferencesResolved condition: %+v", c)
+	}
+	if want := "the reply chain is at least 11 deep, more than the maximum of 10"; c.Message != want {
+		t.Errorf("unexpected message: want %q, got %q", want, c.Message)
+	}
+	if ss.IsReady() {
+		t.Errorf("expected the Subscription not to be ready")
+	}
+}
+
+func TestSubscription_EffectiveConcurrency(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	channel := func(args string) *Channel {
+		c := &Channel{}
+		if args != "" {
+			c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(args)}
+		}
+		return c
+	}
+	testCases := map[string]struct {
+		concurrency *int32
+		from        *Channel
+		want        *int32
+	}{
+		"inherited from the channel": {
+			from: channel(`{"defaultConcurrency":4}`),
+			want: int32Ptr(4),
+		},
+		"overridden by the subscription": {
+			concurrency: int32Ptr(1),
+			from:        channel(`{"defaultConcurrency":4}`),
+			want:        int32Ptr(1),
+		},
+		"set only on the subscription": {
+			concurrency: int32Ptr(2),
+			from:        channel(""),
+			want:        int32Ptr(2),
+		},
+		"unset": {
+			from: channel(`{"topic":"orders"}`),
+		},
+		"no channel": {},
+		"undecodable channel arguments": {
+			from: channel(`{"defaultConcurrency":"many"}`),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s := &Subscription{
+				Spec: SubscriptionSpec{
+					Concurrency: tc.concurrency,
+				},
+			}
+			if diff := cmp.Diff(tc.want, s.EffectiveConcurrency(tc.from)); diff != "" {
+				t.Errorf("unexpected concurrency (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -17,13 +17,30 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/knative/pkg/apis"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// ReplyKinds are the kinds a Subscription's result may target. Replies are sent on to the target,
+// so it must implement Channelable, or the events would reach a dead end.
+var ReplyKinds = sets.NewString("Channel")
+
+// MaxDeliveryRetry is the maximum number of times a Subscription may retry a failed delivery.
+var MaxDeliveryRetry int32 = 10
+
+// MaxBackoffDelay is the longest delay a Subscription may wait before its first retry.
+var MaxBackoffDelay = 10 * time.Second
+
 func (s *Subscription) Validate() *apis.FieldError {
 	return s.Spec.Validate().ViaField("spec")
 }
@@ -60,12 +77,34 @@ func (ss *SubscriptionSpec) Validate() *apis.FieldError {
 		}
 	}
 
+	switch ss.DeliveryOrder {
+	case "", DeliveryOrderUnordered, DeliveryOrderOrdered:
+	default:
+		fe := apis.ErrInvalidValue(string(ss.DeliveryOrder), "deliveryOrder")
+		fe.Details = fmt.Sprintf("must be one of %q or %q", DeliveryOrderUnordered, DeliveryOrderOrdered)
+		errs = errs.Also(fe)
+	}
+
+	if ss.DeadLetterSink != nil {
+		errs = errs.Also(isValidDeadLetterSink(*ss.DeadLetterSink).ViaField("deadLetterSink"))
+	}
+
+	if ss.Delivery != nil {
+		errs = errs.Also(ss.Delivery.Validate().ViaField("delivery"))
+	}
+
+	if ss.Concurrency != nil && *ss.Concurrency < 1 {
+		fe := apis.ErrInvalidValue(strconv.Itoa(int(*ss.Concurrency)), "concurrency")
+		fe.Details = "must be at least 1"
+		errs = errs.Also(fe)
+	}
+
 	return errs
 }
 
 func isCallableNilOrEmpty(c *Callable) bool {
 	return c == nil || equality.Semantic.DeepEqual(c, &Callable{}) ||
-		(equality.Semantic.DeepEqual(c.Target, &corev1.ObjectReference{}) && c.TargetURI == nil)
+		(equality.Semantic.DeepEqual(c.Target, &corev1.ObjectReference{}) && c.TargetURI == nil && c.Selector == nil)
 
 }
 
@@ -75,6 +114,19 @@ func isValidCallable(c Callable) *apis.FieldError {
 		errs = errs.Also(apis.ErrMultipleOneOf("target", "targetURI"))
 	}
 
+	if c.Selector != nil {
+		if (c.TargetURI != nil && *c.TargetURI != "") || (c.Target != nil && !equality.Semantic.DeepEqual(c.Target, &corev1.ObjectReference{})) {
+			errs = errs.Also(apis.ErrMultipleOneOf("target", "targetURI", "selector"))
+		}
+		if _, err := metav1.LabelSelectorAsSelector(c.Selector); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "Invalid selector",
+				Paths:   []string{"selector"},
+				Details: err.Error(),
+			})
+		}
+	}
+
 	// If Target given, check the fields.
 	if c.Target != nil && !equality.Semantic.DeepEqual(c.Target, &corev1.ObjectReference{}) {
 		fe := isValidObjectReference(*c.Target)
@@ -85,6 +137,55 @@ func isValidCallable(c Callable) *apis.FieldError {
 	return errs
 }
 
+func isValidDeadLetterSink(d DeadLetterSink) *apis.FieldError {
+	hasTarget := d.Target != nil && !equality.Semantic.DeepEqual(d.Target, &corev1.ObjectReference{})
+	hasTargetURI := d.TargetURI != nil && *d.TargetURI != ""
+	if hasTarget && hasTargetURI {
+		return apis.ErrMultipleOneOf("target", "targetURI")
+	}
+	if !hasTarget && !hasTargetURI {
+		return apis.ErrMissingOneOf("target", "targetURI")
+	}
+	if hasTarget {
+		if fe := isValidObjectReference(*d.Target); fe != nil {
+			return fe.ViaField("target")
+		}
+	}
+	return nil
+}
+
+func (ds *DeliverySpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ds.Retry != nil && *ds.Retry < 0 {
+		fe := apis.ErrInvalidValue(strconv.Itoa(int(*ds.Retry)), "retry")
+		fe.Details = "must not be negative"
+		errs = errs.Also(fe)
+	} else if ds.Retry != nil && *ds.Retry > MaxDeliveryRetry {
+		fe := apis.ErrInvalidValue(strconv.Itoa(int(*ds.Retry)), "retry")
+		fe.Details = fmt.Sprintf("must not be greater than %d", MaxDeliveryRetry)
+		errs = errs.Also(fe)
+	}
+
+	switch ds.BackoffPolicy {
+	case "", BackoffPolicyLinear, BackoffPolicyExponential:
+	default:
+		fe := apis.ErrInvalidValue(string(ds.BackoffPolicy), "backoffPolicy")
+		fe.Details = fmt.Sprintf("must be one of %q or %q", BackoffPolicyLinear, BackoffPolicyExponential)
+		errs = errs.Also(fe)
+	}
+
+	if ds.BackoffDelay != nil {
+		if fe := validateDuration(*ds.BackoffDelay); fe != nil {
+			errs = errs.Also(fe.ViaField("backoffDelay"))
+		} else if ds.BackoffDelay.Duration > MaxBackoffDelay {
+			fe := apis.ErrInvalidValue(ds.BackoffDelay.Duration.String(), "backoffDelay")
+			fe.Details = fmt.Sprintf("must not be longer than %v", MaxBackoffDelay)
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
+
 func isFromEmpty(f corev1.ObjectReference) bool {
 	return isSubscribableEmpty(f)
 }
@@ -106,10 +207,13 @@ func isValidResultStrategy(r ResultStrategy) *apis.FieldError {
 	if fe != nil {
 		return fe.ViaField("target")
 	}
-	if r.Target.Kind != "Channel" {
+	if !ReplyKinds.Has(r.Target.Kind) {
+		kinds := ReplyKinds.List()
+		for i, k := range kinds {
+			kinds[i] = fmt.Sprintf("'%s'", k)
+		}
 		fe := apis.ErrInvalidValue(r.Target.Kind, "kind")
-		fe.Paths = []string{"kind"}
-		fe.Details = "only 'Channel' kind is allowed"
+		fe.Details = fmt.Sprintf("only %s kind is allowed", strings.Join(kinds, " or "))
 		return fe
 	}
 	if r.Target.APIVersion != "eventing.knative.dev/v1alpha1" {
@@ -129,8 +233,8 @@ func (current *Subscription) CheckImmutableFields(og apis.Immutable) *apis.Field
 		return nil
 	}
 
-	// Only Call and Result are mutable.
-	ignoreArguments := cmpopts.IgnoreFields(SubscriptionSpec{}, "Call", "Result")
+	// Only Call, Result, DeliveryOrder, Concurrency, DeadLetterSink and Delivery are mutable.
+	ignoreArguments := cmpopts.IgnoreFields(SubscriptionSpec{}, "Call", "Result", "DeliveryOrder", "Concurrency", "DeadLetterSink", "Delivery")
 	if diff := cmp.Diff(original.Spec, current.Spec, ignoreArguments); diff != "" {
 		return &apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
@@ -17,10 +17,13 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/knative/pkg/apis"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const (
@@ -227,6 +230,111 @@ func TestSubscriptionSpecValidation(t *testing.T) {
 			fe := apis.ErrMissingField("result.target.name")
 			return fe
 		}(),
+	}, {
+		name: "ordered delivery",
+		c: &SubscriptionSpec{
+			From:          getValidFromRef(),
+			Call:          getValidCall(),
+			DeliveryOrder: DeliveryOrderOrdered,
+		},
+		want: nil,
+	}, {
+		name: "unordered delivery",
+		c: &SubscriptionSpec{
+			From:          getValidFromRef(),
+			Call:          getValidCall(),
+			DeliveryOrder: DeliveryOrderUnordered,
+		},
+		want: nil,
+	}, {
+		name: "invalid delivery order",
+		c: &SubscriptionSpec{
+			From:          getValidFromRef(),
+			Call:          getValidCall(),
+			DeliveryOrder: "sorted",
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("sorted", "deliveryOrder")
+			fe.Details = `must be one of "unordered" or "ordered"`
+			return fe
+		}(),
+	}, {
+		name: "invalid concurrency",
+		c: &SubscriptionSpec{
+			From:        getValidFromRef(),
+			Call:        getValidCall(),
+			Concurrency: func() *int32 { c := int32(0); return &c }(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("0", "concurrency")
+			fe.Details = "must be at least 1"
+			return fe
+		}(),
+	}, {
+		name: "valid dead-letter sink",
+		c: &SubscriptionSpec{
+			From:           getValidFromRef(),
+			Call:           getValidCall(),
+			DeadLetterSink: getValidDeadLetterSink(),
+		},
+		want: nil,
+	}, {
+		name: "empty dead-letter sink",
+		c: &SubscriptionSpec{
+			From:           getValidFromRef(),
+			Call:           getValidCall(),
+			DeadLetterSink: &DeadLetterSink{},
+		},
+		want: apis.ErrMissingOneOf("deadLetterSink.target", "deadLetterSink.targetURI"),
+	}, {
+		name: "valid delivery",
+		c: &SubscriptionSpec{
+			From: getValidFromRef(),
+			Call: getValidCall(),
+			Delivery: &DeliverySpec{
+				Retry:         func() *int32 { r := int32(3); return &r }(),
+				BackoffPolicy: BackoffPolicyLinear,
+				BackoffDelay:  &metav1.Duration{Duration: time.Second},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid delivery",
+		c: &SubscriptionSpec{
+			From: getValidFromRef(),
+			Call: getValidCall(),
+			Delivery: &DeliverySpec{
+				Retry:         func() *int32 { r := int32(-1); return &r }(),
+				BackoffPolicy: "random",
+				BackoffDelay:  &metav1.Duration{Duration: -time.Second},
+			},
+		},
+		want: func() *apis.FieldError {
+			retry := apis.ErrInvalidValue("-1", "delivery.retry")
+			retry.Details = "must not be negative"
+			policy := apis.ErrInvalidValue("random", "delivery.backoffPolicy")
+			policy.Details = `must be one of "linear" or "exponential"`
+			delay := apis.ErrInvalidValue("-1s", "delivery.backoffDelay")
+			delay.Details = "must not be negative"
+			return retry.Also(policy).Also(delay)
+		}(),
+	}, {
+		name: "delivery out of bounds",
+		c: &SubscriptionSpec{
+			From: getValidFromRef(),
+			Call: getValidCall(),
+			Delivery: &DeliverySpec{
+				Retry:        func() *int32 { r := int32(11); return &r }(),
+				BackoffDelay: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+		want: func() *apis.FieldError {
+			retry := apis.ErrInvalidValue("11", "delivery.retry")
+			retry.Details = "must not be greater than 10"
+			delay := apis.ErrInvalidValue("1m0s", "delivery.backoffDelay")
+			delay.Details = "must not be longer than 10s"
+			return retry.Also(delay)
+		}(),
 	}}
 
 	for _, test := range tests {
@@ -239,6 +347,16 @@ func TestSubscriptionSpecValidation(t *testing.T) {
 	}
 }
 
+func getValidDeadLetterSink() *DeadLetterSink {
+	return &DeadLetterSink{
+		Target: &corev1.ObjectReference{
+			Name:       "deadletterchannel",
+			Kind:       channelKind,
+			APIVersion: channelAPIVersion,
+		},
+	}
+}
+
 func TestSubscriptionImmutable(t *testing.T) {
 	newFrom := getValidFromRef()
 	newFrom.Name = "newFromChannel"
@@ -277,6 +395,20 @@ func TestSubscriptionImmutable(t *testing.T) {
 		},
 		og:   nil,
 		want: nil,
+	}, {
+		name: "valid, defaulted DeliveryOrder",
+		c: &Subscription{
+			Spec: SubscriptionSpec{
+				From:          getValidFromRef(),
+				DeliveryOrder: DeliveryOrderUnordered,
+			},
+		},
+		og: &Subscription{
+			Spec: SubscriptionSpec{
+				From: getValidFromRef(),
+			},
+		},
+		want: nil,
 	}, {
 		name: "valid, new Call",
 		c: &Subscription{
@@ -292,6 +424,40 @@ func TestSubscriptionImmutable(t *testing.T) {
 			},
 		},
 		want: nil,
+	}, {
+		name: "valid, new Delivery",
+		c: &Subscription{
+			Spec: SubscriptionSpec{
+				From: getValidFromRef(),
+				Call: getValidCall(),
+				Delivery: &DeliverySpec{
+					BackoffPolicy: BackoffPolicyLinear,
+				},
+			},
+		},
+		og: &Subscription{
+			Spec: SubscriptionSpec{
+				From: getValidFromRef(),
+				Call: getValidCall(),
+			},
+		},
+		want: nil,
+	}, {
+		name: "valid, new DeadLetterSink",
+		c: &Subscription{
+			Spec: SubscriptionSpec{
+				From:           getValidFromRef(),
+				Call:           getValidCall(),
+				DeadLetterSink: getValidDeadLetterSink(),
+			},
+		},
+		og: &Subscription{
+			Spec: SubscriptionSpec{
+				From: getValidFromRef(),
+				Call: getValidCall(),
+			},
+		},
+		want: nil,
 	}, {
 		name: "valid, new Result",
 		c: &Subscription{
@@ -600,6 +766,38 @@ func TestValidCallable(t *testing.T) {
 			},
 		},
 		want: nil,
+	}, {
+		name: "valid selector",
+		c: Callable{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "audit"},
+			},
+		},
+		want: nil,
+	}, {
+		name: "both selector and targetURI given",
+		c: Callable{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "audit"},
+			},
+			TargetURI: &targetURI,
+		},
+		want: apis.ErrMultipleOneOf("target", "targetURI", "selector"),
+	}, {
+		name: "invalid selector",
+		c: Callable{
+			Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "app",
+					Operator: "Near",
+				}},
+			},
+		},
+		want: &apis.FieldError{
+			Message: "Invalid selector",
+			Paths:   []string{"selector"},
+			Details: `"Near" is not a valid pod selector operator`,
+		},
 	}}
 
 	for _, test := range tests {
@@ -671,6 +869,20 @@ func TestValidResultStrategy(t *testing.T) {
 			fe.Details = "only 'Channel' kind is allowed"
 			return fe
 		}(),
+	}, {
+		name: "service reply",
+		c: ResultStrategy{
+			Target: &corev1.ObjectReference{
+				Name:       "reply-service",
+				APIVersion: "v1",
+				Kind:       "Service",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("Service", "kind")
+			fe.Details = "only 'Channel' kind is allowed"
+			return fe
+		}(),
 	}, {
 		name: "invalid apiVersion",
 		c: ResultStrategy{
@@ -723,3 +935,122 @@ func TestValidResultStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestValidDeadLetterSink(t *testing.T) {
+	targetURI := "http://deadletter.example.com/"
+	tests := []struct {
+		name string
+		c    DeadLetterSink
+		want *apis.FieldError
+	}{{
+		name: "valid target",
+		c:    *getValidDeadLetterSink(),
+		want: nil,
+	}, {
+		name: "valid targetURI",
+		c: DeadLetterSink{
+			TargetURI: &targetURI,
+		},
+		want: nil,
+	}, {
+		name: "both target and targetURI given",
+		c: DeadLetterSink{
+			Target:    getValidDeadLetterSink().Target,
+			TargetURI: &targetURI,
+		},
+		want: apis.ErrMultipleOneOf("target", "targetURI"),
+	}, {
+		name: "empty target",
+		c: DeadLetterSink{
+			Target: &corev1.ObjectReference{},
+		},
+		want: apis.ErrMissingOneOf("target", "targetURI"),
+	}, {
+		name: "missing name in target",
+		c: DeadLetterSink{
+			Target: &corev1.ObjectReference{
+				APIVersion: channelAPIVersion,
+				Kind:       channelKind,
+			},
+		},
+		want: apis.ErrMissingField("target.name"),
+	}, {
+		name: "extra field, namespace",
+		c: DeadLetterSink{
+			Target: &corev1.ObjectReference{
+				Name:       "deadletterchannel",
+				APIVersion: channelAPIVersion,
+				Kind:       channelKind,
+				Namespace:  "secretnamespace",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("target.Namespace")
+			fe.Details = "only name, apiVersion and kind are supported fields"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := isValidDeadLetterSink(test.c)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: isValidDeadLetterSink (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionValidation_ReplyKinds(t *testing.T) {
+	defer func(kinds sets.String) { ReplyKinds = kinds }(ReplyKinds)
+	ReplyKinds = sets.NewString("Channel", "KafkaChannel")
+
+	tests := []struct {
+		name   string
+		result *ResultStrategy
+		want   *apis.FieldError
+	}{{
+		name:   "channel reply",
+		result: getValidResultStrategy(),
+	}, {
+		name: "additional reply kind",
+		result: &ResultStrategy{
+			Target: &corev1.ObjectReference{
+				Name:       "reply-channel",
+				APIVersion: channelAPIVersion,
+				Kind:       "KafkaChannel",
+			},
+		},
+	}, {
+		name: "service reply",
+		result: &ResultStrategy{
+			Target: &corev1.ObjectReference{
+				Name:       "reply-service",
+				APIVersion: channelAPIVersion,
+				Kind:       "Service",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("Service", "spec.result.kind")
+			fe.Details = "only 'Channel' or 'KafkaChannel' kind is allowed"
+			return fe
+		}(),
+	}, {
+		name: "empty reply",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Subscription{
+				Spec: SubscriptionSpec{
+					From:   getValidFromRef(),
+					Call:   getValidCall(),
+					Result: test.result,
+				},
+			}
+			got := s.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// SetDefaults defaults the namespace of the subscriber's target to the Trigger's. The Broker isn't
+// defaulted: a Trigger must name the Broker it receives events from.
+func (t *Trigger) SetDefaults() {
+	if s := t.Spec.Subscriber; s != nil && s.Target != nil && s.Target.Namespace == "" {
+		s.Target.Namespace = t.Namespace
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTriggerDefaults(t *testing.T) {
+	testCases := map[string]struct {
+		initial  TriggerSpec
+		expected TriggerSpec
+	}{
+		"blank subscriber namespace": {
+			initial: TriggerSpec{
+				Broker:     "default",
+				Subscriber: &Callable{Target: &corev1.ObjectReference{Name: "foo"}},
+			},
+			expected: TriggerSpec{
+				Broker:     "default",
+				Subscriber: &Callable{Target: &corev1.ObjectReference{Name: "foo", Namespace: "trigger-namespace"}},
+			},
+		},
+		"subscriber namespace set": {
+			initial: TriggerSpec{
+				Broker:     "default",
+				Subscriber: &Callable{Target: &corev1.ObjectReference{Name: "foo", Namespace: "other"}},
+			},
+			expected: TriggerSpec{
+				Broker:     "default",
+				Subscriber: &Callable{Target: &corev1.ObjectReference{Name: "foo", Namespace: "other"}},
+			},
+		},
+		"empty broker isn't defaulted": {
+			initial:  TriggerSpec{},
+			expected: TriggerSpec{},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			tr := Trigger{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "trigger-namespace"},
+				Spec:       tc.initial,
+			}
+			tr.SetDefaults()
+			if diff := cmp.Diff(tc.expected, tr.Spec); diff != "" {
+				t.Errorf("unexpected spec (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:defaulter-gen=true
+
+// Trigger delivers the events of a Broker that pass its filter to a subscriber. It corresponds to
+// the triggers.eventing.knative.dev CRD.
+type Trigger struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              TriggerSpec   `json:"spec"`
+	Status            TriggerStatus `json:"status,omitempty"`
+}
+
+// Check that Trigger can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*Trigger)(nil)
+var _ apis.Defaultable = (*Trigger)(nil)
+var _ apis.Immutable = (*Trigger)(nil)
+var _ runtime.Object = (*Trigger)(nil)
+var _ webhook.GenericCRD = (*Trigger)(nil)
+
+// TriggerFilterAttributes are the CloudEvents context attributes a Trigger can filter events by.
+var TriggerFilterAttributes = sets.NewString("cloudEventsVersion", "contentType", "eventType", "eventTypeVersion", "schemaURL", "source")
+
+// TriggerSpec specifies the Broker a Trigger receives events from, which of them it selects and
+// where it delivers them.
+type TriggerSpec struct {
+	// Broker is the name of the Broker in the Trigger's namespace that events are received from.
+	//
+	// This field is immutable.
+	Broker string `json:"broker"`
+
+	// Filter selects the events that are delivered to the Subscriber. If unset, every event is.
+	// +optional
+	Filter *TriggerFilter `json:"filter,omitempty"`
+
+	// Subscriber is where the selected events are delivered. Only one of its target and
+	// targetURI may be set. The target's namespace defaults to the Trigger's, and may only be
+	// another namespace if AllowCrossNamespaceTriggerSubscribers is set.
+	Subscriber *Callable `json:"subscriber"`
+}
+
+// TriggerFilter selects events by their CloudEvents context attributes.
+type TriggerFilter struct {
+	// Attributes maps CloudEvents context attribute names, e.g. eventType or source, to the value
+	// the attribute must have. An event is selected if all of them match exactly.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Expressions are further conditions on CloudEvents context attributes. An event is selected
+	// if it matches Attributes and all of them.
+	// +optional
+	Expressions []TriggerFilterExpression `json:"expressions,omitempty"`
+}
+
+// TriggerFilterOperator is the operator of a TriggerFilterExpression.
+type TriggerFilterOperator string
+
+const (
+	// TriggerFilterOpExists matches events that set the attribute to a non-empty value.
+	TriggerFilterOpExists TriggerFilterOperator = "Exists"
+	// TriggerFilterOpNotEquals matches events whose attribute differs from the expression's only
+	// value.
+	TriggerFilterOpNotEquals TriggerFilterOperator = "NotEquals"
+	// TriggerFilterOpIn matches events whose attribute is one of the expression's values.
+	TriggerFilterOpIn TriggerFilterOperator = "In"
+)
+
+// TriggerFilterExpression is a condition on a CloudEvents context attribute.
+type TriggerFilterExpression struct {
+	// Attribute is the name of the CloudEvents context attribute, e.g. eventType or source.
+	Attribute string `json:"attribute"`
+
+	// Operator is how the attribute is compared to Values.
+	Operator TriggerFilterOperator `json:"operator"`
+
+	// Values are the values the attribute is compared to. Exists takes none, NotEquals exactly one
+	// and In at least one.
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// Matches returns true if the event whose context attributes are attributes passes the filter. A
+// nil filter passes every event.
+func (f *TriggerFilter) Matches(attributes map[string]string) bool {
+	if f == nil {
+		return true
+	}
+	for k, v := range f.Attributes {
+		if attributes[k] != v {
+			return false
+		}
+	}
+	for _, e := range f.Expressions {
+		if !e.Matches(attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches returns true if the event whose context attributes are attributes satisfies the
+// expression. An expression with an unknown operator matches no event.
+func (e *TriggerFilterExpression) Matches(attributes map[string]string) bool {
+	value := attributes[e.Attribute]
+	switch e.Operator {
+	case TriggerFilterOpExists:
+		return value != ""
+	case TriggerFilterOpNotEquals:
+		return len(e.Values) == 1 && value != e.Values[0]
+	case TriggerFilterOpIn:
+		for _, v := range e.Values {
+			if value == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// triggerCondSet is a condition set with Ready as the happy condition and BrokerReady and
+// SubscriberResolved as the dependent conditions.
+var triggerCondSet = duckv1alpha1.NewLivingConditionSet(TriggerConditionBroker, TriggerConditionSubscriberResolved)
+
+// TriggerStatus represents the current state of a Trigger.
+type TriggerStatus struct {
+	// Represents the latest available observations of a Trigger's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// SubscriberURI is the fully resolved URI for spec.subscriber.
+	// +optional
+	SubscriberURI string `json:"subscriberURI,omitempty"`
+}
+
+const (
+	// TriggerConditionReady has status True when all subconditions below have been set to True.
+	TriggerConditionReady = duckv1alpha1.ConditionReady
+
+	// TriggerConditionBroker has status True when the Trigger's Broker is Ready.
+	TriggerConditionBroker duckv1alpha1.ConditionType = "BrokerReady"
+
+	// TriggerConditionSubscriberResolved has status True when spec.subscriber resolved to a URI.
+	TriggerConditionSubscriberResolved duckv1alpha1.ConditionType = "SubscriberResolved"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ts *TriggerStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return triggerCondSet.Manage(ts).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ts *TriggerStatus) IsReady() bool {
+	return triggerCondSet.Manage(ts).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ts *TriggerStatus) InitializeConditions() {
+	triggerCondSet.Manage(ts).InitializeConditions()
+}
+
+// PropagateBrokerStatus sets the BrokerReady condition from the readiness of the Trigger's Broker.
+func (ts *TriggerStatus) PropagateBrokerStatus(b *Broker) {
+	if b.Status.IsReady() {
+		triggerCondSet.Manage(ts).MarkTrue(TriggerConditionBroker)
+	} else {
+		triggerCondSet.Manage(ts).MarkFalse(TriggerConditionBroker, "BrokerNotReady", "the Broker %s is not ready", b.Name)
+	}
+}
+
+// MarkBrokerDoesNotExist sets the BrokerReady condition to False state, because there is no Broker
+// with the name the Trigger references.
+func (ts *TriggerStatus) MarkBrokerDoesNotExist(name string) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionBroker, "BrokerDoesNotExist", "the Broker %s does not exist", name)
+}
+
+// SetSubscriberURI records the URI the Trigger's events are delivered to. The SubscriberResolved
+// condition is set to True if it isn't empty, otherwise it is set to False.
+func (ts *TriggerStatus) SetSubscriberURI(uri string) {
+	ts.SubscriberURI = uri
+	if uri != "" {
+		triggerCondSet.Manage(ts).MarkTrue(TriggerConditionSubscriberResolved)
+	} else {
+		triggerCondSet.Manage(ts).MarkFalse(TriggerConditionSubscriberResolved, "emptyURI", "the subscriber did not resolve to a URI")
+	}
+}
+
+// MarkSubscriberNotResolved sets the SubscriberResolved condition to False state and clears the
+// subscriber URI, for when spec.subscriber can't be resolved.
+func (ts *TriggerStatus) MarkSubscriberNotResolved(reason, messageFormat string, messageA ...interface{}) {
+	ts.SubscriberURI = ""
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionSubscriberResolved, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TriggerList is a collection of Triggers.
+type TriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Trigger `json:"items"`
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTriggerFilter_Matches(t *testing.T) {
+	attributes := map[string]string{
+		"eventType": "dev.knative.foo",
+		"source":    "/foo",
+	}
+	testCases := map[string]struct {
+		filter *TriggerFilter
+		want   bool
+	}{
+		"nil filter": {
+			want: true,
+		},
+		"no attributes": {
+			filter: &TriggerFilter{},
+			want:   true,
+		},
+		"type matches": {
+			filter: &TriggerFilter{Attributes: map[string]string{"eventType": "dev.knative.foo"}},
+			want:   true,
+		},
+		"type and source match": {
+			filter: &TriggerFilter{Attributes: map[string]string{"eventType": "dev.knative.foo", "source": "/foo"}},
+			want:   true,
+		},
+		"source differs": {
+			filter: &TriggerFilter{Attributes: map[string]string{"eventType": "dev.knative.foo", "source": "/bar"}},
+			want:   false,
+		},
+		"attribute not set": {
+			filter: &TriggerFilter{Attributes: map[string]string{"schemaURL": "http://example.com/schema"}},
+			want:   false,
+		},
+		"exists": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpExists}}},
+			want:   true,
+		},
+		"exists, attribute not set": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "schemaURL", Operator: TriggerFilterOpExists}}},
+			want:   false,
+		},
+		"exists, attribute empty": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "contentType", Operator: TriggerFilterOpExists}}},
+			want:   false,
+		},
+		"not equals": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpNotEquals, Values: []string{"/bar"}}}},
+			want:   true,
+		},
+		"not equals, attribute equal": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpNotEquals, Values: []string{"/foo"}}}},
+			want:   false,
+		},
+		"not equals, attribute not set": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "schemaURL", Operator: TriggerFilterOpNotEquals, Values: []string{"/foo"}}}},
+			want:   true,
+		},
+		"in": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpIn, Values: []string{"/bar", "/foo"}}}},
+			want:   true,
+		},
+		"in, attribute not listed": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpIn, Values: []string{"/bar", "/baz"}}}},
+			want:   false,
+		},
+		"in, no values": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpIn}}},
+			want:   false,
+		},
+		"unknown operator": {
+			filter: &TriggerFilter{Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: "Like", Values: []string{"/foo"}}}},
+			want:   false,
+		},
+		"attributes and expressions match": {
+			filter: &TriggerFilter{
+				Attributes:  map[string]string{"eventType": "dev.knative.foo"},
+				Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpExists}},
+			},
+			want: true,
+		},
+		"attributes match, expressions don't": {
+			filter: &TriggerFilter{
+				Attributes:  map[string]string{"eventType": "dev.knative.foo"},
+				Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpNotEquals, Values: []string{"/foo"}}},
+			},
+			want: false,
+		},
+		"expressions match, attributes don't": {
+			filter: &TriggerFilter{
+				Attributes:  map[string]string{"eventType": "dev.knative.bar"},
+				Expressions: []TriggerFilterExpression{{Attribute: "source", Operator: TriggerFilterOpExists}},
+			},
+			want: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.filter.Matches(attributes); got != tc.want {
+				t.Errorf("unexpected match: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTriggerStatus_Conditions(t *testing.T) {
+	readyBroker := &Broker{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	readyBroker.Status.InitializeConditions()
+	readyBroker.Status.PropagateChannelStatus(readyChannelForTrigger())
+
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	if ts.IsReady() {
+		t.Errorf("expected a new Trigger not to be ready")
+	}
+
+	ts.PropagateBrokerStatus(&Broker{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+	if c := ts.GetCondition(TriggerConditionBroker); c.Status != corev1.ConditionFalse || c.Reason != "BrokerNotReady" {
+		t.Errorf("unexpected BrokerReady condition: %+v", c)
+	}
+
+	ts.MarkBrokerDoesNotExist("default")
+	if c := ts.GetCondition(TriggerConditionBroker); c.Status != corev1.ConditionFalse || c.Reason != "BrokerDoesNotExist" {
+		t.Errorf("unexpected BrokerReady condition: %+v", c)
+	}
+
+	ts.PropagateBrokerStatus(readyBroker)
+	ts.SetSubscriberURI("")
+	if c := ts.GetCondition(TriggerConditionSubscriberResolved); c.Status != corev1.ConditionFalse {
+		t.Errorf("unexpected SubscriberResolved condition: %+v", c)
+	}
+
+	ts.SetSubscriberURI("http://subscriber.example.com")
+	if !ts.IsReady() {
+		t.Errorf("expected the Trigger to be ready, got %+v", ts.Conditions)
+	}
+
+	ts.MarkSubscriberNotResolved("NotFound", "the subscriber %s does not exist", "foo")
+	if ts.IsReady() || ts.SubscriberURI != "" {
+		t.Errorf("expected the Trigger not to be ready and have no subscriber URI, got %+v", ts)
+	}
+}
+
+func readyChannelForTrigger() *Channel {
+	ch := &Channel{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "default-broker"}}
+	ch.Status.InitializeConditions()
+	ch.Status.MarkCompatible()
+	ch.Status.MarkProvisioned()
+	ch.Status.SetSinkable("default-broker.test-namespace.svc.cluster.local")
+	ch.Status.SetSubscribable(ch.Namespace, ch.Name)
+	return ch
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// AllowCrossNamespaceTriggerSubscribers enables Triggers whose subscriber's target is in another
+// namespace than the Trigger.
+var AllowCrossNamespaceTriggerSubscribers = false
+
+func (t *Trigger) Validate() *apis.FieldError {
+	errs := t.Spec.Validate().ViaField("spec")
+	if s := t.Spec.Subscriber; s != nil && s.Target != nil && s.Target.Namespace != "" &&
+		s.Target.Namespace != t.Namespace && !AllowCrossNamespaceTriggerSubscribers {
+		fe := apis.ErrInvalidValue(s.Target.Namespace, "spec.subscriber.target.namespace")
+		fe.Details = "the subscriber must be in the Trigger's namespace"
+		errs = errs.Also(fe)
+	}
+	return errs
+}
+
+// We require the Broker and a Subscriber with exactly one of target and targetURI. The filter may
+// only match on known CloudEvents context attributes.
+func (ts *TriggerSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ts.Broker == "" {
+		errs = errs.Also(apis.ErrMissingField("broker"))
+	}
+
+	if ts.Filter != nil {
+		for k := range ts.Filter.Attributes {
+			if !TriggerFilterAttributes.Has(k) {
+				fe := apis.ErrInvalidKeyName(k, "filter.attributes")
+				fe.Details = fmt.Sprintf("only the CloudEvents attributes %v can be filtered by", TriggerFilterAttributes.List())
+				errs = errs.Also(fe)
+			}
+		}
+		for i, e := range ts.Filter.Expressions {
+			errs = errs.Also(e.Validate().ViaFieldIndex("expressions", i).ViaField("filter"))
+		}
+	}
+
+	if isCallableNilOrEmpty(ts.Subscriber) {
+		fe := apis.ErrMissingField("subscriber")
+		fe.Details = "the Trigger must reference a subscriber"
+		return errs.Also(fe)
+	}
+	if ts.Subscriber.Selector != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("subscriber.selector"))
+	}
+	hasTarget := ts.Subscriber.Target != nil && !equality.Semantic.DeepEqual(ts.Subscriber.Target, &corev1.ObjectReference{})
+	hasTargetURI := ts.Subscriber.TargetURI != nil && *ts.Subscriber.TargetURI != ""
+	if !hasTarget && !hasTargetURI {
+		errs = errs.Also(apis.ErrMissingOneOf("subscriber.target", "subscriber.targetURI"))
+	}
+	// Unlike a Subscription's, a Trigger's subscriber may have a namespace, which Trigger.Validate
+	// checks.
+	subscriber := *ts.Subscriber
+	if subscriber.Target != nil {
+		target := *subscriber.Target
+		target.Namespace = ""
+		subscriber.Target = &target
+	}
+	if fe := isValidCallable(subscriber); fe != nil {
+		errs = errs.Also(fe.ViaField("subscriber"))
+	}
+	return errs
+}
+
+// Validate rejects an expression on an attribute that can't be filtered by, with an unknown operator,
+// or with the wrong number of values for its operator.
+func (e *TriggerFilterExpression) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if !TriggerFilterAttributes.Has(e.Attribute) {
+		fe := apis.ErrInvalidValue(e.Attribute, "attribute")
+		fe.Details = fmt.Sprintf("only the CloudEvents attributes %v can be filtered by", TriggerFilterAttributes.List())
+		errs = errs.Also(fe)
+	}
+	switch e.Operator {
+	case TriggerFilterOpExists:
+		if len(e.Values) != 0 {
+			errs = errs.Also(apis.ErrDisallowedFields("values"))
+		}
+	case TriggerFilterOpNotEquals:
+		if len(e.Values) != 1 {
+			fe := apis.ErrInvalidValue(fmt.Sprintf("%d values", len(e.Values)), "values")
+			fe.Details = "NotEquals takes exactly one value"
+			errs = errs.Also(fe)
+		}
+	case TriggerFilterOpIn:
+		if len(e.Values) == 0 {
+			errs = errs.Also(apis.ErrMissingField("values"))
+		}
+	default:
+		fe := apis.ErrInvalidValue(string(e.Operator), "operator")
+		fe.Details = fmt.Sprintf("the operator must be one of %s, %s or %s", TriggerFilterOpExists, TriggerFilterOpNotEquals, TriggerFilterOpIn)
+		errs = errs.Also(fe)
+	}
+	return errs
+}
+
+func (current *Trigger) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	original, ok := og.(*Trigger)
+	if !ok {
+		return &apis.FieldError{Message: "The provided original was not a Trigger"}
+	}
+	if original == nil {
+		return nil
+	}
+
+	// Only Filter and Subscriber are mutable.
+	ignoreArguments := cmpopts.IgnoreFields(TriggerSpec{}, "Filter", "Subscriber")
+	if diff := cmp.Diff(original.Spec, current.Spec, ignoreArguments); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec"},
+			Details: diff,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getValidTriggerSpec() TriggerSpec {
+	return TriggerSpec{
+		Broker: "default",
+		Filter: &TriggerFilter{
+			Attributes: map[string]string{
+				"eventType": "dev.knative.foo",
+			},
+		},
+		Subscriber: getValidCall(),
+	}
+}
+
+func TestTriggerSpecValidation(t *testing.T) {
+	targetURI := "http://subscriber.example.com"
+	tests := []struct {
+		name string
+		ts   func(*TriggerSpec)
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		ts:   func(*TriggerSpec) {},
+		want: nil,
+	}, {
+		name: "valid target URI",
+		ts: func(ts *TriggerSpec) {
+			ts.Subscriber = &Callable{TargetURI: &targetURI}
+		},
+		want: nil,
+	}, {
+		name: "no filter",
+		ts: func(ts *TriggerSpec) {
+			ts.Filter = nil
+		},
+		want: nil,
+	}, {
+		name: "empty broker",
+		ts: func(ts *TriggerSpec) {
+			ts.Broker = ""
+		},
+		want: apis.ErrMissingField("spec.broker"),
+	}, {
+		name: "unknown filter attribute",
+		ts: func(ts *TriggerSpec) {
+			ts.Filter.Attributes["color"] = "blue"
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidKeyName("color", "spec.filter.attributes")
+			fe.Details = "only the CloudEvents attributes [cloudEventsVersion contentType eventType eventTypeVersion schemaURL source] can be filtered by"
+			return fe
+		}(),
+	}, {
+		name: "valid filter expressions",
+		ts: func(ts *TriggerSpec) {
+			ts.Filter.Expressions = []TriggerFilterExpression{
+				{Attribute: "source", Operator: TriggerFilterOpExists},
+				{Attribute: "source", Operator: TriggerFilterOpNotEquals, Values: []string{"/foo"}},
+				{Attribute: "eventTypeVersion", Operator: TriggerFilterOpIn, Values: []string{"v1", "v2"}},
+			}
+		},
+		want: nil,
+	}, {
+		name: "unknown filter expression operator",
+		ts: func(ts *TriggerSpec) {
+			ts.Filter.Expressions = []TriggerFilterExpression{{Attribute: "source", Operator: "Like", Values: []string{"/foo"}}}
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("Like", "spec.filter.expressions[0].operator")
+			fe.Details = "the operator must be one of Exists, NotEquals or In"
+			return fe
+		}(),
+	}, {
+		name: "unknown filter expression attribute",
+		ts: func(ts *TriggerSpec) {
+			ts.Filter.Expressions = []TriggerFilterExpression{{Attribute: "color", Operator: TriggerFilterOpExists}}
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("color", "spec.filter.expressions[0].attribute")
+			fe.Details = "only the CloudEvents attributes [cloudEventsVersion contentType eventType eventTypeVersion schemaURL source] can be filtered by"
+			return fe
+		}(),
+	}, {
+		name: "filter expression values",
+		ts: func(ts *TriggerSpec) {
+			ts.Filter.Expressions = []TriggerFilterExpression{
+				{Attribute: "source", Operator: TriggerFilterOpExists, Values: []string{"/foo"}},
+				{Attribute: "source", Operator: TriggerFilterOpNotEquals},
+				{Attribute: "source", Operator: TriggerFilterOpIn},
+			}
+		},
+		want: func() *apis.FieldError {
+			errs := apis.ErrDisallowedFields("spec.filter.expressions[0].values")
+			fe := apis.ErrInvalidValue("0 values", "spec.filter.expressions[1].values")
+			fe.Details = "NotEquals takes exactly one value"
+			return errs.Also(fe).Also(apis.ErrMissingField("spec.filter.expressions[2].values"))
+		}(),
+	}, {
+		name: "missing subscriber",
+		ts: func(ts *TriggerSpec) {
+			ts.Subscriber = nil
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.subscriber")
+			fe.Details = "the Trigger must reference a subscriber"
+			return fe
+		}(),
+	}, {
+		name: "subscriber selector",
+		ts: func(ts *TriggerSpec) {
+			ts.Subscriber = &Callable{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}}
+		},
+		want: apis.ErrDisallowedFields("spec.subscriber.selector").
+			Also(apis.ErrMissingOneOf("spec.subscriber.target", "spec.subscriber.targetURI")),
+	}, {
+		name: "subscriber target and target URI",
+		ts: func(ts *TriggerSpec) {
+			ts.Subscriber.TargetURI = &targetURI
+		},
+		want: apis.ErrMultipleOneOf("spec.subscriber.target", "spec.subscriber.targetURI"),
+	}, {
+		name: "subscriber target missing name",
+		ts: func(ts *TriggerSpec) {
+			ts.Subscriber.Target.Name = ""
+		},
+		want: apis.ErrMissingField("spec.subscriber.target.name"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{Spec: getValidTriggerSpec()}
+			test.ts(&tr.Spec)
+			got := tr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestTriggerValidation_SubscriberNamespace(t *testing.T) {
+	defer func(allow bool) { AllowCrossNamespaceTriggerSubscribers = allow }(AllowCrossNamespaceTriggerSubscribers)
+
+	tests := []struct {
+		name                string
+		namespace           string
+		allowCrossNamespace bool
+		want                *apis.FieldError
+	}{{
+		name: "blank namespace",
+	}, {
+		name:      "fully specified",
+		namespace: "trigger-namespace",
+	}, {
+		name:      "other namespace",
+		namespace: "other",
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("other", "spec.subscriber.target.namespace")
+			fe.Details = "the subscriber must be in the Trigger's namespace"
+			return fe
+		}(),
+	}, {
+		name:                "other namespace allowed",
+		namespace:           "other",
+		allowCrossNamespace: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			AllowCrossNamespaceTriggerSubscribers = test.allowCrossNamespace
+			tr := &Trigger{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "trigger-namespace"},
+				Spec:       getValidTriggerSpec(),
+			}
+			tr.Spec.Subscriber.Target.Namespace = test.namespace
+			if diff := cmp.Diff(test.want.Error(), tr.Validate().Error()); diff != "" {
+				t.Errorf("%s: Validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestTriggerImmutable(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   func(*TriggerSpec)
+		want bool
+	}{{
+		name: "unchanged",
+		ts:   func(*TriggerSpec) {},
+	}, {
+		name: "filter changed",
+		ts: func(ts *TriggerSpec) {
+			ts.Filter.Attributes["source"] = "/foo"
+		},
+	}, {
+		name: "subscriber changed",
+		ts: func(ts *TriggerSpec) {
+			ts.Subscriber = &Callable{Target: &corev1.ObjectReference{Name: "other", Kind: routeKind, APIVersion: routeAPIVersion}}
+		},
+	}, {
+		name: "broker changed",
+		ts: func(ts *TriggerSpec) {
+			ts.Broker = "orders"
+		},
+		want: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			og := &Trigger{Spec: getValidTriggerSpec()}
+			c := &Trigger{Spec: getValidTriggerSpec()}
+			test.ts(&c.Spec)
+			got := c.CheckImmutableFields(og)
+			if (got != nil) != test.want {
+				t.Errorf("%s: CheckImmutableFields want error %v, got %v", test.name, test.want, got)
+			}
+		})
+	}
+
+	if got := (&Trigger{Spec: getValidTriggerSpec()}).CheckImmutableFields((*Trigger)(nil)); got != nil {
+		t.Errorf("expected no error checking against a nil original, got %v", got)
+	}
+	if got := (&Trigger{}).CheckImmutableFields(&DummyImmutableType{}); got == nil {
+		t.Errorf("expected an error checking a Trigger against another type")
+	}
+}
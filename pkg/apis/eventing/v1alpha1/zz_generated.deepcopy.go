@@ -21,11 +21,132 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apis "github.com/knative/pkg/apis"
 	duck_v1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Broker) DeepCopyInto(out *Broker) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Broker.
+func (in *Broker) DeepCopy() *Broker {
+	if in == nil {
+		return nil
+	}
+	out := new(Broker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Broker) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerList) DeepCopyInto(out *BrokerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Broker, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerList.
+func (in *BrokerList) DeepCopy() *BrokerList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
+	*out = *in
+	if in.ChannelTemplate != nil {
+		in, out := &in.ChannelTemplate, &out.ChannelTemplate
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ChannelSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSpec.
+func (in *BrokerSpec) DeepCopy() *BrokerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerStatus) DeepCopyInto(out *BrokerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Sinkable = in.Sinkable
+	if in.Channel != nil {
+		in, out := &in.Channel, &out.Channel
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.ObjectReference)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerStatus.
+func (in *BrokerStatus) DeepCopy() *BrokerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Callable) DeepCopyInto(out *Callable) {
 	*out = *in
@@ -47,6 +168,15 @@ func (in *Callable) DeepCopyInto(out *Callable) {
 			**out = **in
 		}
 	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.LabelSelector)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -88,6 +218,152 @@ func (in *Channel) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelAddress) DeepCopyInto(out *ChannelAddress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelAddress.
+func (in *ChannelAddress) DeepCopy() *ChannelAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelArguments) DeepCopyInto(out *ChannelArguments) {
+	*out = *in
+	if in.ConsumerGroupPrefix != nil {
+		in, out := &in.ConsumerGroupPrefix, &out.ConsumerGroupPrefix
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	if in.OrderedDelivery != nil {
+		in, out := &in.OrderedDelivery, &out.OrderedDelivery
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(bool)
+			**out = **in
+		}
+	}
+	if in.PartitionKey != nil {
+		in, out := &in.PartitionKey, &out.PartitionKey
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.ObjectReference)
+			**out = **in
+		}
+	}
+	if in.DefaultConcurrency != nil {
+		in, out := &in.DefaultConcurrency, &out.DefaultConcurrency
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int32)
+			**out = **in
+		}
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ChannelPlacement)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.EventSchema != nil {
+		in, out := &in.EventSchema, &out.EventSchema
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(runtime.RawExtension)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelArguments.
+func (in *ChannelArguments) DeepCopy() *ChannelArguments {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelArguments)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelDeliveryStats) DeepCopyInto(out *ChannelDeliveryStats) {
+	*out = *in
+	if in.OldestUnacknowledgedTime != nil {
+		in, out := &in.OldestUnacknowledgedTime, &out.OldestUnacknowledgedTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = (*in).DeepCopy()
+		}
+	}
+	if in.ApproximateBacklog != nil {
+		in, out := &in.ApproximateBacklog, &out.ApproximateBacklog
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int64)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelDeliveryStats.
+func (in *ChannelDeliveryStats) DeepCopy() *ChannelDeliveryStats {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelDeliveryStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelDuckStatus) DeepCopyInto(out *ChannelDuckStatus) {
+	*out = *in
+	out.Sinkable = in.Sinkable
+	out.Subscribable = in.Subscribable
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelDuckStatus.
+func (in *ChannelDuckStatus) DeepCopy() *ChannelDuckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelDuckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChannelList) DeepCopyInto(out *ChannelList) {
 	*out = *in
@@ -121,6 +397,34 @@ func (in *ChannelList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelPlacement) DeepCopyInto(out *ChannelPlacement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelPlacement.
+func (in *ChannelPlacement) DeepCopy() *ChannelPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
 	*out = *in
@@ -129,49 +433,174 @@ func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(ProvisionerReference)
+			*out = new(ProvisionerReference)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(runtime.RawExtension)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Channelable != nil {
+		in, out := &in.Channelable, &out.Channelable
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(Channelable)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
+func (in *ChannelSpec) DeepCopy() *ChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
+	*out = *in
+	out.Sinkable = in.Sinkable
+	out.Subscribable = in.Subscribable
+	if in.SubscribableTargets != nil {
+		in, out := &in.SubscribableTargets, &out.SubscribableTargets
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.EffectiveRetention != nil {
+		in, out := &in.EffectiveRetention, &out.EffectiveRetention
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Duration)
+			**out = **in
+		}
+	}
+	if in.DeliveryStats != nil {
+		in, out := &in.DeliveryStats, &out.DeliveryStats
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ChannelDeliveryStats)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.SubscriberLags != nil {
+		in, out := &in.SubscriberLags, &out.SubscriberLags
+		*out = make([]SubscriberLag, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastReadyTime != nil {
+		in, out := &in.LastReadyTime, &out.LastReadyTime
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(apis.VolatileTime)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.ConditionTransitions != nil {
+		in, out := &in.ConditionTransitions, &out.ConditionTransitions
+		*out = make(map[duck_v1alpha1.ConditionType]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConditionSeverities != nil {
+		in, out := &in.ConditionSeverities, &out.ConditionSeverities
+		*out = make(map[duck_v1alpha1.ConditionType]ConditionSeverity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelStatus.
+func (in *ChannelStatus) DeepCopy() *ChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelSubscriberSpec) DeepCopyInto(out *ChannelSubscriberSpec) {
+	*out = *in
+	if in.Ref != nil {
+		in, out := &in.Ref, &out.Ref
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.ObjectReference)
+			**out = **in
+		}
+	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(DeliverySpec)
 			(*in).DeepCopyInto(*out)
 		}
 	}
-	if in.Arguments != nil {
-		in, out := &in.Arguments, &out.Arguments
+	if in.LeaseExpiry != nil {
+		in, out := &in.LeaseExpiry, &out.LeaseExpiry
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(runtime.RawExtension)
-			(*in).DeepCopyInto(*out)
+			*out = (*in).DeepCopy()
 		}
 	}
-	if in.Channelable != nil {
-		in, out := &in.Channelable, &out.Channelable
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(duck_v1alpha1.Channelable)
+			*out = new(SubscriberFilter)
 			(*in).DeepCopyInto(*out)
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
-func (in *ChannelSpec) DeepCopy() *ChannelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSubscriberSpec.
+func (in *ChannelSubscriberSpec) DeepCopy() *ChannelSubscriberSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelSpec)
+	out := new(ChannelSubscriberSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
+func (in *Channelable) DeepCopyInto(out *Channelable) {
 	*out = *in
-	out.Sinkable = in.Sinkable
-	out.Subscribable = in.Subscribable
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(duck_v1alpha1.Conditions, len(*in))
+	if in.Subscribers != nil {
+		in, out := &in.Subscribers, &out.Subscribers
+		*out = make([]ChannelSubscriberSpec, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -179,12 +608,12 @@ func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelStatus.
-func (in *ChannelStatus) DeepCopy() *ChannelStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Channelable.
+func (in *Channelable) DeepCopy() *Channelable {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelStatus)
+	out := new(Channelable)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -290,6 +719,74 @@ func (in *ClusterProvisionerStatus) DeepCopy() *ClusterProvisionerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadLetterSink) DeepCopyInto(out *DeadLetterSink) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.ObjectReference)
+			**out = **in
+		}
+	}
+	if in.TargetURI != nil {
+		in, out := &in.TargetURI, &out.TargetURI
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadLetterSink.
+func (in *DeadLetterSink) DeepCopy() *DeadLetterSink {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadLetterSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliverySpec) DeepCopyInto(out *DeliverySpec) {
+	*out = *in
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int32)
+			**out = **in
+		}
+	}
+	if in.BackoffDelay != nil {
+		in, out := &in.BackoffDelay, &out.BackoffDelay
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(meta_v1.Duration)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliverySpec.
+func (in *DeliverySpec) DeepCopy() *DeliverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProvisionerReference) DeepCopyInto(out *ProvisionerReference) {
 	*out = *in
@@ -468,6 +965,45 @@ func (in *SourceStatus) DeepCopy() *SourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriberLag) DeepCopyInto(out *SubscriberLag) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriberLag.
+func (in *SubscriberLag) DeepCopy() *SubscriberLag {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriberLag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriberFilter) DeepCopyInto(out *SubscriberFilter) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriberFilter.
+func (in *SubscriberFilter) DeepCopy() *SubscriberFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriberFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
@@ -551,6 +1087,33 @@ func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 			(*in).DeepCopyInto(*out)
 		}
 	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(int32)
+			**out = **in
+		}
+	}
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(DeadLetterSink)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(DeliverySpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -575,6 +1138,7 @@ func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 		}
 	}
 	out.Subscribable = in.Subscribable
+	in.PhysicalSubscription.DeepCopyInto(&out.PhysicalSubscription)
 	return
 }
 
@@ -587,3 +1151,193 @@ func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionStatusPhysicalSubscription) DeepCopyInto(out *SubscriptionStatusPhysicalSubscription) {
+	*out = *in
+	if in.SubscriberURIs != nil {
+		in, out := &in.SubscriberURIs, &out.SubscriberURIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatusPhysicalSubscription.
+func (in *SubscriptionStatusPhysicalSubscription) DeepCopy() *SubscriptionStatusPhysicalSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionStatusPhysicalSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Trigger) DeepCopyInto(out *Trigger) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Trigger.
+func (in *Trigger) DeepCopy() *Trigger {
+	if in == nil {
+		return nil
+	}
+	out := new(Trigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Trigger) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerFilter) DeepCopyInto(out *TriggerFilter) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Expressions != nil {
+		in, out := &in.Expressions, &out.Expressions
+		*out = make([]TriggerFilterExpression, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerFilter.
+func (in *TriggerFilter) DeepCopy() *TriggerFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerFilterExpression) DeepCopyInto(out *TriggerFilterExpression) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerFilterExpression.
+func (in *TriggerFilterExpression) DeepCopy() *TriggerFilterExpression {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerFilterExpression)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerList) DeepCopyInto(out *TriggerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Trigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerList.
+func (in *TriggerList) DeepCopy() *TriggerList {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TriggerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerSpec) DeepCopyInto(out *TriggerSpec) {
+	*out = *in
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(TriggerFilter)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Subscriber != nil {
+		in, out := &in.Subscriber, &out.Subscriber
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(Callable)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerSpec.
+func (in *TriggerSpec) DeepCopy() *TriggerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerStatus) DeepCopyInto(out *TriggerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerStatus.
+func (in *TriggerStatus) DeepCopy() *TriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
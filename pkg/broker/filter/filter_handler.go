@@ -0,0 +1,179 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filter provides an http.Handler that receives the events a Broker's Channel fans out to
+// each of the Broker's Triggers, and delivers those that pass a Trigger's filter to the Trigger's
+// subscriber. A single filter serves every Trigger in the cluster, which address it at
+// TriggerPath.
+package filter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/buses"
+	"github.com/knative/eventing/pkg/event"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const triggerPathPrefix = "/triggers/"
+
+var (
+	errUnknownTrigger = errors.New("unknown trigger")
+	errNotCloudEvent  = errors.New("not a CloudEvent")
+)
+
+// TriggerPath returns the path at which the filter receives the events for the Trigger
+// namespace/name.
+func TriggerPath(namespace, name string) string {
+	return triggerPathPrefix + namespace + "/" + name
+}
+
+// parseTriggerPath is the inverse of TriggerPath.
+func parseTriggerPath(path string) (types.NamespacedName, bool) {
+	if !strings.HasPrefix(path, triggerPathPrefix) {
+		return types.NamespacedName{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(path, triggerPathPrefix), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, true
+}
+
+// Handler delivers events to the subscribers of the Triggers whose filters they pass.
+type Handler struct {
+	client     client.Client
+	dispatcher *buses.MessageDispatcher
+
+	logger *zap.Logger
+}
+
+var _ http.Handler = &Handler{}
+
+// NewHandler creates a new filter.Handler that reads Triggers with c.
+func NewHandler(logger *zap.Logger, c client.Client) *Handler {
+	return &Handler{
+		client:     c,
+		dispatcher: buses.NewMessageDispatcher(logger.Sugar()),
+		logger:     logger,
+	}
+}
+
+// ServeHTTP delivers the event in r to the subscriber of the Trigger named by r's path.
+//
+// The response status codes:
+//
+//	202 - the event was delivered, or did not pass the Trigger's filter
+//	400 - the request is not a CloudEvent
+//	404 - the request was for an unknown Trigger
+//	500 - an error occurred delivering the event
+//
+// Replies from the subscriber are dropped.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	trigger, ok := parseTriggerPath(r.URL.Path)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	// The dispatcher only forwards the headers it considers safe.
+	headers := make(map[string]string, len(r.Header))
+	for k, v := range r.Header {
+		headers[k] = v[0]
+	}
+
+	switch err := h.deliver(trigger, &buses.Message{Headers: headers, Payload: body}); err {
+	case nil:
+		w.WriteHeader(http.StatusAccepted)
+	case errNotCloudEvent:
+		w.WriteHeader(http.StatusBadRequest)
+	case errUnknownTrigger:
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// deliver sends m to the subscriber of the Trigger named by ref, if it passes the Trigger's filter.
+func (h *Handler) deliver(ref types.NamespacedName, m *buses.Message) error {
+	logger := h.logger.With(zap.String("trigger", ref.String()))
+	t := &eventingv1alpha1.Trigger{}
+	if err := h.client.Get(context.TODO(), ref, t); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Received an event for an unknown Trigger")
+			return errUnknownTrigger
+		}
+		logger.Error("Unable to get the Trigger", zap.Error(err))
+		return err
+	}
+
+	ec, err := eventContext(m)
+	if err != nil {
+		logger.Info("Unable to parse the event", zap.Error(err))
+		return errNotCloudEvent
+	}
+	if !t.Spec.Filter.Matches(attributes(ec)) {
+		logger.Debug("The event did not pass the Trigger's filter", zap.String("eventID", ec.EventID))
+		return nil
+	}
+	if t.Status.SubscriberURI == "" {
+		logger.Error("The Trigger has no subscriber URI")
+		return errors.New("trigger has no subscriber URI")
+	}
+	return h.dispatcher.DispatchMessage(m, t.Status.SubscriberURI, "", buses.DispatchDefaults{Namespace: t.Namespace})
+}
+
+// eventContext parses the CloudEvents context of m, in either the binary or the structured
+// encoding.
+func eventContext(m *buses.Message) (*event.EventContext, error) {
+	r := &http.Request{
+		Header: http.Header{},
+		Body:   ioutil.NopCloser(bytes.NewReader(m.Payload)),
+	}
+	for k, v := range m.Headers {
+		r.Header.Set(k, v)
+	}
+	return event.FromRequest(nil, r)
+}
+
+// attributes returns the context attributes of ec a Trigger can filter by, keyed by their names.
+func attributes(ec *event.EventContext) map[string]string {
+	return map[string]string{
+		"cloudEventsVersion": ec.CloudEventsVersion,
+		"contentType":        ec.ContentType,
+		"eventType":          ec.EventType,
+		"eventTypeVersion":   ec.EventTypeVersion,
+		"schemaURL":          ec.SchemaURL,
+		"source":             ec.Source,
+	}
+}
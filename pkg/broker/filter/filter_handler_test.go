@@ -0,0 +1,231 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/event"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS      = "test-namespace"
+	triggerName = "test-trigger"
+
+	// replaceSubscriber is the subscriber URI of Triggers, which will be replaced by the URI of
+	// the started HTTP server.
+	replaceSubscriber = "replaceSubscriber"
+
+	structuredEvent = `{
+    "cloudEventsVersion" : "0.1",
+    "eventType" : "com.example.someevent",
+    "source" : "/mycontext",
+    "eventID" : "A234-1234-1234",
+    "contentType" : "application/json",
+    "data" : {"much": "wow"}
+}`
+)
+
+func init() {
+	// Add types to scheme
+	eventingv1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	testCases := map[string]struct {
+		trigger        *eventingv1alpha1.Trigger
+		path           string
+		method         string
+		headers        map[string]string
+		body           string
+		subscriber     func(http.ResponseWriter, *http.Request)
+		expectedStatus int
+		expectedCalls  int32
+	}{
+		"unknown path": {
+			path:           "/channels/test-namespace/test-trigger",
+			expectedStatus: http.StatusNotFound,
+		},
+		"wrong method": {
+			trigger:        makeTrigger(nil),
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		"unknown trigger": {
+			expectedStatus: http.StatusNotFound,
+		},
+		"not a CloudEvent": {
+			trigger:        makeTrigger(nil),
+			headers:        map[string]string{"Content-Type": "text/plain"},
+			body:           "hello",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"structured event passes": {
+			trigger: makeTrigger(map[string]string{"eventType": "com.example.someevent"}),
+			headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+			body:    structuredEvent,
+			subscriber: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  1,
+		},
+		"structured event filtered out": {
+			trigger:        makeTrigger(map[string]string{"eventType": "com.example.otherevent"}),
+			headers:        map[string]string{"Content-Type": "application/cloudevents+json"},
+			body:           structuredEvent,
+			expectedStatus: http.StatusAccepted,
+		},
+		"binary event passes": {
+			trigger: makeTrigger(map[string]string{"eventType": "com.example.someevent", "source": "/mycontext"}),
+			headers: binaryHeaders(),
+			body:    `{"much":"wow"}`,
+			subscriber: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("CE-EventType") != "com.example.someevent" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedCalls:  1,
+		},
+		"binary event filtered out": {
+			trigger:        makeTrigger(map[string]string{"source": "/othercontext"}),
+			headers:        binaryHeaders(),
+			body:           `{"much":"wow"}`,
+			expectedStatus: http.StatusAccepted,
+		},
+		"subscriber fails": {
+			trigger: makeTrigger(nil),
+			headers: binaryHeaders(),
+			body:    `{"much":"wow"}`,
+			subscriber: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCalls:  1,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			calls := atomic.NewInt32(0)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Inc()
+				if tc.subscriber == nil {
+					t.Errorf("unexpected delivery to the subscriber")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				tc.subscriber(w, r)
+			}))
+			defer server.Close()
+
+			var objs []runtime.Object
+			if tc.trigger != nil {
+				tc.trigger.Status.SubscriberURI = server.URL
+				objs = append(objs, tc.trigger)
+			}
+			h := NewHandler(zap.NewNop(), fake.NewFakeClient(objs...))
+
+			path := tc.path
+			if path == "" {
+				path = TriggerPath(testNS, triggerName)
+			}
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "http://broker-filter.knative-eventing.svc.cluster.local"+path, strings.NewReader(tc.body))
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tc.expectedStatus {
+				t.Errorf("unexpected status: want %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if got := calls.Load(); got != tc.expectedCalls {
+				t.Errorf("unexpected number of deliveries: want %d, got %d", tc.expectedCalls, got)
+			}
+		})
+	}
+}
+
+func TestTriggerPath(t *testing.T) {
+	path := TriggerPath(testNS, triggerName)
+	if path != "/triggers/test-namespace/test-trigger" {
+		t.Errorf("unexpected path %q", path)
+	}
+	got, ok := parseTriggerPath(path)
+	if !ok || got.Namespace != testNS || got.Name != triggerName {
+		t.Errorf("expected %q to parse back to %s/%s, got %v, %v", path, testNS, triggerName, got, ok)
+	}
+	for _, bad := range []string{"/", "/triggers/", "/triggers/test-namespace", "/triggers/test-namespace/", "/triggers/a/b/c"} {
+		if _, ok := parseTriggerPath(bad); ok {
+			t.Errorf("expected %q not to parse", bad)
+		}
+	}
+}
+
+func TestAttributes_Filterable(t *testing.T) {
+	for k := range attributes(&event.EventContext{}) {
+		if !eventingv1alpha1.TriggerFilterAttributes.Has(k) {
+			t.Errorf("attribute %q is not filterable", k)
+		}
+	}
+	if got, want := len(attributes(&event.EventContext{})), eventingv1alpha1.TriggerFilterAttributes.Len(); got != want {
+		t.Errorf("expected all %d filterable attributes to be extracted, got %d", want, got)
+	}
+}
+
+func makeTrigger(filter map[string]string) *eventingv1alpha1.Trigger {
+	t := &eventingv1alpha1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      triggerName,
+		},
+		Spec: eventingv1alpha1.TriggerSpec{
+			Broker: "default",
+		},
+	}
+	if filter != nil {
+		t.Spec.Filter = &eventingv1alpha1.TriggerFilter{Attributes: filter}
+	}
+	t.Status.SetSubscriberURI(replaceSubscriber)
+	return t
+}
+
+func binaryHeaders() map[string]string {
+	return map[string]string{
+		"Content-Type":          "application/json",
+		"CE-CloudEventsVersion": "0.1",
+		"CE-EventID":            "A234-1234-1234",
+		"CE-EventType":          "com.example.someevent",
+		"CE-Source":             "/mycontext",
+	}
+}
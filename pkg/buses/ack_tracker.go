@@ -0,0 +1,148 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const ackCallbackHeaderName = "Knative-Ack-Callback"
+
+// ErrAckTimeout is returned when a pending delivery is not acknowledged before the
+// AckTracker's timeout elapses.
+var ErrAckTimeout = errors.New("timed out waiting for delivery acknowledgment")
+
+// ErrUnknownDelivery is returned when an acknowledgment is received for a delivery that is not
+// pending, either because it was never registered or because it already completed.
+var ErrUnknownDelivery = errors.New("unknown delivery")
+
+// AckTracker tracks deliveries to asynchronous subscribers. Such subscribers respond to the
+// delivery request with '202 Accepted' and an empty body, and later acknowledge the delivery
+// out-of-band by POSTing to the callback URL passed to them in the Knative-Ack-Callback header.
+//
+// AckTracker is an http.Handler that receives those callbacks.
+type AckTracker struct {
+	callbackURL string
+	timeout     time.Duration
+
+	mutex   sync.Mutex
+	pending map[string]chan struct{}
+
+	logger *zap.SugaredLogger
+}
+
+var _ http.Handler = &AckTracker{}
+
+// NewAckTracker creates a new AckTracker. callbackURL is the base URL at which the tracker is
+// served, the delivery's ID is appended to it to form the per-delivery callback URL. Deliveries
+// not acknowledged within timeout are considered failed.
+func NewAckTracker(callbackURL string, timeout time.Duration, logger *zap.SugaredLogger) *AckTracker {
+	return &AckTracker{
+		callbackURL: strings.TrimSuffix(callbackURL, "/"),
+		timeout:     timeout,
+		pending:     make(map[string]chan struct{}),
+		logger:      logger,
+	}
+}
+
+// Register starts tracking a new pending delivery and returns its ID. Callers must eventually
+// call Forget with the returned ID.
+func (t *AckTracker) Register() string {
+	id := uuid.New().String()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.pending[id] = make(chan struct{})
+	return id
+}
+
+// CallbackURL returns the URL the subscriber must POST to in order to acknowledge the delivery
+// with the given ID.
+func (t *AckTracker) CallbackURL(id string) string {
+	return t.callbackURL + "/" + id
+}
+
+// Ack acknowledges the pending delivery with the given ID.
+func (t *AckTracker) Ack(id string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	ch, ok := t.pending[id]
+	if !ok {
+		return ErrUnknownDelivery
+	}
+	select {
+	case <-ch:
+		// Already acknowledged.
+	default:
+		close(ch)
+	}
+	return nil
+}
+
+// Wait blocks until the delivery with the given ID is acknowledged, the timeout elapses, in which
+// case ErrAckTimeout is returned, or ctx is done, in which case its error is returned.
+func (t *AckTracker) Wait(ctx context.Context, id string) error {
+	t.mutex.Lock()
+	ch, ok := t.pending[id]
+	t.mutex.Unlock()
+	if !ok {
+		return ErrUnknownDelivery
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-time.After(t.timeout):
+		return ErrAckTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Forget stops tracking the delivery with the given ID.
+func (t *AckTracker) Forget(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.pending, id)
+}
+
+// ServeHTTP acknowledges the delivery whose ID is the last element of the request's path.
+//
+// The response status codes:
+//   200 - the delivery was acknowledged
+//   404 - the delivery is not pending
+//   405 - the request was not a POST
+func (t *AckTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	id := path.Base(r.URL.Path)
+	if err := t.Ack(id); err != nil {
+		t.logger.Infof("Received acknowledgment for unknown delivery %q", id)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestAckTracker_ServeHTTP(t *testing.T) {
+	testCases := map[string]struct {
+		method       string
+		register     bool
+		expectedCode int
+	}{
+		"acknowledged": {
+			method:       http.MethodPost,
+			register:     true,
+			expectedCode: http.StatusOK,
+		},
+		"unknown delivery": {
+			method:       http.MethodPost,
+			expectedCode: http.StatusNotFound,
+		},
+		"not a POST": {
+			method:       http.MethodGet,
+			register:     true,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			tracker := NewAckTracker("http://dispatcher/acks/", time.Minute, zap.NewNop().Sugar())
+			id := "unregistered"
+			if tc.register {
+				id = tracker.Register()
+			}
+			req := httptest.NewRequest(tc.method, tracker.CallbackURL(id), nil)
+			w := httptest.NewRecorder()
+			tracker.ServeHTTP(w, req)
+			if w.Code != tc.expectedCode {
+				t.Errorf("Unexpected status code. Expected %v. Actual %v", tc.expectedCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestAckTracker_WaitCanceled(t *testing.T) {
+	tracker := NewAckTracker("http://dispatcher/acks/", time.Minute, zap.NewNop().Sugar())
+	id := tracker.Register()
+	defer tracker.Forget(id)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tracker.Wait(ctx, id); err != context.DeadlineExceeded {
+		t.Errorf("Unexpected error from Wait. Expected %v. Actual: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestDispatchMessageWithRetries_AckOutlivesDelivery(t *testing.T) {
+	tracker := NewAckTracker("http://dispatcher/acks/", time.Minute, zap.NewNop().Sugar())
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer destServer.Close()
+
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	md.SetAckTracker(tracker)
+	start := time.Now()
+	err := md.DispatchMessageWithRetries(&Message{Payload: []byte("destination")}, getDomain(t, true, destServer.URL), "", "", RetryConfig{Timeout: 50 * time.Millisecond}, DispatchDefaults{})
+	if err == nil {
+		t.Errorf("Expected an error once the delivery timed out waiting for the acknowledgment")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the delivery's timeout to stop waiting for the acknowledgment, waited %v", elapsed)
+	}
+}
+
+func TestDispatchMessage_Ack(t *testing.T) {
+	testCases := map[string]struct {
+		ack         bool
+		expectedErr error
+	}{
+		"ack received": {
+			ack: true,
+		},
+		"ack timeout": {
+			expectedErr: ErrAckTimeout,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var tracker *AckTracker
+			ackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tracker.ServeHTTP(w, r)
+			}))
+			defer ackServer.Close()
+			tracker = NewAckTracker(ackServer.URL, 100*time.Millisecond, zap.NewNop().Sugar())
+
+			callbacks := make(chan string, 1)
+			destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				callbacks <- r.Header.Get(ackCallbackHeaderName)
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer destServer.Close()
+
+			if tc.ack {
+				go func() {
+					res, err := http.Post(<-callbacks, "", nil)
+					if err != nil {
+						t.Errorf("Unable to acknowledge delivery: %v", err)
+						return
+					}
+					res.Body.Close()
+					if res.StatusCode != http.StatusOK {
+						t.Errorf("Unexpected acknowledgment status code. Expected %v. Actual %v", http.StatusOK, res.StatusCode)
+					}
+				}()
+			}
+
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			md.SetAckTracker(tracker)
+			err := md.DispatchMessage(&Message{Payload: []byte("destination")}, getDomain(t, true, destServer.URL), "", DispatchDefaults{})
+			if tc.expectedErr == nil && err != nil {
+				t.Errorf("Unexpected error from DispatchMessage: %v", err)
+			}
+			if tc.expectedErr != nil && (err == nil || err.Error() != "Unable to complete request "+tc.expectedErr.Error()) {
+				t.Errorf("Unexpected error from DispatchMessage. Expected %v. Actual: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
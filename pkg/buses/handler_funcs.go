@@ -33,6 +33,9 @@ const (
 	// ErrResourceSync is used as part of the Event 'reason' when a resource fails
 	// to sync.
 	errResourceSync = "ErrResourceSync"
+	// ErrArgumentsRejected is used as part of the Event 'reason' when a resource's
+	// arguments don't satisfy the bus' parameters.
+	errArgumentsRejected = "ArgumentsRejected"
 )
 
 // ResolvedParameters is a map containing parameter names and the resolved
@@ -84,15 +87,14 @@ func (h EventHandlerFuncs) onProvision(channel *channelsv1alpha1.Channel, reconc
 	if h.ProvisionFunc == nil {
 		return nil
 	}
-	parameters, err := h.resolveChannelParameters(reconciler.bus.GetSpec(), channel.Spec)
-	if err != nil {
-		return err
-	}
 	channelCopy := channel.DeepCopy()
 	var cond *channelsv1alpha1.ChannelCondition
 	ref := NewChannelReference(channel)
-	err = h.ProvisionFunc(ref, parameters)
+	parameters, err := h.resolveChannelParameters(reconciler.bus.GetSpec(), channel.Spec)
 	if err != nil {
+		reconciler.RecordChannelEventf(ref, corev1.EventTypeWarning, errArgumentsRejected, "Channel arguments rejected: %s", err)
+		cond = util.NewChannelCondition(channelsv1alpha1.ChannelProvisioned, corev1.ConditionFalse, errArgumentsRejected, err.Error())
+	} else if err = h.ProvisionFunc(ref, parameters); err != nil {
 		reconciler.RecordChannelEventf(ref, corev1.EventTypeWarning, errResourceSync, "Error provisioning channel: %s", err)
 		cond = util.NewChannelCondition(channelsv1alpha1.ChannelProvisioned, corev1.ConditionFalse, errResourceSync, err.Error())
 	} else {
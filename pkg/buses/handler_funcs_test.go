@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buses
+
+import (
+	"testing"
+
+	channelsv1alpha1 "github.com/knative/eventing/pkg/apis/channels/v1alpha1"
+	"github.com/knative/eventing/pkg/client/clientset/versioned/fake"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestOnProvision_ArgumentsRejected(t *testing.T) {
+	channel := &channelsv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-channel",
+		},
+		Spec: channelsv1alpha1.ChannelSpec{
+			Bus: "test-bus",
+		},
+	}
+	bus := &channelsv1alpha1.Bus{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-bus",
+		},
+		Spec: channelsv1alpha1.BusSpec{
+			Parameters: &channelsv1alpha1.BusParameters{
+				Channel: &[]channelsv1alpha1.Parameter{{
+					Name: "topic",
+				}},
+			},
+		},
+	}
+	cache := NewCache()
+	cache.AddChannel(channel)
+	recorder := record.NewFakeRecorder(10)
+	client := fake.NewSimpleClientset(channel)
+	r := &Reconciler{
+		bus:            bus,
+		cache:          cache,
+		eventingClient: client,
+		recorder:       recorder,
+		logger:         zap.NewNop().Sugar(),
+	}
+	h := EventHandlerFuncs{
+		ProvisionFunc: func(ChannelReference, ResolvedParameters) error {
+			t.Errorf("Unexpected call to ProvisionFunc")
+			return nil
+		},
+		logger: zap.NewNop().Sugar(),
+	}
+
+	if err := h.onProvision(channel, r); err == nil {
+		t.Errorf("Expected an error provisioning a channel with missing arguments")
+	}
+
+	select {
+	case e := <-recorder.Events:
+		if want := "Warning ArgumentsRejected Channel arguments rejected: missing required arguments: [topic]"; e != want {
+			t.Errorf("Unexpected event. Expected %q. Actual %q", want, e)
+		}
+	default:
+		t.Errorf("Expected an ArgumentsRejected event")
+	}
+
+	updated, err := client.ChannelsV1alpha1().Channels(channel.Namespace).Get(channel.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unable to get the channel: %v", err)
+	}
+	var provisioned *channelsv1alpha1.ChannelCondition
+	for i, c := range updated.Status.Conditions {
+		if c.Type == channelsv1alpha1.ChannelProvisioned {
+			provisioned = &updated.Status.Conditions[i]
+		}
+	}
+	if provisioned == nil || provisioned.Status != corev1.ConditionFalse || provisioned.Reason != "ArgumentsRejected" {
+		t.Errorf("Unexpected Provisioned condition: %+v", provisioned)
+	}
+}
@@ -18,17 +18,32 @@ package buses
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
-const correlationIDHeaderName = "Knative-Correlation-Id"
+const (
+	correlationIDHeaderName = "Knative-Correlation-Id"
+	cloudEventIDHeaderName  = "CE-EventID"
+
+	// attemptHeaderName carries the number of the delivery attempt, starting at 1, as a
+	// CloudEvents extension.
+	attemptHeaderName = "CE-X-Knative-Attempt"
+
+	// correlationIDLogKey is the key used for a delivery's correlation ID in structured logs.
+	correlationIDLogKey = "knative.dev/correlationid"
+)
 
 // MessageDispatcher dispatches messages to a destination over HTTP.
 type MessageDispatcher struct {
@@ -37,6 +52,9 @@ type MessageDispatcher struct {
 	forwardPrefixes  []string
 	supportedSchemes map[string]bool
 
+	// ackTracker, if set, allows destinations to acknowledge deliveries asynchronously.
+	ackTracker *AckTracker
+
 	logger *zap.SugaredLogger
 }
 
@@ -45,10 +63,80 @@ type DispatchDefaults struct {
 	Namespace string
 }
 
+// BackoffPolicy is the policy used to compute the delay between retries of a delivery.
+type BackoffPolicy string
+
+const (
+	// BackoffPolicyLinear waits BackoffDelay times the number of the retry.
+	BackoffPolicyLinear BackoffPolicy = "linear"
+	// BackoffPolicyExponential waits BackoffDelay times 2 to the power of the number of the
+	// retry, minus one.
+	BackoffPolicyExponential BackoffPolicy = "exponential"
+)
+
+const (
+	// maxBackoffExponent bounds the growth of exponential backoff, so the delay can't overflow.
+	maxBackoffExponent = 16
+	// maxBackoff bounds the delay before any retry, whatever the policy.
+	maxBackoff = time.Minute
+)
+
+// RetryConfig configures how failed requests to a destination are retried.
+type RetryConfig struct {
+	// Retry is the number of times a failed request is retried. Zero means it is attempted
+	// only once.
+	Retry int
+	// BackoffPolicy is the policy used to compute the delay between retries. Empty means
+	// exponential.
+	BackoffPolicy BackoffPolicy
+	// BackoffDelay is the delay before the first retry.
+	BackoffDelay time.Duration
+	// Timeout bounds the whole delivery, including the retries and sending the message to the
+	// dead-letter sink. Once it elapses, pending requests are canceled and no more are made. Zero
+	// means no limit.
+	Timeout time.Duration
+}
+
+// backoff returns how long to wait before the retry'th retry, counting from 1, up to maxBackoff.
+func (c RetryConfig) backoff(retry int) time.Duration {
+	var backoff time.Duration
+	if c.BackoffPolicy == BackoffPolicyLinear {
+		backoff = c.BackoffDelay * time.Duration(retry)
+	} else {
+		if retry > maxBackoffExponent {
+			retry = maxBackoffExponent
+		}
+		backoff = c.BackoffDelay * time.Duration(1<<uint(retry-1))
+	}
+	if backoff > maxBackoff || backoff < 0 {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// context returns the context bounding a delivery by c.Timeout.
+func (c RetryConfig) context() (context.Context, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.Timeout)
+}
+
+// DefaultDialTimeout and DefaultRequestTimeout are the timeouts, see SetTimeouts, of the dispatchers
+// NewMessageDispatcher creates. The dispatchers' commands set them from flags. Zero means no limit.
+var (
+	DefaultDialTimeout    time.Duration
+	DefaultRequestTimeout time.Duration
+)
+
+// DefaultAckTracker is the AckTracker, see SetAckTracker, of the dispatchers NewMessageDispatcher
+// creates. The dispatchers' commands set it when asynchronous acknowledgment is enabled by flags.
+var DefaultAckTracker *AckTracker
+
 // NewMessageDispatcher creates a new message dispatcher that can dispatch
 // messages to HTTP destinations.
 func NewMessageDispatcher(logger *zap.SugaredLogger) *MessageDispatcher {
-	return &MessageDispatcher{
+	d := &MessageDispatcher{
 		httpClient:      &http.Client{},
 		forwardHeaders:  headerSet(forwardHeaders),
 		forwardPrefixes: forwardPrefixes,
@@ -56,9 +144,42 @@ func NewMessageDispatcher(logger *zap.SugaredLogger) *MessageDispatcher {
 			"http":  true,
 			"https": true,
 		},
+		ackTracker: DefaultAckTracker,
 
 		logger: logger,
 	}
+	if DefaultDialTimeout != 0 || DefaultRequestTimeout != 0 {
+		d.SetTimeouts(DefaultDialTimeout, DefaultRequestTimeout)
+	}
+	return d
+}
+
+// SetAckTracker enables asynchronous acknowledgment of deliveries. When set, each request to a
+// destination carries a Knative-Ack-Callback header. If the destination responds with '202
+// Accepted' and an empty body, the delivery is held pending until the destination POSTs to that
+// callback URL, or fails once the AckTracker's timeout elapses.
+func (d *MessageDispatcher) SetAckTracker(t *AckTracker) {
+	d.ackTracker = t
+}
+
+// SetTimeouts limits how long each request to a destination may take. dialTimeout bounds
+// establishing the connection, and requestTimeout bounds the whole request, including dialing,
+// sending the message and reading the response. A zero duration means no limit.
+func (d *MessageDispatcher) SetTimeouts(dialTimeout, requestTimeout time.Duration) {
+	d.httpClient = &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   dialTimeout,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		Timeout: requestTimeout,
+	}
 }
 
 // DispatchMessage dispatches a message to a destination over HTTP.
@@ -66,22 +187,66 @@ func NewMessageDispatcher(logger *zap.SugaredLogger) *MessageDispatcher {
 // The destination and replyTo are DNS names. For names with a single label,
 // the default namespace is used to expand it into a fully qualified name
 // within the cluster.
+//
+// Every delivery carries a correlation ID, which is propagated in the Knative-Correlation-Id
+// header and included in all log lines for the delivery. It is taken from the message's
+// Knative-Correlation-Id header, or else from its CloudEvent ID, or else generated.
+//
+// Every request carries the number of the attempt in the CE-X-Knative-Attempt header.
 func (d *MessageDispatcher) DispatchMessage(message *Message, destination, replyTo string, defaults DispatchDefaults) error {
+	message = withCorrelationID(message)
+	logger := d.logger.With(zap.String(correlationIDLogKey, message.Headers[correlationIDHeaderName]))
+	return d.dispatch(context.Background(), logger, message, destination, replyTo, RetryConfig{}, defaults)
+}
+
+// DispatchMessageWithDeadLetter dispatches a message like DispatchMessage. If the message can't be
+// delivered to the destination, or its reply can't be forwarded to replyTo, the original message
+// is sent to deadLetterSink instead. An error is only returned if that fails too. An empty
+// deadLetterSink behaves like DispatchMessage.
+func (d *MessageDispatcher) DispatchMessageWithDeadLetter(message *Message, destination, replyTo, deadLetterSink string, defaults DispatchDefaults) error {
+	return d.DispatchMessageWithRetries(message, destination, replyTo, deadLetterSink, RetryConfig{}, defaults)
+}
+
+// DispatchMessageWithRetries dispatches a message like DispatchMessageWithDeadLetter, but retries
+// failed requests to the destination and to replyTo as configured by retry. The message is only
+// sent to deadLetterSink once the retries are exhausted, unless retry's Timeout elapsed first.
+func (d *MessageDispatcher) DispatchMessageWithRetries(message *Message, destination, replyTo, deadLetterSink string, retry RetryConfig, defaults DispatchDefaults) error {
+	message = withCorrelationID(message)
+	logger := d.logger.With(zap.String(correlationIDLogKey, message.Headers[correlationIDHeaderName]))
+	ctx, cancel := retry.context()
+	defer cancel()
+	err := d.dispatch(ctx, logger, message, destination, replyTo, retry, defaults)
+	if err == nil || deadLetterSink == "" {
+		return err
+	}
+
+	deadLetterURL := d.resolveURL(deadLetterSink, defaults.Namespace)
+	logger.Infof("Sending undeliverable message to the dead-letter sink %s: %v", deadLetterURL.String(), err)
+	if _, dlErr := d.executeRequest(ctx, logger, deadLetterURL, message, nil); dlErr != nil {
+		return fmt.Errorf("%v, and failed to send it to the dead-letter sink: %v", err, dlErr)
+	}
+	return nil
+}
+
+// dispatch sends the message to the destination, and the reply to replyTo.
+func (d *MessageDispatcher) dispatch(ctx context.Context, logger *zap.SugaredLogger, message *Message, destination, replyTo string, retry RetryConfig, defaults DispatchDefaults) error {
 	var err error
+
 	// Default to replying with the original message. If there is a destination, then replace it
 	// with the response from the call to the destination instead.
 	reply := message
 	if destination != "" {
 		destinationURL := d.resolveURL(destination, defaults.Namespace)
-		reply, err = d.executeRequest(destinationURL, message)
+		reply, err = d.executeRequestWithRetries(ctx, logger, destinationURL, message, d.ackTracker, retry)
 		if err != nil {
+			logger.Infof("Unable to complete request to %s: %v", destinationURL.String(), err)
 			return fmt.Errorf("Unable to complete request %v", err)
 		}
 	}
 
 	if replyTo != "" && reply != nil {
 		replyToURL := d.resolveURL(replyTo, defaults.Namespace)
-		_, err = d.executeRequest(replyToURL, reply)
+		_, err = d.executeRequestWithRetries(ctx, logger, replyToURL, reply, nil, retry)
 		if err != nil {
 			return fmt.Errorf("Failed to forward reply %v", err)
 		}
@@ -89,13 +254,43 @@ func (d *MessageDispatcher) DispatchMessage(message *Message, destination, reply
 	return nil
 }
 
-func (d *MessageDispatcher) executeRequest(url *url.URL, message *Message) (*Message, error) {
-	d.logger.Infof("Dispatching message to %s", url.String())
+// executeRequestWithRetries sends the message to url, retrying as configured by retry until a
+// request succeeds or ctx is done. Each request carries the number of its attempt.
+func (d *MessageDispatcher) executeRequestWithRetries(ctx context.Context, logger *zap.SugaredLogger, url *url.URL, message *Message, ackTracker *AckTracker, retry RetryConfig) (*Message, error) {
+	for attempt := 1; ; attempt++ {
+		reply, err := d.executeRequest(ctx, logger, url, withAttempt(message, attempt), ackTracker)
+		if err == nil || attempt > retry.Retry {
+			return reply, err
+		}
+		backoff := retry.backoff(attempt)
+		logger.Infof("Attempt %d to %s failed, retrying in %v: %v", attempt, url.String(), backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%v, and gave up retrying: %v", err, ctx.Err())
+		}
+	}
+}
+
+// executeRequest sends the message to url, canceling the request once ctx is done. If ackTracker
+// is non-nil, the destination may acknowledge the message asynchronously.
+func (d *MessageDispatcher) executeRequest(ctx context.Context, logger *zap.SugaredLogger, url *url.URL, message *Message, ackTracker *AckTracker) (*Message, error) {
+	logger.Infof("Dispatching message to %s", url.String())
 	req, err := http.NewRequest(http.MethodPost, url.String(), bytes.NewReader(message.Payload))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create request %v", err)
 	}
+	req = req.WithContext(ctx)
 	req.Header = d.toHTTPHeaders(message.Headers)
+	ackID := ""
+	if ackTracker != nil {
+		// Register before sending, the destination may acknowledge before it responds.
+		ackID = ackTracker.Register()
+		defer ackTracker.Forget(ackID)
+		req.Header.Set(ackCallbackHeaderName, ackTracker.CallbackURL(ackID))
+	}
 	res, err := d.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -120,12 +315,60 @@ func (d *MessageDispatcher) executeRequest(url *url.URL, message *Message) (*Mes
 		return nil, fmt.Errorf("Unable to read response %v", err)
 	}
 	if len(payload) == 0 {
+		if ackID != "" && res.StatusCode == http.StatusAccepted {
+			// The destination will acknowledge the event asynchronously.
+			logger.Infof("Waiting for acknowledgment from %s", url.String())
+			return nil, ackTracker.Wait(ctx, ackID)
+		}
 		// The response body is empty, the event has 'finished'.
 		return nil, nil
 	}
 	return &Message{headers, payload}, nil
 }
 
+// withCorrelationID returns a copy of message whose headers carry a correlation ID. The message's
+// headers are not modified, as they may be shared by concurrent deliveries.
+func withCorrelationID(message *Message) *Message {
+	headers := make(map[string]string, len(message.Headers)+1)
+	correlationID, eventID := "", ""
+	for h, v := range message.Headers {
+		switch {
+		case strings.EqualFold(h, correlationIDHeaderName):
+			correlationID = v
+			continue
+		case strings.EqualFold(h, cloudEventIDHeaderName):
+			eventID = v
+		}
+		headers[h] = v
+	}
+	if correlationID == "" {
+		correlationID = eventID
+	}
+	if correlationID == "" {
+		correlationID = uuid.New().String()
+	}
+	headers[correlationIDHeaderName] = correlationID
+	return &Message{
+		Headers: headers,
+		Payload: message.Payload,
+	}
+}
+
+// withAttempt returns a copy of message whose headers carry the number of the delivery attempt.
+func withAttempt(message *Message, attempt int) *Message {
+	headers := make(map[string]string, len(message.Headers)+1)
+	for h, v := range message.Headers {
+		if !strings.EqualFold(h, attemptHeaderName) {
+			headers[h] = v
+		}
+	}
+	headers[attemptHeaderName] = strconv.Itoa(attempt)
+	return &Message{
+		Headers: headers,
+		Payload: message.Payload,
+	}
+}
+
 // isFailure returns true if the status code is not a successful HTTP status.
 func isFailure(statusCode int) bool {
 	return statusCode < http.StatusOK /* 200 */ ||
@@ -18,14 +18,18 @@ package buses
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/google/go-cmp/cmp"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -35,6 +39,10 @@ var (
 		"content-length":  {},
 		"content-type":    {},
 		"user-agent":      {},
+		// Checked by TestDispatchMessage_CorrelationID.
+		"knative-correlation-id": {},
+		// Checked by TestDispatchMessageWithRetries.
+		"ce-x-knative-attempt": {},
 	}
 )
 
@@ -299,6 +307,87 @@ func TestDispatchMessage(t *testing.T) {
 	}
 }
 
+func TestDispatchMessage_CorrelationID(t *testing.T) {
+	testCases := map[string]struct {
+		headers               map[string]string
+		expectedCorrelationID string
+	}{
+		"existing correlation ID": {
+			headers: map[string]string{
+				"Knative-Correlation-Id": "correlation-id",
+				"Ce-Eventid":             "event-id",
+			},
+			expectedCorrelationID: "correlation-id",
+		},
+		"CloudEvent ID": {
+			headers: map[string]string{
+				"Ce-Eventid": "event-id",
+			},
+			expectedCorrelationID: "event-id",
+		},
+		"generated": {
+			headers: map[string]string{},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destHandler := &fakeHandler{
+				t: t,
+				response: &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("destination-response")),
+				},
+				requests: make([]requestValidation, 0),
+			}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+			replyHandler := &fakeHandler{
+				t:        t,
+				requests: make([]requestValidation, 0),
+			}
+			replyServer := httptest.NewServer(replyHandler)
+			defer replyServer.Close()
+
+			var logs bytes.Buffer
+			core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&logs), zap.DebugLevel)
+			md := NewMessageDispatcher(zap.New(core).Sugar())
+			err := md.DispatchMessage(&Message{Headers: tc.headers, Payload: []byte("destination")},
+				getDomain(t, true, destServer.URL),
+				getDomain(t, true, replyServer.URL),
+				DispatchDefaults{})
+			if err != nil {
+				t.Errorf("Unexpected error from DispatchMessage: %v", err)
+			}
+
+			destID := destHandler.popRequest(t).Headers.Get(correlationIDHeaderName)
+			replyID := replyHandler.popRequest(t).Headers.Get(correlationIDHeaderName)
+			if destID == "" {
+				t.Errorf("Expected a correlation ID on the destination request")
+			}
+			if tc.expectedCorrelationID != "" && destID != tc.expectedCorrelationID {
+				t.Errorf("Unexpected destination correlation ID. Expected %q. Actual %q", tc.expectedCorrelationID, destID)
+			}
+			if replyID != destID {
+				t.Errorf("Unexpected reply correlation ID. Expected %q. Actual %q", destID, replyID)
+			}
+
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			if len(lines) == 0 {
+				t.Errorf("Expected log lines for the delivery")
+			}
+			for _, line := range lines {
+				entry := map[string]interface{}{}
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("Unable to parse log line %q: %v", line, err)
+				}
+				if entry[correlationIDLogKey] != destID {
+					t.Errorf("Unexpected correlation ID in log line %q. Expected %q", line, destID)
+				}
+			}
+		})
+	}
+}
+
 func getDomain(t *testing.T, shouldSend bool, serverURL string) string {
 	if shouldSend {
 		server, err := url.Parse(serverURL)
@@ -387,3 +476,288 @@ func canonicalizeHeaders(rvs ...requestValidation) {
 		}
 	}
 }
+
+func TestDispatchMessage_Timeouts(t *testing.T) {
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer slowServer.Close()
+
+	testCases := map[string]struct {
+		dialTimeout    time.Duration
+		requestTimeout time.Duration
+		defaults       bool
+		expectedErr    string
+	}{
+		"no timeouts": {},
+		"dial timeout": {
+			// Any connection attempt exceeds a deadline this short.
+			dialTimeout:    time.Nanosecond,
+			requestTimeout: time.Minute,
+			expectedErr:    "dial tcp",
+		},
+		"request timeout": {
+			dialTimeout:    time.Minute,
+			requestTimeout: 50 * time.Millisecond,
+			expectedErr:    "Client.Timeout exceeded",
+		},
+		"default request timeout": {
+			dialTimeout:    time.Minute,
+			requestTimeout: 50 * time.Millisecond,
+			defaults:       true,
+			expectedErr:    "Client.Timeout exceeded",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var md *MessageDispatcher
+			if tc.defaults {
+				defer func(dial, request time.Duration) {
+					DefaultDialTimeout, DefaultRequestTimeout = dial, request
+				}(DefaultDialTimeout, DefaultRequestTimeout)
+				DefaultDialTimeout, DefaultRequestTimeout = tc.dialTimeout, tc.requestTimeout
+				md = NewMessageDispatcher(zap.NewNop().Sugar())
+			} else {
+				md = NewMessageDispatcher(zap.NewNop().Sugar())
+				md.SetTimeouts(tc.dialTimeout, tc.requestTimeout)
+			}
+			err := md.DispatchMessage(&Message{Payload: []byte("destination")},
+				getDomain(t, true, slowServer.URL), "", DispatchDefaults{})
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error from DispatchMessage: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("Expected an error containing %q. Actual %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageWithDeadLetter(t *testing.T) {
+	testCases := map[string]struct {
+		destinationStatus int
+		replyStatus       int
+		deadLetterStatus  int
+		noDeadLetterSink  bool
+		expectDeadLetter  bool
+		expectedErr       bool
+	}{
+		"delivered": {
+			destinationStatus: http.StatusOK,
+			replyStatus:       http.StatusOK,
+		},
+		"destination fails": {
+			destinationStatus: http.StatusInternalServerError,
+			deadLetterStatus:  http.StatusOK,
+			expectDeadLetter:  true,
+		},
+		"reply fails": {
+			destinationStatus: http.StatusOK,
+			replyStatus:       http.StatusInternalServerError,
+			deadLetterStatus:  http.StatusOK,
+			expectDeadLetter:  true,
+		},
+		"dead-letter sink fails": {
+			destinationStatus: http.StatusInternalServerError,
+			deadLetterStatus:  http.StatusInternalServerError,
+			expectDeadLetter:  true,
+			expectedErr:       true,
+		},
+		"no dead-letter sink": {
+			destinationStatus: http.StatusInternalServerError,
+			noDeadLetterSink:  true,
+			expectedErr:       true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.destinationStatus)
+				w.Write([]byte("reply"))
+			}))
+			defer destination.Close()
+			reply := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.replyStatus)
+			}))
+			defer reply.Close()
+			var deadLetters []string
+			deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				deadLetters = append(deadLetters, string(body))
+				w.WriteHeader(tc.deadLetterStatus)
+			}))
+			defer deadLetter.Close()
+
+			deadLetterSink := getDomain(t, true, deadLetter.URL)
+			if tc.noDeadLetterSink {
+				deadLetterSink = ""
+			}
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			err := md.DispatchMessageWithDeadLetter(&Message{Payload: []byte("event")},
+				getDomain(t, true, destination.URL), getDomain(t, true, reply.URL), deadLetterSink, DispatchDefaults{})
+			if tc.expectedErr != (err != nil) {
+				t.Errorf("Unexpected error from DispatchMessageWithDeadLetter. Expected %v. Actual: %v", tc.expectedErr, err)
+			}
+			var want []string
+			if tc.expectDeadLetter {
+				// The original event is dead-lettered, not the reply.
+				want = []string{"event"}
+			}
+			if diff := cmp.Diff(want, deadLetters); diff != "" {
+				t.Errorf("Unexpected dead letters (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageWithRetries(t *testing.T) {
+	testCases := map[string]struct {
+		retry            int
+		failures         int
+		expectedAttempts []string
+		expectDeadLetter bool
+	}{
+		"no retries": {
+			failures:         0,
+			expectedAttempts: []string{"1"},
+		},
+		"succeeds after retries": {
+			retry:            3,
+			failures:         2,
+			expectedAttempts: []string{"1", "2", "3"},
+		},
+		"retries exhausted": {
+			retry:            2,
+			failures:         5,
+			expectedAttempts: []string{"1", "2", "3"},
+			expectDeadLetter: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var attempts []string
+			destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts = append(attempts, r.Header.Get("CE-X-Knative-Attempt"))
+				if len(attempts) <= tc.failures {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer destination.Close()
+			var deadLetters []string
+			deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				deadLetters = append(deadLetters, string(body))
+			}))
+			defer deadLetter.Close()
+
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			retry := RetryConfig{
+				Retry:         tc.retry,
+				BackoffPolicy: BackoffPolicyLinear,
+				BackoffDelay:  time.Millisecond,
+			}
+			err := md.DispatchMessageWithRetries(&Message{Payload: []byte("event")},
+				getDomain(t, true, destination.URL), "", getDomain(t, true, deadLetter.URL), retry, DispatchDefaults{})
+			if err != nil {
+				t.Errorf("Unexpected error from DispatchMessageWithRetries: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedAttempts, attempts); diff != "" {
+				t.Errorf("Unexpected attempts (-want +got): %s", diff)
+			}
+			var want []string
+			if tc.expectDeadLetter {
+				want = []string{"event"}
+			}
+			if diff := cmp.Diff(want, deadLetters); diff != "" {
+				t.Errorf("Unexpected dead letters (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageWithRetries_Timeout(t *testing.T) {
+	var attempts atomic.Int32
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Inc()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer destination.Close()
+	var deadLettered atomic.Int32
+	deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadLettered.Inc()
+	}))
+	defer deadLetter.Close()
+
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	retry := RetryConfig{
+		Retry:         100,
+		BackoffPolicy: BackoffPolicyLinear,
+		BackoffDelay:  20 * time.Millisecond,
+		Timeout:       100 * time.Millisecond,
+	}
+	start := time.Now()
+	err := md.DispatchMessageWithRetries(&Message{Payload: []byte("event")},
+		getDomain(t, true, destination.URL), "", getDomain(t, true, deadLetter.URL), retry, DispatchDefaults{})
+	if err == nil {
+		t.Errorf("Expected an error once the timeout elapsed")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected DispatchMessageWithRetries to give up after its timeout, it took %v", elapsed)
+	}
+	if got := attempts.Load(); got < 2 || got > 6 {
+		t.Errorf("Unexpected number of attempts within the timeout: %d", got)
+	}
+	if got := deadLettered.Load(); got != 0 {
+		t.Errorf("Expected nothing to be dead-lettered after the timeout, got %d", got)
+	}
+}
+
+func TestRetryConfigBackoff(t *testing.T) {
+	testCases := map[string]struct {
+		policy   BackoffPolicy
+		delay    time.Duration
+		expected []time.Duration
+	}{
+		"linear": {
+			policy:   BackoffPolicyLinear,
+			expected: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second},
+		},
+		"exponential": {
+			policy:   BackoffPolicyExponential,
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		"default": {
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		"linear, capped": {
+			policy:   BackoffPolicyLinear,
+			delay:    25 * time.Second,
+			expected: []time.Duration{25 * time.Second, 50 * time.Second, time.Minute, time.Minute},
+		},
+		"exponential, capped": {
+			policy:   BackoffPolicyExponential,
+			delay:    25 * time.Second,
+			expected: []time.Duration{25 * time.Second, 50 * time.Second, time.Minute, time.Minute},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if tc.delay == 0 {
+				tc.delay = time.Second
+			}
+			c := RetryConfig{BackoffPolicy: tc.policy, BackoffDelay: tc.delay}
+			var actual []time.Duration
+			for retry := 1; retry <= len(tc.expected); retry++ {
+				actual = append(actual, c.backoff(retry))
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("Unexpected backoff (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -27,10 +27,12 @@ import (
 
 type EventingV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	BrokersGetter
 	ChannelsGetter
 	ClusterProvisionersGetter
 	SourcesGetter
 	SubscriptionsGetter
+	TriggersGetter
 }
 
 // EventingV1alpha1Client is used to interact with features provided by the eventing.knative.dev group.
@@ -38,6 +40,10 @@ type EventingV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *EventingV1alpha1Client) Brokers(namespace string) BrokerInterface {
+	return newBrokers(c, namespace)
+}
+
 func (c *EventingV1alpha1Client) Channels(namespace string) ChannelInterface {
 	return newChannels(c, namespace)
 }
@@ -54,6 +60,10 @@ func (c *EventingV1alpha1Client) Subscriptions(namespace string) SubscriptionInt
 	return newSubscriptions(c, namespace)
 }
 
+func (c *EventingV1alpha1Client) Triggers(namespace string) TriggerInterface {
+	return newTriggers(c, namespace)
+}
+
 // NewForConfig creates a new EventingV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*EventingV1alpha1Client, error) {
 	config := *c
@@ -18,6 +18,8 @@ limitations under the License.
 
 package v1alpha1
 
+type BrokerExpansion interface{}
+
 type ChannelExpansion interface{}
 
 type ClusterProvisionerExpansion interface{}
@@ -25,3 +27,5 @@ type ClusterProvisionerExpansion interface{}
 type SourceExpansion interface{}
 
 type SubscriptionExpansion interface{}
+
+type TriggerExpansion interface{}
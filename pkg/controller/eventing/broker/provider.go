@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "broker-controller"
+)
+
+type reconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+	logger   *zap.Logger
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a Broker controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return nil, err
+	}
+	logger = logger.With(zap.String("controller", controllerAgentName))
+
+	// Setup a new controller to Reconcile Brokers.
+	c, err := controller.New(controllerAgentName, mgr, controller.Options{
+		Reconciler: &reconciler{
+			recorder: mgr.GetRecorder(controllerAgentName),
+			logger:   logger,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch Brokers.
+	if err := c.Watch(&source.Kind{Type: &eventingv1alpha1.Broker{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch the Channels that are owned by Brokers.
+	if err := c.Watch(&source.Kind{Type: &eventingv1alpha1.Channel{}}, &handler.EnqueueRequestForOwner{OwnerType: &eventingv1alpha1.Broker{}, IsController: true}); err != nil {
+		return nil, err
+	}
+
+	// Watch Triggers, as they are the subscribers of their Broker's Channel.
+	if err := c.Watch(&source.Kind{Type: &eventingv1alpha1.Trigger{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: triggerToBroker{}}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
+
+// triggerToBroker maps a Trigger to the Broker it receives events from.
+type triggerToBroker struct{}
+
+func (triggerToBroker) Map(obj handler.MapObject) []reconcile.Request {
+	t, ok := obj.Object.(*eventingv1alpha1.Trigger)
+	if !ok {
+		// This wasn't a Trigger.
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: t.Namespace,
+			Name:      t.Spec.Broker,
+		},
+	}}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type object interface {
+	metav1.Object
+	runtime.Object
+}
+
+func TestTriggerToBroker(t *testing.T) {
+	testCases := []struct {
+		name string
+		obj  object
+		rr   []reconcile.Request
+	}{{
+		name: "trigger",
+		obj: &eventingv1alpha1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "t"},
+			Spec:       eventingv1alpha1.TriggerSpec{Broker: "mesh"},
+		},
+		rr: []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: testNS, Name: "mesh"},
+		}},
+	}, {
+		name: "not a trigger",
+		obj: &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "svc"},
+		},
+		rr: []reconcile.Request{},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := triggerToBroker{}.Map(handler.MapObject{Meta: tc.obj, Object: tc.obj})
+			if diff := cmp.Diff(tc.rr, rr); diff != "" {
+				t.Errorf("unexpected requests (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/system"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// FilterDomain is the domain of the broker filter, which delivers the events of each Broker's
+// Channel to the Broker's Triggers.
+var FilterDomain = controller.ServiceHostName("broker-filter", system.Namespace)
+
+// ChannelName returns the name of the Channel the Broker named brokerName holds its events in.
+func ChannelName(brokerName string) string {
+	return fmt.Sprintf("%s-broker", brokerName)
+}
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Broker resource
+// with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := context.TODO()
+	logger := r.logger.With(zap.Any("request", request))
+
+	b := &eventingv1alpha1.Broker{}
+	err := r.client.Get(ctx, request.NamespacedName, b)
+
+	// The Broker may have been deleted since it was added to the workqueue, or a Trigger may
+	// reference a Broker that doesn't exist. Either way there's nothing to be done.
+	if errors.IsNotFound(err) {
+		logger.Info("Could not find Broker")
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		logger.Error("Could not fetch Broker", zap.Error(err))
+		return reconcile.Result{}, err
+	}
+
+	original := b.DeepCopy()
+
+	// Reconcile this copy of the Broker and then write back any status
+	// updates regardless of whether the reconcile error out.
+	err = r.reconcile(ctx, b)
+	if err != nil {
+		logger.Warn("Error reconciling Broker", zap.Error(err))
+	}
+	if equality.Semantic.DeepEqual(original.Status, b.Status) {
+		// If we didn't change anything then don't call updateStatus.
+		// This is important because the copy we loaded from the informer's
+		// cache may be stale and we don't want to overwrite a prior update
+		// to status with this stale state.
+	} else if _, updateErr := r.updateStatus(ctx, b); updateErr != nil {
+		logger.Warn("Failed to update Broker status", zap.Error(updateErr))
+		return reconcile.Result{}, updateErr
+	}
+
+	// Requeue if the resource is not ready:
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, b *eventingv1alpha1.Broker) error {
+	b.Status.InitializeConditions()
+
+	// The Channel is owned by the Broker, so it is garbage collected once the Broker is deleted.
+	if b.DeletionTimestamp != nil {
+		return nil
+	}
+
+	subscribers, err := r.subscribers(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	ch, err := r.reconcileChannel(ctx, b, subscribers)
+	if err != nil {
+		return err
+	}
+	b.Status.PropagateChannelStatus(ch)
+	return nil
+}
+
+// subscribers returns the subscribers of the Broker's Channel: the broker filter, at the path of
+// each of the Broker's Triggers whose subscriber is resolved, sorted by the Triggers' names.
+// Triggers whose subscriber isn't resolved are left out, as the filter could not deliver to them.
+func (r *reconciler) subscribers(ctx context.Context, b *eventingv1alpha1.Broker) ([]eventingv1alpha1.ChannelSubscriberSpec, error) {
+	triggers, err := r.listTriggers(ctx, b.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].Name < triggers[j].Name
+	})
+	var subscribers []eventingv1alpha1.ChannelSubscriberSpec
+	for _, t := range triggers {
+		if t.Spec.Broker != b.Name || t.DeletionTimestamp != nil || t.Status.SubscriberURI == "" {
+			continue
+		}
+		subscribers = append(subscribers, eventingv1alpha1.ChannelSubscriberSpec{
+			CallableDomain: fmt.Sprintf("http://%s%s", FilterDomain, filter.TriggerPath(t.Namespace, t.Name)),
+		})
+	}
+	return subscribers, nil
+}
+
+// listTriggers lists all the Triggers in the namespace.
+func (r *reconciler) listTriggers(ctx context.Context, namespace string) ([]eventingv1alpha1.Trigger, error) {
+	opts := &client.ListOptions{
+		Namespace: namespace,
+		// Set Raw because if we need to get more than one page, then we will put the continue token
+		// into opts.Raw.Continue.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Trigger",
+			},
+		},
+	}
+	var triggers []eventingv1alpha1.Trigger
+	for {
+		tl := &eventingv1alpha1.TriggerList{}
+		if err := r.client.List(ctx, opts, tl); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, tl.Items...)
+		if tl.Continue == "" {
+			return triggers, nil
+		}
+		opts.Raw.Continue = tl.Continue
+	}
+}
+
+// reconcileChannel creates the Broker's Channel, or updates its subscribers, and returns it.
+func (r *reconciler) reconcileChannel(ctx context.Context, b *eventingv1alpha1.Broker, subscribers []eventingv1alpha1.ChannelSubscriberSpec) (*eventingv1alpha1.Channel, error) {
+	ch := &eventingv1alpha1.Channel{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: b.Namespace, Name: ChannelName(b.Name)}, ch)
+	if errors.IsNotFound(err) {
+		ch = newChannel(b, subscribers)
+		if err := r.client.Create(ctx, ch); err != nil {
+			b.Status.MarkChannelFailed("ChannelCreateFailed", "failed to create the Channel %s: %v", ch.Name, err)
+			return nil, err
+		}
+		r.recorder.Eventf(b, corev1.EventTypeNormal, "ChannelCreated", "Created Channel %q", ch.Name)
+		return ch, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !metav1.IsControlledBy(ch, b) {
+		b.Status.MarkChannelFailed("ChannelNotOwned", "the Channel %s is not owned by the Broker", ch.Name)
+		return nil, fmt.Errorf("broker %s/%s does not own Channel %q", b.Namespace, b.Name, ch.Name)
+	}
+
+	channelable := &eventingv1alpha1.Channelable{Subscribers: subscribers}
+	if !equality.Semantic.DeepEqual(ch.Spec.Channelable, channelable) {
+		ch.Spec.Channelable = channelable
+		if err := r.client.Update(ctx, ch); err != nil {
+			return nil, err
+		}
+	}
+	return ch, nil
+}
+
+// newChannel returns the Channel for the Broker, as specified by its ChannelTemplate.
+func newChannel(b *eventingv1alpha1.Broker, subscribers []eventingv1alpha1.ChannelSubscriberSpec) *eventingv1alpha1.Channel {
+	spec := eventingv1alpha1.ChannelSpec{}
+	if b.Spec.ChannelTemplate != nil {
+		spec = *b.Spec.ChannelTemplate.DeepCopy()
+	}
+	spec.Channelable = &eventingv1alpha1.Channelable{Subscribers: subscribers}
+	return &eventingv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: b.Namespace,
+			Name:      ChannelName(b.Name),
+			Labels: map[string]string{
+				eventingv1alpha1.BrokerLabelKey: b.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*controller.NewControllerRef(b, true),
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, b *eventingv1alpha1.Broker) (*eventingv1alpha1.Broker, error) {
+	newBroker := &eventingv1alpha1.Broker{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Name}, newBroker)
+
+	if err != nil {
+		return nil, err
+	}
+	newBroker.Status = b.Status
+
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the Broker resource. UpdateStatus will not
+	// allow changes to the Spec of the resource, which is ideal for ensuring
+	// nothing other than resource status has been updated.
+	if err = r.client.Update(ctx, newBroker); err != nil {
+		return nil, err
+	}
+	return newBroker, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"fmt"
+	"testing"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	testNS      = "testnamespace"
+	brokerName  = "default"
+	channelName = "default-broker"
+	sinkableDNS = "default-broker-channel.testnamespace.svc.cluster.local"
+)
+
+var deletedTime = metav1.Now()
+
+func init() {
+	// Add types to scheme
+	eventingv1alpha1.AddToScheme(scheme.Scheme)
+	duckv1alpha1.AddToScheme(scheme.Scheme)
+}
+
+var testCases = []controllertesting.TestCase{
+	{
+		Name:         "non existent key",
+		ReconcileKey: "non-existent-test-ns/non-existent-test-key",
+		WantErr:      false,
+	}, {
+		Name: "new broker creates its channel",
+		InitialState: []runtime.Object{
+			getNewBroker(),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, brokerName),
+		WantPresent: []runtime.Object{
+			getNewChannel(nil),
+			getBrokerWithStatus(getNewChannel(nil)),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "triggers become the channel's subscribers",
+		InitialState: []runtime.Object{
+			getNewBroker(),
+			getProvisionedChannel(nil),
+			getTrigger("b-trigger", brokerName, "http://b.example.com/"),
+			getTrigger("a-trigger", brokerName, "http://a.example.com/"),
+			getTrigger("unresolved", brokerName, ""),
+			getTrigger("other-broker", "other", "http://other.example.com/"),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, brokerName),
+		WantPresent: []runtime.Object{
+			getProvisionedChannel(filterSubscribers("a-trigger", "b-trigger")),
+			getBrokerWithStatus(getProvisionedChannel(nil)),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "deleted triggers are removed from the channel's subscribers",
+		InitialState: []runtime.Object{
+			getNewBroker(),
+			getProvisionedChannel(filterSubscribers("a-trigger", "b-trigger")),
+			getTrigger("a-trigger", brokerName, "http://a.example.com/"),
+			getDeletedTrigger("b-trigger", brokerName, "http://b.example.com/"),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, brokerName),
+		WantPresent: []runtime.Object{
+			getProvisionedChannel(filterSubscribers("a-trigger")),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "channel not owned by the broker",
+		InitialState: []runtime.Object{
+			getNewBroker(),
+			getUnownedChannel(),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, brokerName),
+		WantErrMsg:   `broker testnamespace/default does not own Channel "default-broker"`,
+		WantPresent: []runtime.Object{
+			getBrokerWithChannelNotOwned(),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "deleted broker",
+		InitialState: []runtime.Object{
+			getDeletedBroker(),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, brokerName),
+		WantAbsent: []runtime.Object{
+			getNewChannel(nil),
+		},
+	},
+}
+
+func TestAllCases(t *testing.T) {
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			recorder: recorder,
+			logger:   zap.NewNop(),
+		}
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func getNewBroker() *eventingv1alpha1.Broker {
+	b := &eventingv1alpha1.Broker{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Broker",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      brokerName,
+		},
+	}
+	b.SetDefaults()
+	return b
+}
+
+func getDeletedBroker() *eventingv1alpha1.Broker {
+	b := getNewBroker()
+	b.DeletionTimestamp = &deletedTime
+	return b
+}
+
+func getBrokerWithStatus(ch *eventingv1alpha1.Channel) *eventingv1alpha1.Broker {
+	b := getNewBroker()
+	b.Status.InitializeConditions()
+	b.Status.PropagateChannelStatus(ch)
+	return b
+}
+
+func getBrokerWithChannelNotOwned() *eventingv1alpha1.Broker {
+	b := getNewBroker()
+	b.Status.InitializeConditions()
+	b.Status.MarkChannelFailed("ChannelNotOwned", "the Channel %s is not owned by the Broker", channelName)
+	return b
+}
+
+func getNewChannel(subscribers []eventingv1alpha1.ChannelSubscriberSpec) *eventingv1alpha1.Channel {
+	b := getNewBroker()
+	ch := newChannel(b, subscribers)
+	ch.TypeMeta = metav1.TypeMeta{
+		APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+	}
+	return ch
+}
+
+func getProvisionedChannel(subscribers []eventingv1alpha1.ChannelSubscriberSpec) *eventingv1alpha1.Channel {
+	ch := getNewChannel(subscribers)
+	controllertesting.SimulateProvisioned(ch, sinkableDNS)
+	return ch
+}
+
+func getUnownedChannel() *eventingv1alpha1.Channel {
+	ch := getNewChannel(nil)
+	ch.OwnerReferences = nil
+	return ch
+}
+
+func getTrigger(name, broker, subscriberURI string) *eventingv1alpha1.Trigger {
+	return &eventingv1alpha1.Trigger{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Trigger",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      name,
+		},
+		Spec: eventingv1alpha1.TriggerSpec{
+			Broker: broker,
+			Subscriber: &eventingv1alpha1.Callable{
+				TargetURI: &subscriberURI,
+			},
+		},
+		Status: eventingv1alpha1.TriggerStatus{
+			SubscriberURI: subscriberURI,
+		},
+	}
+}
+
+func getDeletedTrigger(name, broker, subscriberURI string) *eventingv1alpha1.Trigger {
+	t := getTrigger(name, broker, subscriberURI)
+	t.DeletionTimestamp = &deletedTime
+	return t
+}
+
+func filterSubscribers(triggerNames ...string) []eventingv1alpha1.ChannelSubscriberSpec {
+	var subscribers []eventingv1alpha1.ChannelSubscriberSpec
+	for _, name := range triggerNames {
+		subscribers = append(subscribers, eventingv1alpha1.ChannelSubscriberSpec{
+			CallableDomain: fmt.Sprintf("http://%s%s", FilterDomain, filter.TriggerPath(testNS, name)),
+		})
+	}
+	return subscribers
+}
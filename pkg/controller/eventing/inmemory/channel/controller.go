@@ -18,6 +18,7 @@ package channel
 
 import (
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/lifecycle"
 	"github.com/knative/eventing/pkg/system"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"go.uber.org/zap"
@@ -45,6 +46,10 @@ var (
 		Namespace: system.Namespace,
 		Name:      ConfigMapName,
 	}
+
+	// LifecycleEventSink is the URI that CloudEvents about the lifecycle of in-memory Channels
+	// are sent to. If empty, no lifecycle events are sent.
+	LifecycleEventSink = ""
 )
 
 // ProvideController returns a Controller that represents the in-memory-channel Provisioner.
@@ -55,6 +60,7 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		configMapKey: defaultConfigMapKey,
 		recorder:     mgr.GetRecorder(controllerAgentName),
 		logger:       logger,
+		emitter:      lifecycle.NewEmitter(LifecycleEventSink),
 	}
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
 		Reconciler: r,
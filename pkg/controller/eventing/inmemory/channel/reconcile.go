@@ -22,6 +22,7 @@ import (
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/controller"
 	cpcontroller "github.com/knative/eventing/pkg/controller/eventing/inmemory/clusterprovisioner"
+	"github.com/knative/eventing/pkg/controller/eventing/lifecycle"
 	"github.com/knative/eventing/pkg/sidecar/configmap"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
@@ -50,6 +51,8 @@ type reconciler struct {
 	client   client.Client
 	recorder record.EventRecorder
 	logger   *zap.Logger
+	// emitter sends lifecycle CloudEvents about Channels. It may be nil.
+	emitter *lifecycle.Emitter
 
 	configMapKey client.ObjectKey
 }
@@ -85,7 +88,7 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 
 	// Does this Controller control this Channel?
 	if !r.shouldReconcile(c) {
-		logger.Info("Not reconciling Channel, it is not controlled by this Controller", zap.Any("ref", c.Spec))
+		logger.Info("Not reconciling Channel, it is not controlled by this Controller", zap.Stringer("provisioner", c.Spec.Provisioner))
 		return reconcile.Result{}, nil
 	}
 	logger.Info("Reconciling Channel")
@@ -119,7 +122,11 @@ func (r *reconciler) shouldReconcile(c *eventingv1alpha1.Channel) bool {
 
 func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel) error {
 	logger := r.logger.With(zap.Any("channel", c))
+	wasReady := c.Status.IsReady()
+	hadFinalizer := sets.NewString(c.Finalizers...).Has(finalizerName)
 
+	// In-memory Channels have no provisioner-specific conditions.
+	c.Status.ClearStaleConditions()
 	c.Status.InitializeConditions()
 
 	// We are syncing three things:
@@ -137,11 +144,24 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 		// K8s garbage collection will delete the K8s service and VirtualService for this channel.
 		// We use a finalizer to ensure the channel config has been synced.
 		r.removeFinalizer(c)
+		if hadFinalizer {
+			r.emitLifecycleEvent(logger, r.emitter.ChannelDeleted, c)
+		}
 		return nil
 	}
 
 	r.addFinalizer(c)
-	c.Status.SetSubscribable(c.Namespace, c.Name)
+	if !hadFinalizer {
+		r.emitLifecycleEvent(logger, r.emitter.ChannelCreated, c)
+	}
+	if err := r.setSpecHash(c); err != nil {
+		logger.Info("Error hashing the Channel's spec", zap.Error(err))
+		return err
+	}
+	if err := c.MarkSelfSubscribable(); err != nil {
+		logger.Info("Error making the Channel Subscribable", zap.Error(err))
+		return err
+	}
 
 	if svc, err := r.createK8sService(ctx, c); err != nil {
 		logger.Info("Error creating the Channel's K8s Service", zap.Error(err))
@@ -155,10 +175,24 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 		return err
 	}
 
+	// In-memory Channels take no arguments, so every version of the provisioner accepts them.
+	c.Status.MarkCompatible()
 	c.Status.MarkProvisioned()
+	c.Status.ObserveGeneration(c.Spec.Generation)
+	if !wasReady && c.Status.IsReady() {
+		r.emitLifecycleEvent(logger, r.emitter.ChannelReady, c)
+	}
 	return nil
 }
 
+// emitLifecycleEvent calls emit on the Channel. Failing to emit a lifecycle event doesn't fail the
+// reconciliation, so it is only logged.
+func (r *reconciler) emitLifecycleEvent(logger *zap.Logger, emit func(*eventingv1alpha1.Channel) error, c *eventingv1alpha1.Channel) {
+	if err := emit(c); err != nil {
+		logger.Info("Error emitting a lifecycle event", zap.Error(err))
+	}
+}
+
 func (r *reconciler) addFinalizer(c *eventingv1alpha1.Channel) {
 	finalizers := sets.NewString(c.Finalizers...)
 	finalizers.Insert(finalizerName)
@@ -171,6 +205,19 @@ func (r *reconciler) removeFinalizer(c *eventingv1alpha1.Channel) {
 	c.Finalizers = finalizers.List()
 }
 
+// setSpecHash records the hash of the Channel's spec in its SpecHashAnnotation.
+func (r *reconciler) setSpecHash(c *eventingv1alpha1.Channel) error {
+	hash, err := c.SpecHash()
+	if err != nil {
+		return err
+	}
+	if c.Annotations == nil {
+		c.Annotations = make(map[string]string)
+	}
+	c.Annotations[eventingv1alpha1.SpecHashAnnotation] = hash
+	return nil
+}
+
 func (r *reconciler) getK8sService(ctx context.Context, c *eventingv1alpha1.Channel) (*corev1.Service, error) {
 	svcKey := types.NamespacedName{
 		Namespace: c.Namespace,
@@ -194,8 +241,15 @@ func (r *reconciler) createK8sService(ctx context.Context, c *eventingv1alpha1.C
 		return nil, err
 	}
 
-	// Check if this Channel is the owner of the K8s service.
-	if !metav1.IsControlledBy(svc, c) {
+	// Older versions of this controller may have created the K8s Service without an owner, so
+	// backfill it to have the Service garbage collected with the Channel. Only a Service labeled
+	// as created for this Channel is adopted, not any Service that happens to have its name.
+	if metav1.GetControllerOf(svc) == nil && createdForChannel(svc, c) {
+		svc.OwnerReferences = append(svc.OwnerReferences, *newChannelControllerRef(c))
+		if err := r.client.Update(ctx, svc); err != nil {
+			return nil, err
+		}
+	} else if !metav1.IsControlledBy(svc, c) {
 		r.logger.Warn("Channel's K8s Service is not owned by the Channel", zap.Any("channel", c), zap.Any("service", svc))
 	}
 	return svc, nil
@@ -227,33 +281,62 @@ func (r *reconciler) createVirtualService(ctx context.Context, c *eventingv1alph
 		return err
 	}
 
-	// If the Virtual Service is not controlled by this Channel, we should log a warning, but don't
-	// consider it an error.
-	if !metav1.IsControlledBy(virtualService, c) {
+	// Older versions of this controller may have created the VirtualService without an owner, so
+	// backfill it to have the VirtualService garbage collected with the Channel. Only a
+	// VirtualService labeled as created for this Channel is adopted. If it is controlled by
+	// something else, we should log a warning, but don't consider it an error.
+	if metav1.GetControllerOf(virtualService) == nil && createdForChannel(virtualService, c) {
+		virtualService.OwnerReferences = append(virtualService.OwnerReferences, *newChannelControllerRef(c))
+		if err := r.client.Update(ctx, virtualService); err != nil {
+			return err
+		}
+	} else if !metav1.IsControlledBy(virtualService, c) {
 		r.logger.Warn("VirtualService not owned by Channel", zap.Any("channel", c), zap.Any("virtualService", virtualService))
 	}
 	return nil
 }
 
+// newChannelControllerRef returns an OwnerReference making the Channel the controller of the
+// resource it is set on.
+func newChannelControllerRef(c *eventingv1alpha1.Channel) *metav1.OwnerReference {
+	return metav1.NewControllerRef(c, schema.GroupVersionKind{
+		Group:   eventingv1alpha1.SchemeGroupVersion.Group,
+		Version: eventingv1alpha1.SchemeGroupVersion.Version,
+		Kind:    "Channel",
+	})
+}
+
+// channelLabels returns the labels set on the resources created for a Channel.
+func channelLabels(c *eventingv1alpha1.Channel) map[string]string {
+	return map[string]string{
+		"channel":     c.Name,
+		"provisioner": c.Spec.Provisioner.Ref.Name,
+	}
+}
+
+// createdForChannel returns whether o carries the labels this controller sets on the resources it
+// creates for the Channel c.
+func createdForChannel(o metav1.Object, c *eventingv1alpha1.Channel) bool {
+	l := o.GetLabels()
+	for k, v := range channelLabels(c) {
+		if l[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
 // newK8sService creates a new Service for a Channel resource. It also sets the appropriate
 // OwnerReferences on the resource so handleObject can discover the Channel resource that 'owns' it.
 // As well as being garbage collected when the Channel is deleted.
 func newK8sService(c *eventingv1alpha1.Channel) *corev1.Service {
-	labels := map[string]string{
-		"channel":     c.Name,
-		"provisioner": c.Spec.Provisioner.Ref.Name,
-	}
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      controller.ChannelServiceName(c.ObjectMeta.Name),
 			Namespace: c.Namespace,
-			Labels:    labels,
+			Labels:    channelLabels(c),
 			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(c, schema.GroupVersionKind{
-					Group:   eventingv1alpha1.SchemeGroupVersion.Group,
-					Version: eventingv1alpha1.SchemeGroupVersion.Version,
-					Kind:    "Channel",
-				}),
+				*newChannelControllerRef(c),
 			},
 		},
 		Spec: corev1.ServiceSpec{
@@ -271,22 +354,14 @@ func newK8sService(c *eventingv1alpha1.Channel) *corev1.Service {
 // appropriate OwnerReferences on the resource so handleObject can discover the Channel resource
 // that 'owns' it. As well as being garbage collected when the Channel is deleted.
 func newVirtualService(channel *eventingv1alpha1.Channel) *istiov1alpha3.VirtualService {
-	labels := map[string]string{
-		"channel":     channel.Name,
-		"provisioner": channel.Spec.Provisioner.Ref.Name,
-	}
 	destinationHost := controller.ServiceHostName(controller.ClusterBusDispatcherServiceName(channel.Spec.Provisioner.Ref.Name), system.Namespace)
 	return &istiov1alpha3.VirtualService{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      controller.ChannelVirtualServiceName(channel.Name),
 			Namespace: channel.Namespace,
-			Labels:    labels,
+			Labels:    channelLabels(channel),
 			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(channel, schema.GroupVersionKind{
-					Group:   eventingv1alpha1.SchemeGroupVersion.Group,
-					Version: eventingv1alpha1.SchemeGroupVersion.Version,
-					Kind:    "Channel",
-				}),
+				*newChannelControllerRef(channel),
 			},
 		},
 		Spec: istiov1alpha3.VirtualServiceSpec{
@@ -323,6 +398,13 @@ func (r *reconciler) updateChannel(ctx context.Context, u *eventingv1alpha1.Chan
 		updated = true
 		o.SetFinalizers(u.Finalizers)
 	}
+	if hash, ok := u.Annotations[eventingv1alpha1.SpecHashAnnotation]; ok && o.Annotations[eventingv1alpha1.SpecHashAnnotation] != hash {
+		updated = true
+		if o.Annotations == nil {
+			o.Annotations = make(map[string]string)
+		}
+		o.Annotations[eventingv1alpha1.SpecHashAnnotation] = hash
+	}
 	if !equality.Semantic.DeepEqual(o.Status, u.Status) {
 		updated = true
 		o.Status = u.Status
@@ -40,6 +40,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -73,7 +74,7 @@ var (
 				Namespace: cNamespace,
 				Name:      "c1",
 				FanoutConfig: fanout.Config{
-					Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+					Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							CallableDomain: "foo",
 						},
@@ -91,7 +92,7 @@ var (
 				Namespace: cNamespace,
 				Name:      "c3",
 				FanoutConfig: fanout.Config{
-					Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+					Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							CallableDomain: "steve",
 						},
@@ -116,8 +117,8 @@ var (
 						Name: cpName,
 					},
 				},
-				Channelable: &duckv1alpha1.Channelable{
-					Subscribers: []duckv1alpha1.ChannelSubscriberSpec{
+				Channelable: &eventingv1alpha1.Channelable{
+					Subscribers: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							CallableDomain: "foo",
 						},
@@ -146,8 +147,8 @@ var (
 						Name: "some-other-provisioner",
 					},
 				},
-				Channelable: &duckv1alpha1.Channelable{
-					Subscribers: []duckv1alpha1.ChannelSubscriberSpec{
+				Channelable: &eventingv1alpha1.Channelable{
+					Subscribers: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							CallableDomain: "anything",
 						},
@@ -169,8 +170,8 @@ var (
 						Name: cpName,
 					},
 				},
-				Channelable: &duckv1alpha1.Channelable{
-					Subscribers: []duckv1alpha1.ChannelSubscriberSpec{
+				Channelable: &eventingv1alpha1.Channelable{
+					Subscribers: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							CallableDomain: "steve",
 						},
@@ -334,7 +335,7 @@ func TestReconcile(t *testing.T) {
 			WantErrMsg: testErrorMessage,
 		},
 		{
-			Name: "K8s service already exists - not owned by Channel",
+			Name: "K8s service already exists - without an owner, owner backfilled",
 			InitialState: []runtime.Object{
 				makeChannel(),
 				makeConfigMap(),
@@ -342,6 +343,31 @@ func TestReconcile(t *testing.T) {
 			},
 			WantPresent: []runtime.Object{
 				makeReadyChannel(),
+				makeK8sService(),
+			},
+		},
+		{
+			Name: "K8s service already exists - without an owner or labels, not adopted",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sServiceNotCreatedForChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sServiceNotCreatedForChannel(),
+			},
+		},
+		{
+			Name: "K8s service already exists - owned by something else",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sServiceOwnedBySomethingElse(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sServiceOwnedBySomethingElse(),
 			},
 		},
 		{
@@ -380,7 +406,7 @@ func TestReconcile(t *testing.T) {
 			WantErrMsg: testErrorMessage,
 		},
 		{
-			Name: "VirtualService already exists - not owned by Channel",
+			Name: "VirtualService already exists - without an owner, owner backfilled",
 			InitialState: []runtime.Object{
 				makeChannel(),
 				makeConfigMap(),
@@ -389,6 +415,20 @@ func TestReconcile(t *testing.T) {
 			},
 			WantPresent: []runtime.Object{
 				makeReadyChannel(),
+				makeVirtualService(),
+			},
+		},
+		{
+			Name: "VirtualService already exists - without an owner or labels, not adopted",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualServiceNotCreatedForChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeVirtualServiceNotCreatedForChannel(),
 			},
 		},
 		{
@@ -438,6 +478,57 @@ func TestReconcile(t *testing.T) {
 				makeConfigMapWithVerifyConfigMapData(),
 			},
 		},
+		{
+			Name: "Channel reconcile successful - spec hash updated",
+			InitialState: []runtime.Object{
+				makeReadyChannelWithStaleSpecHash(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists:   (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+				MockUpdates: verifyConfigMapData(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeConfigMapWithVerifyConfigMapData(),
+			},
+		},
+		{
+			Name: "Channel reconcile successful - new generation observed",
+			InitialState: []runtime.Object{
+				makeReadyChannelWithNewGeneration(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists:   (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+				MockUpdates: verifyConfigMapData(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannelWithObservedGeneration(),
+				makeConfigMapWithVerifyConfigMapData(),
+			},
+		},
+		{
+			Name: "Channel reconcile successful - stale conditions cleared",
+			InitialState: []runtime.Object{
+				makeChannelWithStaleCondition(),
+				makeConfigMap(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists:   (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+				MockUpdates: verifyConfigMapData(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sService(),
+				makeVirtualService(),
+				makeConfigMapWithVerifyConfigMapData(),
+			},
+		},
 	}
 	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 	for _, tc := range testCases {
@@ -460,6 +551,46 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+func TestReconcile_CanonicalYAMLUnchanged(t *testing.T) {
+	source := makeChannel()
+	source.Status = eventingv1alpha1.ChannelStatus{}
+	tc := controllertesting.TestCase{
+		InitialState: []runtime.Object{
+			source.DeepCopy(),
+			makeConfigMap(),
+		},
+	}
+	c := tc.GetClient()
+	r := &reconciler{
+		client:       c,
+		recorder:     record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName}),
+		logger:       zap.NewNop(),
+		configMapKey: types.NamespacedName{Namespace: cmNamespace, Name: cmName},
+	}
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cNamespace, Name: cName}}); err != nil {
+		t.Fatalf("Unexpected error reconciling the Channel: %v", err)
+	}
+	reconciled := &eventingv1alpha1.Channel{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: cNamespace, Name: cName}, reconciled); err != nil {
+		t.Fatalf("Unable to get the reconciled Channel: %v", err)
+	}
+	if len(reconciled.Finalizers) == 0 || len(reconciled.Annotations) == 0 {
+		t.Fatalf("Expected the reconciler to add its finalizer and spec hash, got %+v", reconciled.ObjectMeta)
+	}
+
+	want, err := source.CanonicalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error serializing the source Channel: %v", err)
+	}
+	got, err := reconciled.CanonicalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error serializing the reconciled Channel: %v", err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("Unexpected canonical YAML of the reconciled Channel (-want, +got) = %v", diff)
+	}
+}
+
 func makeChannel() *eventingv1alpha1.Channel {
 	c := &eventingv1alpha1.Channel{
 		TypeMeta: metav1.TypeMeta{
@@ -498,10 +629,33 @@ func makeChannelWithFinalizerAndSubscribableAndSinkable() *eventingv1alpha1.Chan
 func makeReadyChannel() *eventingv1alpha1.Channel {
 	// Ready channels have the finalizer and are Subscribable and Sinkable.
 	c := makeChannelWithFinalizerAndSubscribableAndSinkable()
+	c.Status.MarkCompatible()
 	c.Status.MarkProvisioned()
 	return c
 }
 
+func makeReadyChannelWithNewGeneration() *eventingv1alpha1.Channel {
+	c := makeReadyChannel()
+	c.Spec.Generation = 2
+	c.Status.ObservedGeneration = 1
+	return c
+}
+
+func makeReadyChannelWithObservedGeneration() *eventingv1alpha1.Channel {
+	c := makeReadyChannelWithNewGeneration()
+	c.Status.ObservedGeneration = 2
+	return c
+}
+
+func makeChannelWithStaleCondition() *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Status.Conditions = append(c.Status.Conditions, duckv1alpha1.Condition{
+		Type:   "DispatcherReady",
+		Status: corev1.ConditionTrue,
+	})
+	return c
+}
+
 func makeChannelNilProvisioner() *eventingv1alpha1.Channel {
 	c := makeChannel()
 	c.Spec.Provisioner = nil
@@ -529,6 +683,14 @@ func makeChannelWithWrongProvisionerName() *eventingv1alpha1.Channel {
 func makeChannelWithFinalizer() *eventingv1alpha1.Channel {
 	c := makeChannel()
 	c.Finalizers = []string{finalizerName}
+	hash, _ := c.SpecHash()
+	c.Annotations = map[string]string{eventingv1alpha1.SpecHashAnnotation: hash}
+	return c
+}
+
+func makeReadyChannelWithStaleSpecHash() *eventingv1alpha1.Channel {
+	c := makeReadyChannel()
+	c.Annotations[eventingv1alpha1.SpecHashAnnotation] = "stale"
 	return c
 }
 
@@ -605,6 +767,20 @@ func makeK8sServiceNotOwnedByChannel() *corev1.Service {
 	return svc
 }
 
+func makeK8sServiceNotCreatedForChannel() *corev1.Service {
+	svc := makeK8sServiceNotOwnedByChannel()
+	svc.Labels = nil
+	return svc
+}
+
+func makeK8sServiceOwnedBySomethingElse() *corev1.Service {
+	svc := makeK8sService()
+	svc.OwnerReferences[0].Kind = "ConfigMap"
+	svc.OwnerReferences[0].Name = "something-else"
+	svc.OwnerReferences[0].UID = "something-else-uid"
+	return svc
+}
+
 func makeVirtualService() *istiov1alpha3.VirtualService {
 	return &istiov1alpha3.VirtualService{
 		TypeMeta: metav1.TypeMeta{
@@ -657,6 +833,12 @@ func makeVirtualServiceNowOwnedByChannel() *istiov1alpha3.VirtualService {
 	return vs
 }
 
+func makeVirtualServiceNotCreatedForChannel() *istiov1alpha3.VirtualService {
+	vs := makeVirtualServiceNowOwnedByChannel()
+	vs.Labels = map[string]string{"channel": cName, "provisioner": "some-other-provisioner"}
+	return vs
+}
+
 func errorOnSecondChannelGet() []controllertesting.MockGet {
 	passThrough := []controllertesting.MockGet{
 		func(innerClient client.Client, ctx context.Context, key client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
@@ -68,3 +68,7 @@ func main() {
 		logger.Fatal("Manager.Start() returned an error", zap.Error(err))
 	}
 }
+
+func init() {
+	flag.StringVar(&channel.LifecycleEventSink, "lifecycleEventSink", "", "The URI to send CloudEvents about the lifecycle of Channels to. If empty, none are sent.")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lifecycle emits CloudEvents about the lifecycle of eventing resources, so that they can
+// themselves be consumed as events.
+package lifecycle
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/event"
+)
+
+const (
+	// ChannelCreatedEventType is the type of the CloudEvent emitted when a Channel is first
+	// reconciled.
+	ChannelCreatedEventType = "dev.knative.eventing.channel.created"
+
+	// ChannelReadyEventType is the type of the CloudEvent emitted when a Channel becomes ready.
+	ChannelReadyEventType = "dev.knative.eventing.channel.ready"
+
+	// ChannelDeletedEventType is the type of the CloudEvent emitted when a Channel is deleted.
+	ChannelDeletedEventType = "dev.knative.eventing.channel.deleted"
+)
+
+// emitTimeout bounds how long emitting an event may block the reconciliation that emits it, should
+// the sink be slow or unreachable. It is a variable for tests.
+var emitTimeout = 5 * time.Second
+
+// ChannelEventData is the data of the CloudEvents emitted about a Channel.
+type ChannelEventData struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+// Emitter sends lifecycle CloudEvents to a sink. A nil Emitter is valid and emits nothing, so that
+// controllers can emit unconditionally and leave it to configuration whether events are sent.
+type Emitter struct {
+	sinkURI string
+	client  *http.Client
+}
+
+// NewEmitter returns an Emitter sending to sinkURI, or nil if sinkURI is empty.
+func NewEmitter(sinkURI string) *Emitter {
+	if sinkURI == "" {
+		return nil
+	}
+	return &Emitter{
+		sinkURI: sinkURI,
+		client:  &http.Client{Timeout: emitTimeout},
+	}
+}
+
+// ChannelCreated emits a ChannelCreatedEventType CloudEvent about c.
+func (e *Emitter) ChannelCreated(c *eventingv1alpha1.Channel) error {
+	return e.emitChannelEvent(ChannelCreatedEventType, c)
+}
+
+// ChannelReady emits a ChannelReadyEventType CloudEvent about c.
+func (e *Emitter) ChannelReady(c *eventingv1alpha1.Channel) error {
+	return e.emitChannelEvent(ChannelReadyEventType, c)
+}
+
+// ChannelDeleted emits a ChannelDeletedEventType CloudEvent about c.
+func (e *Emitter) ChannelDeleted(c *eventingv1alpha1.Channel) error {
+	return e.emitChannelEvent(ChannelDeletedEventType, c)
+}
+
+// ChannelSource returns the source of the CloudEvents emitted about c, which is its API path.
+func ChannelSource(c *eventingv1alpha1.Channel) string {
+	return fmt.Sprintf("/apis/%s/namespaces/%s/channels/%s", eventingv1alpha1.SchemeGroupVersion.String(), c.Namespace, c.Name)
+}
+
+func (e *Emitter) emitChannelEvent(eventType string, c *eventingv1alpha1.Channel) error {
+	if e == nil {
+		return nil
+	}
+	data := ChannelEventData{
+		Namespace: c.Namespace,
+		Name:      c.Name,
+		UID:       string(c.UID),
+	}
+	ctx := event.EventContext{
+		CloudEventsVersion: event.CloudEventsVersion,
+		EventID:            uuid.New().String(),
+		EventTime:          time.Now(),
+		EventType:          eventType,
+		Source:             ChannelSource(c),
+	}
+	req, err := event.Binary.NewRequest(e.sinkURI, data, ctx)
+	if err != nil {
+		return err
+	}
+	res, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected response from the lifecycle event sink: %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/event"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEmitter(t *testing.T) {
+	c := &eventingv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-channel",
+			UID:       "test-uid",
+		},
+	}
+	testCases := map[string]struct {
+		emit      func(*Emitter) error
+		eventType string
+	}{
+		"created": {
+			emit:      func(e *Emitter) error { return e.ChannelCreated(c) },
+			eventType: "dev.knative.eventing.channel.created",
+		},
+		"ready": {
+			emit:      func(e *Emitter) error { return e.ChannelReady(c) },
+			eventType: "dev.knative.eventing.channel.ready",
+		},
+		"deleted": {
+			emit:      func(e *Emitter) error { return e.ChannelDeleted(c) },
+			eventType: "dev.knative.eventing.channel.deleted",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var gotCtx *event.EventContext
+			var gotData ChannelEventData
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx, err := event.FromRequest(&gotData, r)
+				if err != nil {
+					t.Errorf("Unexpected error parsing the CloudEvent: %v", err)
+				}
+				gotCtx = ctx
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer sink.Close()
+
+			if err := tc.emit(NewEmitter(sink.URL)); err != nil {
+				t.Fatalf("Unexpected error emitting: %v", err)
+			}
+			if gotCtx == nil {
+				t.Fatalf("expected the sink to receive a CloudEvent")
+			}
+			if gotCtx.EventType != tc.eventType {
+				t.Errorf("unexpected event type: want %q, got %q", tc.eventType, gotCtx.EventType)
+			}
+			if want := "/apis/eventing.knative.dev/v1alpha1/namespaces/test-namespace/channels/test-channel"; gotCtx.Source != want {
+				t.Errorf("unexpected source: want %q, got %q", want, gotCtx.Source)
+			}
+			wantData := ChannelEventData{
+				Namespace: "test-namespace",
+				Name:      "test-channel",
+				UID:       "test-uid",
+			}
+			if diff := cmp.Diff(wantData, gotData); diff != "" {
+				t.Errorf("unexpected data (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestEmitter_SinkError(t *testing.T) {
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer sink.Close()
+
+	if err := NewEmitter(sink.URL).ChannelCreated(&eventingv1alpha1.Channel{}); err == nil {
+		t.Errorf("expected an error when the sink fails")
+	}
+}
+
+func TestEmitter_SinkTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { emitTimeout = timeout }(emitTimeout)
+	emitTimeout = 50 * time.Millisecond
+
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer sink.Close()
+
+	if err := NewEmitter(sink.URL).ChannelCreated(&eventingv1alpha1.Channel{}); err == nil {
+		t.Errorf("expected an error when the sink doesn't respond in time")
+	}
+}
+
+func TestEmitter_Disabled(t *testing.T) {
+	e := NewEmitter("")
+	if e != nil {
+		t.Fatalf("expected no Emitter without a sink, got %v", e)
+	}
+	if err := e.ChannelReady(&eventingv1alpha1.Channel{}); err != nil {
+		t.Errorf("expected a nil Emitter to do nothing, got %v", err)
+	}
+}
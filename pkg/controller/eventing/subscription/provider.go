@@ -17,7 +17,12 @@ limitations under the License.
 package subscription
 
 import (
+	"github.com/golang/glog"
 	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -35,6 +40,10 @@ const (
 	controllerAgentName = "subscription-controller"
 )
 
+// MaxReplyChainDepth is the maximum number of reply hops a chain of Subscriptions, each sending
+// its result to the Channel the next one is from, may have.
+var MaxReplyChainDepth = 10
+
 type reconciler struct {
 	client        client.Client
 	restConfig    *rest.Config
@@ -48,10 +57,11 @@ var _ reconcile.Reconciler = &reconciler{}
 // ProvideController returns a Subscription controller.
 func ProvideController(mgr manager.Manager) (controller.Controller, error) {
 	// Setup a new controller to Reconcile Subscriptions.
+	r := &reconciler{
+		recorder: mgr.GetRecorder(controllerAgentName),
+	}
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: &reconciler{
-			recorder: mgr.GetRecorder(controllerAgentName),
-		},
+		Reconciler: r,
 	})
 	if err != nil {
 		return nil, err
@@ -62,6 +72,11 @@ func ProvideController(mgr manager.Manager) (controller.Controller, error) {
 		return nil, err
 	}
 
+	// Watch K8s Services so that Subscriptions selecting them are refreshed as they change.
+	if err := c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: &serviceToSubscriptions{r: r}}); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -76,3 +91,31 @@ func (r *reconciler) InjectConfig(c *rest.Config) error {
 	r.dynamicClient, err = dynamic.NewForConfig(c)
 	return err
 }
+
+// serviceToSubscriptions maps a K8s Service to the Subscriptions in its namespace whose call
+// selector matches it.
+type serviceToSubscriptions struct {
+	r *reconciler
+}
+
+func (m *serviceToSubscriptions) Map(obj handler.MapObject) []reconcile.Request {
+	subscriptions, err := m.r.listSubscriptions(obj.Meta.GetNamespace())
+	if err != nil {
+		glog.Warningf("Failed to list Subscriptions in %q: %s", obj.Meta.GetNamespace(), err)
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, sub := range subscriptions {
+		if sub.Spec.Call == nil || sub.Spec.Call.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(sub.Spec.Call.Selector)
+		if err != nil || !selector.Matches(labels.Set(obj.Meta.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscription
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestServiceToSubscriptionsMap(t *testing.T) {
+	testCases := []struct {
+		name   string
+		labels map[string]string
+		rr     []reconcile.Request
+	}{{
+		name:   "matches audit selector",
+		labels: map[string]string{"app": "audit"},
+		rr: []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: testNS, Name: "audit"},
+		}},
+	}, {
+		name:   "labels updated to match billing selector",
+		labels: map[string]string{"app": "billing"},
+		rr: []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: testNS, Name: "billing"},
+		}},
+	}, {
+		name:   "matches no selector",
+		labels: map[string]string{"app": "other"},
+		rr:     []reconcile.Request{},
+	}}
+	c := fake.NewFakeClient(
+		getNewSubscriptionWithSelector("audit", map[string]string{"app": "audit"}),
+		getNewSubscriptionWithSelector("billing", map[string]string{"app": "billing"}),
+		getNewSubscription(),
+	)
+	m := &serviceToSubscriptions{r: &reconciler{client: c}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := getK8sService()
+			svc.Labels = tc.labels
+			actualRR := m.Map(handler.MapObject{Meta: svc, Object: svc})
+			if diff := cmp.Diff(tc.rr, actualRR); diff != "" {
+				t.Errorf("Reconcile request (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestResolveCallSelector(t *testing.T) {
+	services := []runtime.Object{
+		getK8sServiceWithLabels("audit-1", map[string]string{"app": "audit"}),
+		getK8sServiceWithLabels("audit-2", map[string]string{"app": "audit"}),
+		getK8sServiceWithLabels("billing", map[string]string{"app": "billing"}),
+	}
+	r := &reconciler{client: fake.NewFakeClient(services...)}
+	domains, err := r.resolveCallSelector(testNS, &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "audit"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error resolving selector: %v", err)
+	}
+	want := []string{
+		"audit-1.testnamespace.svc.cluster.local",
+		"audit-2.testnamespace.svc.cluster.local",
+	}
+	if diff := cmp.Diff(want, domains); diff != "" {
+		t.Errorf("Resolved domains (-want, +got) = %v", diff)
+	}
+}
+
+func getNewSubscriptionWithSelector(name string, matchLabels map[string]string) *eventingv1alpha1.Subscription {
+	sub := getNewSubscription()
+	sub.Name = name
+	sub.Spec.Call = &eventingv1alpha1.Callable{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: matchLabels,
+		},
+	}
+	return sub
+}
+
+func getK8sServiceWithLabels(name string, labels map[string]string) *corev1.Service {
+	svc := getK8sService()
+	svc.Name = name
+	svc.Labels = labels
+	return svc
+}
@@ -30,10 +30,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sort"
 )
 
 // Reconcile compares the actual state with the desired, and attempts to
@@ -99,7 +101,15 @@ func (r *reconciler) reconcile(subscription *v1alpha1.Subscription) error {
 	glog.Infof("Resolved from subscribable to: %+v", from.Status.Subscribable.Channelable)
 
 	callDomain := ""
-	if subscription.Spec.Call != nil {
+	var selectedDomains []string
+	if subscription.Spec.Call != nil && subscription.Spec.Call.Selector != nil {
+		selectedDomains, err = r.resolveCallSelector(subscription.Namespace, subscription.Spec.Call.Selector)
+		if err != nil {
+			glog.Warningf("Failed to resolve Call selector %+v : %s", subscription.Spec.Call.Selector, err)
+			return err
+		}
+		glog.Infof("Resolved call selector to: %q", selectedDomains)
+	} else if subscription.Spec.Call != nil {
 		callDomain, err = r.resolveCall(subscription.Namespace, *subscription.Spec.Call)
 		if err != nil {
 			glog.Warningf("Failed to resolve Call %+v : %s", *subscription.Spec.Call, err)
@@ -125,12 +135,46 @@ func (r *reconciler) reconcile(subscription *v1alpha1.Subscription) error {
 		glog.Infof("Resolved result to: %q", resultDomain)
 	}
 
-	// Everything that was supposed to be resolved was, so flip the status bit on that.
-	subscription.Status.MarkReferencesResolved()
+	deadLetterSinkDomain := ""
+	if subscription.Spec.DeadLetterSink != nil {
+		deadLetterSinkDomain, err = r.resolveDeadLetterSink(subscription.Namespace, *subscription.Spec.DeadLetterSink)
+		if err != nil {
+			glog.Warningf("Failed to resolve DeadLetterSink %v : %v", subscription.Spec.DeadLetterSink, err)
+			return err
+		}
+		glog.Infof("Resolved dead-letter sink to: %q", deadLetterSinkDomain)
+	}
+
+	// Everything that was supposed to be resolved was, so record the resolved URIs and flip the
+	// status bit on that.
+	var subscribers []v1alpha1.ChannelSubscriberSpec
+	ref := subscriptionRef(subscription)
+	if subscription.Spec.Call != nil && subscription.Spec.Call.Selector != nil {
+		subscription.Status.SetSelectedPhysicalSubscription(selectedDomains, resultDomain)
+		for _, d := range selectedDomains {
+			subscribers = append(subscribers, v1alpha1.ChannelSubscriberSpec{Ref: ref, CallableDomain: d, SinkableDomain: resultDomain, DeadLetterSinkDomain: deadLetterSinkDomain, Delivery: subscription.Spec.Delivery})
+		}
+	} else {
+		subscription.SetPhysicalSubscription(callDomain, resultDomain)
+		subscribers = []v1alpha1.ChannelSubscriberSpec{{Ref: ref, CallableDomain: callDomain, SinkableDomain: resultDomain, DeadLetterSinkDomain: deadLetterSinkDomain, Delivery: subscription.Spec.Delivery}}
+	}
+	subscription.Status.SetDeadLetterSinkURI(deadLetterSinkDomain)
+
+	// Refuse to extend reply chains that are already too deep. This is best-effort: it only sees
+	// the Subscriptions downstream of this one, so a chain is flagged at its head.
+	if subscription.Spec.Result != nil {
+		subs, err := r.listSubscriptions(subscription.Namespace)
+		if err != nil {
+			glog.Warningf("Failed to list Subscriptions to check the reply chain depth: %s", err)
+		} else if depth := replyChainDepth(subscription, subs, MaxReplyChainDepth); depth > MaxReplyChainDepth {
+			subscription.Status.MarkReplyChainTooDeep(depth, MaxReplyChainDepth)
+			return fmt.Errorf("reply chain of %s/%s is deeper than %d", subscription.Namespace, subscription.Name, MaxReplyChainDepth)
+		}
+	}
 
 	// Ok, now that we have the From and at least one of the Call/Result, let's reconcile
 	// the From with this information.
-	err = r.reconcileFromChannel(subscription.Namespace, from.Status.Subscribable.Channelable, callDomain, resultDomain, deletionTimestamp != nil)
+	err = r.reconcileFromChannel(subscription.Namespace, from.Status.Subscribable.Channelable, ref, subscribers, deletionTimestamp != nil)
 	if err != nil {
 		glog.Warningf("Failed to resolve from Channel : %s", err)
 		return err
@@ -202,11 +246,172 @@ func (r *reconciler) resolveCall(namespace string, callable v1alpha1.Callable) (
 	return "", fmt.Errorf("status does not contain targetable")
 }
 
+// resolveCallSelector resolves the Spec.Call selector to the domains of the K8s Services in the
+// namespace that match it, sorted so that the resulting subscribers are stable.
+func (r *reconciler) resolveCallSelector(namespace string, selector *metav1.LabelSelector) ([]string, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	opts := &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: s,
+		// Set Raw because if we need to get more than one page, then we will put the continue token
+		// into opts.Raw.Continue.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "Service",
+			},
+		},
+	}
+	domains := []string{}
+	for {
+		svcs := &corev1.ServiceList{}
+		if err := r.client.List(context.TODO(), opts, svcs); err != nil {
+			glog.Warningf("Failed to list K8s Services matching %q: %s", s, err)
+			return nil, err
+		}
+		for _, svc := range svcs.Items {
+			// Not every client honors the LabelSelector, so match again.
+			if s.Matches(labels.Set(svc.Labels)) {
+				domains = append(domains, controller.ServiceHostName(svc.Name, svc.Namespace))
+			}
+		}
+		if svcs.Continue == "" {
+			break
+		}
+		opts.Raw.Continue = svcs.Continue
+	}
+	sort.Strings(domains)
+	return domains, nil
+}
+
+// listSubscriptions lists all the Subscriptions in the namespace.
+func (r *reconciler) listSubscriptions(namespace string) ([]v1alpha1.Subscription, error) {
+	opts := &client.ListOptions{
+		Namespace: namespace,
+		// Set Raw because if we need to get more than one page, then we will put the continue token
+		// into opts.Raw.Continue.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Subscription",
+			},
+		},
+	}
+	var subs []v1alpha1.Subscription
+	for {
+		sl := &v1alpha1.SubscriptionList{}
+		if err := r.client.List(context.TODO(), opts, sl); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sl.Items...)
+		if sl.Continue == "" {
+			return subs, nil
+		}
+		opts.Raw.Continue = sl.Continue
+	}
+}
+
+// replyChainDepth returns the number of reply hops starting at sub: its result Channel, the
+// results of the Subscriptions from that Channel, and so on, along the longest such chain. Depths
+// beyond max, including the endless chains of loops, are returned as max+1.
+func replyChainDepth(sub *v1alpha1.Subscription, subs []v1alpha1.Subscription, max int) int {
+	if sub.Spec.Result == nil || sub.Spec.Result.Target == nil {
+		return 0
+	}
+	// results maps each Channel to the distinct Channels its Subscriptions reply to.
+	results := map[channelKey]map[channelKey]bool{}
+	for _, s := range subs {
+		if s.Spec.Result == nil || s.Spec.Result.Target == nil {
+			continue
+		}
+		from := newChannelKey(s.Namespace, &s.Spec.From)
+		if results[from] == nil {
+			results[from] = map[channelKey]bool{}
+		}
+		results[from][newChannelKey(s.Namespace, s.Spec.Result.Target)] = true
+	}
+
+	// Walk the Channels depth first, visiting each once: depths holds the depth of the Channels
+	// already walked, and walking the Channels of the current chain.
+	depths := map[channelKey]int{}
+	walking := map[channelKey]bool{}
+	var depthFrom func(c channelKey) int
+	depthFrom = func(c channelKey) int {
+		if d, ok := depths[c]; ok {
+			return d
+		}
+		if walking[c] {
+			// A loop.
+			return max + 1
+		}
+		walking[c] = true
+		d := 0
+		for r := range results[c] {
+			if rd := 1 + depthFrom(r); rd > d {
+				d = rd
+			}
+			if d > max {
+				d = max + 1
+				break
+			}
+		}
+		walking[c] = false
+		depths[c] = d
+		return d
+	}
+	if d := 1 + depthFrom(newChannelKey(sub.Namespace, sub.Spec.Result.Target)); d <= max {
+		return d
+	}
+	return max + 1
+}
+
+// channelKey identifies a Channel, or any other object Subscriptions are from or reply to.
+type channelKey struct {
+	apiVersion, kind, namespace, name string
+}
+
+// newChannelKey returns the key of ref, which defaults to namespace if it doesn't set one.
+func newChannelKey(namespace string, ref *corev1.ObjectReference) channelKey {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	return channelKey{apiVersion: ref.APIVersion, kind: ref.Kind, namespace: namespace, name: ref.Name}
+}
+
+// subscriptionRef returns a reference to the Subscription, recorded on the subscribers it adds to
+// the Channel.
+func subscriptionRef(subscription *v1alpha1.Subscription) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+		Namespace:  subscription.Namespace,
+		Name:       subscription.Name,
+		UID:        subscription.UID,
+	}
+}
+
 // resolveResult resolves the Spec.Result object.
 func (r *reconciler) resolveResult(namespace string, resultStrategy v1alpha1.ResultStrategy) (string, error) {
-	obj, err := r.fetchObjectReference(namespace, resultStrategy.Target)
+	return r.resolveSinkable(namespace, resultStrategy.Target)
+}
+
+// resolveDeadLetterSink resolves the Spec.DeadLetterSink object. If it's TargetURI then it's used
+// as is.
+func (r *reconciler) resolveDeadLetterSink(namespace string, deadLetterSink v1alpha1.DeadLetterSink) (string, error) {
+	if deadLetterSink.TargetURI != nil && *deadLetterSink.TargetURI != "" {
+		return *deadLetterSink.TargetURI, nil
+	}
+	return r.resolveSinkable(namespace, deadLetterSink.Target)
+}
+
+// resolveSinkable resolves the domain of an object that fulfills the Sinkable contract.
+func (r *reconciler) resolveSinkable(namespace string, ref *corev1.ObjectReference) (string, error) {
+	obj, err := r.fetchObjectReference(namespace, ref)
 	if err != nil {
-		glog.Warningf("Failed to fetch ResultStrategy target %+v: %s", resultStrategy, err)
+		glog.Warningf("Failed to fetch Sinkable target %+v: %s", ref, err)
 		return "", err
 	}
 	s := duckv1alpha1.Sink{}
@@ -247,15 +452,26 @@ func (r *reconciler) fetchObjectReference(namespace string, ref *corev1.ObjectRe
 	return resourceClient.Get(ref.Name, metav1.GetOptions{})
 }
 
-func (r *reconciler) reconcileFromChannel(namespace string, subscribable corev1.ObjectReference, callDomain string, resultDomain string, deleted bool) error {
-	glog.Infof("Reconciling From Channel: %+v call: %q result %q deleted: %v", subscribable, callDomain, resultDomain, deleted)
+// channelable is the part of a Channelable object a Subscription writes to. Its subscribers are
+// decoded as v1alpha1.ChannelSubscriberSpecs rather than with the duck type, so that the delivery
+// options the duck type doesn't have are part of the patch.
+type channelable struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Channelable *v1alpha1.Channelable `json:"channelable,omitempty"`
+	} `json:"spec"`
+}
+
+func (r *reconciler) reconcileFromChannel(namespace string, subscribable corev1.ObjectReference, ref *corev1.ObjectReference, subscribers []v1alpha1.ChannelSubscriberSpec, deleted bool) error {
+	glog.Infof("Reconciling From Channel: %+v subscribers: %+v deleted: %v", subscribable, subscribers, deleted)
 
 	// First get the original object and convert it to only the bits we care about
 	s, err := r.fetchObjectReference(namespace, &subscribable)
 	if err != nil {
 		return err
 	}
-	original := duckv1alpha1.Channel{}
+	original := channelable{}
 	err = duck.FromUnstructured(s, &original)
 	if err != nil {
 		return err
@@ -263,9 +479,13 @@ func (r *reconciler) reconcileFromChannel(namespace string, subscribable corev1.
 
 	// TODO: Handle deletes.
 
-	after := original.DeepCopy()
-	after.Spec.Channelable = &duckv1alpha1.Channelable{
-		Subscribers: []duckv1alpha1.ChannelSubscriberSpec{{CallableDomain: callDomain, SinkableDomain: resultDomain}},
+	after := original
+	var existing []v1alpha1.ChannelSubscriberSpec
+	if original.Spec.Channelable != nil {
+		existing = original.Spec.Channelable.Subscribers
+	}
+	after.Spec.Channelable = &v1alpha1.Channelable{
+		Subscribers: mergeSubscribers(existing, ref, subscribers),
 	}
 
 	patch, err := duck.CreatePatch(original, after)
@@ -294,6 +514,39 @@ func (r *reconciler) reconcileFromChannel(namespace string, subscribable corev1.
 	return nil
 }
 
+// mergeSubscribers returns existing with the subscribers added by the Subscription ref replaced by
+// subscribers. The subscribers of other Subscriptions, or added directly to the Channel, are kept.
+// The LeaseExpiry and Filter, which Subscriptions don't set, are carried over from the existing
+// subscriber of ref with the same CallableDomain.
+func mergeSubscribers(existing []v1alpha1.ChannelSubscriberSpec, ref *corev1.ObjectReference, subscribers []v1alpha1.ChannelSubscriberSpec) []v1alpha1.ChannelSubscriberSpec {
+	merged := make([]v1alpha1.ChannelSubscriberSpec, 0, len(existing)+len(subscribers))
+	previous := map[string]v1alpha1.ChannelSubscriberSpec{}
+	for _, e := range existing {
+		if sameSubscription(e.Ref, ref) {
+			previous[e.CallableDomain] = e
+		} else {
+			merged = append(merged, e)
+		}
+	}
+	for _, s := range subscribers {
+		if p, ok := previous[s.CallableDomain]; ok {
+			s.LeaseExpiry = p.LeaseExpiry
+			s.Filter = p.Filter
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// sameSubscription returns whether a and b reference the same Subscription. The UIDs are not
+// compared, so that a recreated Subscription replaces the subscribers of its predecessor.
+func sameSubscription(a, b *corev1.ObjectReference) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Kind == b.Kind && a.Namespace == b.Namespace && a.Name == b.Name
+}
+
 func (r *reconciler) CreateResourceInterface(namespace string, ref *corev1.ObjectReference) (dynamic.ResourceInterface, error) {
 	rc := r.dynamicClient.Resource(duckapis.KindToResource(ref.GroupVersionKind()))
 
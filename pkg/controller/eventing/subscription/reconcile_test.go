@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	controllertesting "github.com/knative/eventing/pkg/controller/testing"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
@@ -34,24 +35,26 @@ import (
 )
 
 var (
-	trueVal   = true
-	targetURI = "http://target.example.com"
+	trueVal       = true
+	targetURI     = "http://target.example.com"
+	deadLetterURI = "http://deadletter.example.com"
 )
 
 const (
-	fromChannelName   = "fromchannel"
-	resultChannelName = "resultchannel"
-	sourceName        = "source"
-	routeName         = "callroute"
-	channelKind       = "Channel"
-	routeKind         = "Route"
-	sourceKind        = "Source"
-	targetDNS         = "myfunction.mynamespace.svc.cluster.local"
-	sinkableDNS       = "myresultchannel.mynamespace.svc.cluster.local"
-	eventType         = "myeventtype"
-	subscriptionName  = "testsubscription"
-	testNS            = "testnamespace"
-	k8sServiceName    = "testk8sservice"
+	fromChannelName       = "fromchannel"
+	resultChannelName     = "resultchannel"
+	deadLetterChannelName = "deadletterchannel"
+	sourceName            = "source"
+	routeName             = "callroute"
+	channelKind           = "Channel"
+	routeKind             = "Route"
+	sourceKind            = "Source"
+	targetDNS             = "myfunction.mynamespace.svc.cluster.local"
+	sinkableDNS           = "myresultchannel.mynamespace.svc.cluster.local"
+	eventType             = "myeventtype"
+	subscriptionName      = "testsubscription"
+	testNS                = "testnamespace"
+	k8sServiceName        = "testk8sservice"
 )
 
 func init() {
@@ -380,6 +383,50 @@ var testCases = []controllertesting.TestCase{
 					},
 				}},
 		},
+	}, {
+		Name: "Valid from, call and result, dead-letter sink does not exist",
+		InitialState: []runtime.Object{
+			getNewSubscriptionWithDeadLetterSink(&corev1.ObjectReference{
+				Name:       deadLetterChannelName,
+				Kind:       channelKind,
+				APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			}, nil),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, subscriptionName),
+		WantErrMsg:   `channels.eventing.knative.dev "deadletterchannel" not found`,
+		Scheme:       scheme.Scheme,
+		Objects: []runtime.Object{
+			getFromChannelObject(),
+			getRouteObject(),
+			getResultChannelObject(),
+		},
+	}, {
+		Name: "new subscription with dead-letter sink: adds status, all targets resolved, subscribers modified",
+		InitialState: []runtime.Object{
+			getNewSubscriptionWithDeadLetterSink(nil, &deadLetterURI),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, subscriptionName),
+		// TODO: JSON patch is not working on the fake, see
+		// https://github.com/kubernetes/client-go/issues/478. Marking this as expecting a specific
+		// failure for now, until upstream is fixed.
+		WantResult: reconcile.Result{},
+		WantErrMsg: "invalid JSON document",
+		WantPresent: []runtime.Object{
+			func() *eventingv1alpha1.Subscription {
+				s := getNewSubscriptionWithDeadLetterSink(nil, &deadLetterURI)
+				s.Status.InitializeConditions()
+				s.SetPhysicalSubscription(targetDNS, sinkableDNS)
+				s.Status.SetDeadLetterSinkURI(deadLetterURI)
+				return s
+			}(),
+		},
+		IgnoreTimes: true,
+		Scheme:      scheme.Scheme,
+		Objects: []runtime.Object{
+			getFromChannelObject(),
+			getRouteObject(),
+			getResultChannelObject(),
+		},
 	}, {
 		Name: "new subscription to K8s Service: adds status, all targets resolved, subscribers modified",
 		InitialState: []runtime.Object{
@@ -624,6 +671,76 @@ func getNewSubscription() *eventingv1alpha1.Subscription {
 	return subscription
 }
 
+func getNewSubscriptionWithDeadLetterSink(target *corev1.ObjectReference, targetURI *string) *eventingv1alpha1.Subscription {
+	sub := getNewSubscription()
+	sub.Spec.DeadLetterSink = &eventingv1alpha1.DeadLetterSink{
+		Target:    target,
+		TargetURI: targetURI,
+	}
+	return sub
+}
+
+func getFromChannelObject() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+			"kind":       channelKind,
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      fromChannelName,
+			},
+			"spec": map[string]interface{}{
+				"channelable": map[string]interface{}{},
+			},
+			"status": map[string]interface{}{
+				"subscribable": map[string]interface{}{
+					"channelable": map[string]interface{}{
+						"kind":       channelKind,
+						"name":       fromChannelName,
+						"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					},
+				},
+			},
+		}}
+}
+
+func getRouteObject() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1alpha1",
+			"kind":       routeKind,
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      routeName,
+			},
+			"status": map[string]interface{}{
+				"targetable": map[string]interface{}{
+					"domainInternal": targetDNS,
+				},
+			},
+		}}
+}
+
+func getResultChannelObject() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+			"kind":       channelKind,
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      resultChannelName,
+			},
+			"spec": map[string]interface{}{
+				"channelable": map[string]interface{}{},
+			},
+			"status": map[string]interface{}{
+				"sinkable": map[string]interface{}{
+					"domainInternal": sinkableDNS,
+				},
+			},
+		}}
+}
+
 func getNewSubscriptionToK8sService() *eventingv1alpha1.Subscription {
 	sub := getNewSubscription()
 	sub.Spec.Call = &eventingv1alpha1.Callable{
@@ -734,3 +851,116 @@ func getOwnerReference(blockOwnerDeletion bool) metav1.OwnerReference {
 		BlockOwnerDeletion: &blockOwnerDeletion,
 	}
 }
+
+func TestReplyChainDepth(t *testing.T) {
+	// chain builds Subscriptions from channel c0 to c1, c1 to c2, ..., each replying to the next.
+	chain := func(n int) []eventingv1alpha1.Subscription {
+		var subs []eventingv1alpha1.Subscription
+		for i := 0; i < n; i++ {
+			sub := getNewSubscription()
+			sub.Name = fmt.Sprintf("sub%d", i)
+			sub.Spec.From.Name = fmt.Sprintf("c%d", i)
+			sub.Spec.Result.Target.Name = fmt.Sprintf("c%d", i+1)
+			subs = append(subs, *sub)
+		}
+		return subs
+	}
+	loop := chain(3)
+	loop[2].Spec.Result.Target.Name = "c0"
+
+	testCases := map[string]struct {
+		subs []eventingv1alpha1.Subscription
+		max  int
+		want int
+	}{
+		"within depth": {
+			subs: chain(3),
+			max:  3,
+			want: 3,
+		},
+		"too deep": {
+			subs: chain(5),
+			max:  3,
+			want: 4,
+		},
+		"loop": {
+			subs: loop,
+			max:  10,
+			want: 11,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := replyChainDepth(&tc.subs[0], tc.subs, tc.max); got != tc.want {
+				t.Errorf("unexpected reply chain depth: want %d, got %d", tc.want, got)
+			}
+		})
+	}
+
+	// wide builds levels of Channels, each with width Subscriptions replying to the next level.
+	wide := func(levels, width int) []eventingv1alpha1.Subscription {
+		var subs []eventingv1alpha1.Subscription
+		for i := 0; i < levels; i++ {
+			for j := 0; j < width; j++ {
+				sub := getNewSubscription()
+				sub.Name = fmt.Sprintf("sub%d-%d", i, j)
+				sub.Spec.From.Name = fmt.Sprintf("c%d", i)
+				sub.Spec.Result.Target.Name = fmt.Sprintf("c%d", i+1)
+				subs = append(subs, *sub)
+			}
+		}
+		return subs
+	}
+	if got := replyChainDepth(&wide(1, 1)[0], wide(10, 10), 10); got != 10 {
+		t.Errorf("unexpected reply chain depth of a wide chain: want 10, got %d", got)
+	}
+
+	// A diamond, c0 replying to c1 both directly and through c2, is no loop.
+	diamond := chain(2)
+	shortcut := getNewSubscription()
+	shortcut.Name = "shortcut"
+	shortcut.Spec.From.Name = "c0"
+	shortcut.Spec.Result.Target.Name = "c2"
+	diamond = append(diamond, *shortcut)
+	if got := replyChainDepth(&diamond[0], diamond, 10); got != 2 {
+		t.Errorf("unexpected reply chain depth of a diamond: want 2, got %d", got)
+	}
+
+	// Subscriptions from a same-named Channel in another namespace are not part of the chain.
+	otherNamespace := chain(3)
+	otherNamespace[1].Spec.From.Namespace = "other"
+	if got := replyChainDepth(&otherNamespace[0], otherNamespace, 10); got != 1 {
+		t.Errorf("unexpected reply chain depth across namespaces: want 1, got %d", got)
+	}
+
+	noResult := getNewSubscription()
+	noResult.Spec.Result = nil
+	if got := replyChainDepth(noResult, chain(3), 10); got != 0 {
+		t.Errorf("unexpected reply chain depth without a result: want 0, got %d", got)
+	}
+}
+
+func TestMergeSubscribers(t *testing.T) {
+	ref := &corev1.ObjectReference{Kind: "Subscription", Namespace: testNS, Name: subscriptionName, UID: "new"}
+	lease := metav1.Now()
+	filter := &eventingv1alpha1.SubscriberFilter{Attributes: map[string]string{"eventType": "dev.knative.test"}}
+	existing := []eventingv1alpha1.ChannelSubscriberSpec{
+		{CallableDomain: "direct", LeaseExpiry: &lease},
+		{Ref: &corev1.ObjectReference{Kind: "Subscription", Namespace: testNS, Name: "other"}, CallableDomain: "other"},
+		{Ref: &corev1.ObjectReference{Kind: "Subscription", Namespace: testNS, Name: subscriptionName, UID: "old"}, CallableDomain: "kept", LeaseExpiry: &lease, Filter: filter},
+		{Ref: &corev1.ObjectReference{Kind: "Subscription", Namespace: testNS, Name: subscriptionName, UID: "old"}, CallableDomain: "removed"},
+	}
+	subscribers := []eventingv1alpha1.ChannelSubscriberSpec{
+		{Ref: ref, CallableDomain: "kept", SinkableDomain: "result"},
+		{Ref: ref, CallableDomain: "added"},
+	}
+	want := []eventingv1alpha1.ChannelSubscriberSpec{
+		existing[0],
+		existing[1],
+		{Ref: ref, CallableDomain: "kept", SinkableDomain: "result", LeaseExpiry: &lease, Filter: filter},
+		{Ref: ref, CallableDomain: "added"},
+	}
+	if diff := cmp.Diff(want, mergeSubscribers(existing, ref, subscribers)); diff != "" {
+		t.Errorf("unexpected subscribers (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "trigger-controller"
+)
+
+type reconciler struct {
+	client        client.Client
+	restConfig    *rest.Config
+	dynamicClient dynamic.Interface
+	recorder      record.EventRecorder
+	logger        *zap.Logger
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a Trigger controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return nil, err
+	}
+	logger = logger.With(zap.String("controller", controllerAgentName))
+
+	// Setup a new controller to Reconcile Triggers.
+	r := &reconciler{
+		recorder: mgr.GetRecorder(controllerAgentName),
+		logger:   logger,
+	}
+	c, err := controller.New(controllerAgentName, mgr, controller.Options{
+		Reconciler: r,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch Triggers.
+	if err := c.Watch(&source.Kind{Type: &eventingv1alpha1.Trigger{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch Brokers, so that their Triggers follow their readiness.
+	if err := c.Watch(&source.Kind{Type: &eventingv1alpha1.Broker{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: &brokerToTriggers{r: r}}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
+
+func (r *reconciler) InjectConfig(c *rest.Config) error {
+	r.restConfig = c
+	var err error
+	r.dynamicClient, err = dynamic.NewForConfig(c)
+	return err
+}
+
+// brokerToTriggers maps a Broker to the Triggers in its namespace that reference it.
+type brokerToTriggers struct {
+	r *reconciler
+}
+
+func (m *brokerToTriggers) Map(obj handler.MapObject) []reconcile.Request {
+	triggers, err := m.r.listTriggers(obj.Meta.GetNamespace())
+	if err != nil {
+		m.r.logger.Warn("Failed to list Triggers", zap.String("namespace", obj.Meta.GetNamespace()), zap.Error(err))
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, t := range triggers {
+		if t.Spec.Broker != obj.Meta.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: t.Namespace, Name: t.Name},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestBrokerToTriggers(t *testing.T) {
+	trigger := func(namespace, name, broker string) runtime.Object {
+		return &eventingv1alpha1.Trigger{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Trigger",
+			},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       eventingv1alpha1.TriggerSpec{Broker: broker},
+		}
+	}
+	r := &reconciler{
+		client: fake.NewFakeClient(
+			trigger(testNS, "a", brokerName),
+			trigger(testNS, "b", "other"),
+			trigger("other-ns", "c", brokerName),
+		),
+		logger: zap.NewNop(),
+	}
+	b := getNewBroker(true)
+
+	rr := (&brokerToTriggers{r: r}).Map(handler.MapObject{Meta: b, Object: b})
+	want := []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Namespace: testNS, Name: "a"},
+	}}
+	if diff := cmp.Diff(want, rr); diff != "" {
+		t.Errorf("unexpected requests (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	duckapis "github.com/knative/pkg/apis"
+	"github.com/knative/pkg/apis/duck"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Trigger resource
+// with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := context.TODO()
+	logger := r.logger.With(zap.Any("request", request))
+
+	t := &eventingv1alpha1.Trigger{}
+	err := r.client.Get(ctx, request.NamespacedName, t)
+
+	if errors.IsNotFound(err) {
+		logger.Info("Could not find Trigger")
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		logger.Error("Could not fetch Trigger", zap.Error(err))
+		return reconcile.Result{}, err
+	}
+
+	original := t.DeepCopy()
+
+	// Reconcile this copy of the Trigger and then write back any status
+	// updates regardless of whether the reconcile error out.
+	err = r.reconcile(ctx, t)
+	if err != nil {
+		logger.Warn("Error reconciling Trigger", zap.Error(err))
+	}
+	if equality.Semantic.DeepEqual(original.Status, t.Status) {
+		// If we didn't change anything then don't call updateStatus.
+		// This is important because the copy we loaded from the informer's
+		// cache may be stale and we don't want to overwrite a prior update
+		// to status with this stale state.
+	} else if _, updateErr := r.updateStatus(ctx, t); updateErr != nil {
+		logger.Warn("Failed to update Trigger status", zap.Error(updateErr))
+		return reconcile.Result{}, updateErr
+	}
+
+	// Requeue if the resource is not ready:
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, t *eventingv1alpha1.Trigger) error {
+	t.Status.InitializeConditions()
+
+	// The Broker drops a deleted Trigger from its Channel's subscribers, there is nothing else to
+	// clean up.
+	if t.DeletionTimestamp != nil {
+		return nil
+	}
+
+	b := &eventingv1alpha1.Broker{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: t.Namespace, Name: t.Spec.Broker}, b)
+	if errors.IsNotFound(err) {
+		// The subscriber is still resolved, so that the Trigger starts receiving events as soon as
+		// the Broker is created.
+		t.Status.MarkBrokerDoesNotExist(t.Spec.Broker)
+	} else if err != nil {
+		return err
+	} else {
+		t.Status.PropagateBrokerStatus(b)
+	}
+
+	if t.Spec.Subscriber == nil {
+		t.Status.MarkSubscriberNotResolved("SubscriberMissing", "the Trigger has no subscriber")
+		return nil
+	}
+	namespace := t.Namespace
+	if target := t.Spec.Subscriber.Target; target != nil && target.Namespace != "" {
+		namespace = target.Namespace
+	}
+	uri, err := r.resolveSubscriber(ctx, namespace, *t.Spec.Subscriber)
+	if err != nil {
+		t.Status.MarkSubscriberNotResolved("SubscriberNotResolved", "failed to resolve the subscriber: %v", err)
+		return err
+	}
+	t.Status.SetSubscriberURI(uri)
+	return nil
+}
+
+// resolveSubscriber resolves the Trigger's subscriber to the URI its events are delivered to.
+// A TargetURI is used as is. A K8s Service resolves to its cluster domain, and any other target
+// to the domain it reports in status.targetable.
+func (r *reconciler) resolveSubscriber(ctx context.Context, namespace string, callable eventingv1alpha1.Callable) (string, error) {
+	if callable.TargetURI != nil && *callable.TargetURI != "" {
+		return *callable.TargetURI, nil
+	}
+	if callable.Target == nil {
+		return "", fmt.Errorf("subscriber has neither target nor targetURI")
+	}
+
+	// K8s services are special cased. They can be called, even though they do not satisfy the
+	// Targetable interface.
+	if callable.Target.APIVersion == "v1" && callable.Target.Kind == "Service" {
+		svc := &corev1.Service{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: callable.Target.Name}, svc)
+		if err != nil {
+			return "", err
+		}
+		return domainToURI(controller.ServiceHostName(svc.Name, svc.Namespace)), nil
+	}
+
+	rc := r.dynamicClient.Resource(duckapis.KindToResource(callable.Target.GroupVersionKind()))
+	if rc == nil {
+		return "", fmt.Errorf("failed to create dynamic client resource")
+	}
+	obj, err := rc.Namespace(namespace).Get(callable.Target.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	target := duckv1alpha1.Target{}
+	if err := duck.FromUnstructured(obj, &target); err != nil {
+		return "", err
+	}
+	if target.Status.Targetable == nil || target.Status.Targetable.DomainInternal == "" {
+		return "", fmt.Errorf("status does not contain targetable")
+	}
+	return domainToURI(target.Status.Targetable.DomainInternal), nil
+}
+
+func domainToURI(domain string) string {
+	return fmt.Sprintf("http://%s/", domain)
+}
+
+// listTriggers lists all the Triggers in the namespace.
+func (r *reconciler) listTriggers(namespace string) ([]eventingv1alpha1.Trigger, error) {
+	opts := &client.ListOptions{
+		Namespace: namespace,
+		// Set Raw because if we need to get more than one page, then we will put the continue token
+		// into opts.Raw.Continue.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Trigger",
+			},
+		},
+	}
+	var triggers []eventingv1alpha1.Trigger
+	for {
+		tl := &eventingv1alpha1.TriggerList{}
+		if err := r.client.List(context.TODO(), opts, tl); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, tl.Items...)
+		if tl.Continue == "" {
+			return triggers, nil
+		}
+		opts.Raw.Continue = tl.Continue
+	}
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, t *eventingv1alpha1.Trigger) (*eventingv1alpha1.Trigger, error) {
+	newTrigger := &eventingv1alpha1.Trigger{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: t.Namespace, Name: t.Name}, newTrigger)
+
+	if err != nil {
+		return nil, err
+	}
+	newTrigger.Status = t.Status
+
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the Trigger resource. UpdateStatus will not
+	// allow changes to the Spec of the resource, which is ideal for ensuring
+	// nothing other than resource status has been updated.
+	if err = r.client.Update(ctx, newTrigger); err != nil {
+		return nil, err
+	}
+	return newTrigger, nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"fmt"
+	"testing"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+)
+
+var (
+	targetURI = "http://target.example.com/"
+)
+
+const (
+	testNS         = "testnamespace"
+	triggerName    = "testtrigger"
+	brokerName     = "default"
+	routeName      = "callroute"
+	k8sServiceName = "testk8sservice"
+	targetDNS      = "myfunction.testnamespace.svc.cluster.local"
+)
+
+func init() {
+	// Add types to scheme
+	eventingv1alpha1.AddToScheme(scheme.Scheme)
+	duckv1alpha1.AddToScheme(scheme.Scheme)
+}
+
+var testCases = []controllertesting.TestCase{
+	{
+		Name:         "non existent key",
+		ReconcileKey: "non-existent-test-ns/non-existent-test-key",
+		WantErr:      false,
+	}, {
+		Name: "broker does not exist",
+		InitialState: []runtime.Object{
+			getNewTrigger(uriSubscriber()),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, triggerName),
+		WantPresent: []runtime.Object{
+			withStatus(getNewTrigger(uriSubscriber()), func(ts *eventingv1alpha1.TriggerStatus) {
+				ts.MarkBrokerDoesNotExist(brokerName)
+				ts.SetSubscriberURI(targetURI)
+			}),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "broker not ready",
+		InitialState: []runtime.Object{
+			getNewBroker(false),
+			getNewTrigger(uriSubscriber()),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, triggerName),
+		WantPresent: []runtime.Object{
+			withStatus(getNewTrigger(uriSubscriber()), func(ts *eventingv1alpha1.TriggerStatus) {
+				ts.PropagateBrokerStatus(getNewBroker(false))
+				ts.SetSubscriberURI(targetURI)
+			}),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "k8s service subscriber",
+		InitialState: []runtime.Object{
+			getNewBroker(true),
+			getK8sService(),
+			getNewTrigger(k8sServiceSubscriber()),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, triggerName),
+		WantPresent: []runtime.Object{
+			withStatus(getNewTrigger(k8sServiceSubscriber()), func(ts *eventingv1alpha1.TriggerStatus) {
+				ts.PropagateBrokerStatus(getNewBroker(true))
+				ts.SetSubscriberURI("http://testk8sservice.testnamespace.svc.cluster.local/")
+			}),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "k8s service subscriber in another namespace",
+		InitialState: []runtime.Object{
+			getNewBroker(true),
+			withNamespace(getK8sService(), "other"),
+			getNewTrigger(withTargetNamespace(k8sServiceSubscriber(), "other")),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, triggerName),
+		WantPresent: []runtime.Object{
+			withStatus(getNewTrigger(withTargetNamespace(k8sServiceSubscriber(), "other")), func(ts *eventingv1alpha1.TriggerStatus) {
+				ts.PropagateBrokerStatus(getNewBroker(true))
+				ts.SetSubscriberURI("http://testk8sservice.other.svc.cluster.local/")
+			}),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "k8s service subscriber does not exist",
+		InitialState: []runtime.Object{
+			getNewBroker(true),
+			getNewTrigger(k8sServiceSubscriber()),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, triggerName),
+		WantErrMsg:   `services "testk8sservice" not found`,
+		WantPresent: []runtime.Object{
+			withStatus(getNewTrigger(k8sServiceSubscriber()), func(ts *eventingv1alpha1.TriggerStatus) {
+				ts.PropagateBrokerStatus(getNewBroker(true))
+				ts.MarkSubscriberNotResolved("SubscriberNotResolved", `failed to resolve the subscriber: services "testk8sservice" not found`)
+			}),
+		},
+		IgnoreTimes: true,
+	}, {
+		Name: "targetable subscriber",
+		InitialState: []runtime.Object{
+			getNewBroker(true),
+			getNewTrigger(routeSubscriber()),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, triggerName),
+		WantPresent: []runtime.Object{
+			withStatus(getNewTrigger(routeSubscriber()), func(ts *eventingv1alpha1.TriggerStatus) {
+				ts.PropagateBrokerStatus(getNewBroker(true))
+				ts.SetSubscriberURI("http://" + targetDNS + "/")
+			}),
+		},
+		IgnoreTimes: true,
+		Scheme:      scheme.Scheme,
+		Objects: []runtime.Object{
+			getRoute(map[string]interface{}{
+				"targetable": map[string]interface{}{
+					"domainInternal": targetDNS,
+				},
+			}),
+		},
+	}, {
+		Name: "subscriber not targetable",
+		InitialState: []runtime.Object{
+			getNewBroker(true),
+			getNewTrigger(routeSubscriber()),
+		},
+		ReconcileKey: fmt.Sprintf("%s/%s", testNS, triggerName),
+		WantErrMsg:   "status does not contain targetable",
+		Scheme:       scheme.Scheme,
+		Objects: []runtime.Object{
+			getRoute(map[string]interface{}{}),
+		},
+	},
+}
+
+func TestAllCases(t *testing.T) {
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		dc := tc.GetDynamicClient()
+
+		r := &reconciler{
+			client:        c,
+			dynamicClient: dc,
+			restConfig:    &rest.Config{},
+			recorder:      recorder,
+			logger:        zap.NewNop(),
+		}
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func getNewTrigger(subscriber *eventingv1alpha1.Callable) *eventingv1alpha1.Trigger {
+	return &eventingv1alpha1.Trigger{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Trigger",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      triggerName,
+		},
+		Spec: eventingv1alpha1.TriggerSpec{
+			Broker:     brokerName,
+			Subscriber: subscriber,
+		},
+	}
+}
+
+func withStatus(t *eventingv1alpha1.Trigger, f func(*eventingv1alpha1.TriggerStatus)) *eventingv1alpha1.Trigger {
+	t.Status.InitializeConditions()
+	f(&t.Status)
+	return t
+}
+
+func uriSubscriber() *eventingv1alpha1.Callable {
+	return &eventingv1alpha1.Callable{
+		TargetURI: &targetURI,
+	}
+}
+
+func k8sServiceSubscriber() *eventingv1alpha1.Callable {
+	return &eventingv1alpha1.Callable{
+		Target: &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Name:       k8sServiceName,
+		},
+	}
+}
+
+func withTargetNamespace(c *eventingv1alpha1.Callable, namespace string) *eventingv1alpha1.Callable {
+	c.Target.Namespace = namespace
+	return c
+}
+
+func routeSubscriber() *eventingv1alpha1.Callable {
+	return &eventingv1alpha1.Callable{
+		Target: &corev1.ObjectReference{
+			APIVersion: "serving.knative.dev/v1alpha1",
+			Kind:       "Route",
+			Name:       routeName,
+		},
+	}
+}
+
+func getNewBroker(ready bool) *eventingv1alpha1.Broker {
+	b := &eventingv1alpha1.Broker{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Broker",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      brokerName,
+		},
+	}
+	b.SetDefaults()
+	b.Status.InitializeConditions()
+	if ready {
+		ch := &eventingv1alpha1.Channel{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNS,
+				Name:      "default-broker",
+			},
+		}
+		controllertesting.SimulateProvisioned(ch, "default-broker-channel.testnamespace.svc.cluster.local")
+		b.Status.PropagateChannelStatus(ch)
+	}
+	return b
+}
+
+func getK8sService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      k8sServiceName,
+		},
+	}
+}
+
+func withNamespace(svc *corev1.Service, namespace string) *corev1.Service {
+	svc.Namespace = namespace
+	return svc
+}
+
+func getRoute(status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1alpha1",
+			"kind":       "Route",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      routeName,
+			},
+			"status": status,
+		},
+	}
+}
@@ -53,6 +53,8 @@ func kind(obj metav1.Object) schema.GroupVersionKind {
 		return flowsv1alpha.SchemeGroupVersion.WithKind("Flow")
 
 	// Eventing
+	case *eventingv1alpha.Broker:
+		return eventingv1alpha.SchemeGroupVersion.WithKind("Broker")
 	case *eventingv1alpha.Source:
 		return eventingv1alpha.SchemeGroupVersion.WithKind("Source")
 	case *eventingv1alpha.Channel:
@@ -61,6 +63,8 @@ func kind(obj metav1.Object) schema.GroupVersionKind {
 		return eventingv1alpha.SchemeGroupVersion.WithKind("ClusterProvisioner")
 	case *eventingv1alpha.Subscription:
 		return eventingv1alpha.SchemeGroupVersion.WithKind("Subscription")
+	case *eventingv1alpha.Trigger:
+		return eventingv1alpha.SchemeGroupVersion.WithKind("Trigger")
 
 	default:
 		panic(fmt.Sprintf("Unsupported object type %T", obj))
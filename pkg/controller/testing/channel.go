@@ -0,0 +1,32 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// SimulateProvisioned sets the Channel's status to what a Provisioner that has successfully
+// provisioned it would report, with domainInternal as its address, so that tests need not build a
+// ready status by hand.
+func SimulateProvisioned(ch *eventingv1alpha1.Channel, domainInternal string) {
+	ch.Status.InitializeConditions()
+	ch.Status.MarkCompatible()
+	ch.Status.MarkProvisioned()
+	ch.Status.SetSinkable(domainInternal)
+	ch.Status.SetSubscribable(ch.Namespace, ch.Name)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSimulateProvisioned(t *testing.T) {
+	ch := &eventingv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-channel"},
+	}
+	SimulateProvisioned(ch, "test-channel.test-namespace.svc.cluster.local")
+	if !ch.Status.IsReady() {
+		t.Errorf("Expected the Channel to be ready, got conditions %v", ch.Status.Conditions)
+	}
+	if got, want := ch.Status.Sinkable.DomainInternal, "test-channel.test-namespace.svc.cluster.local"; got != want {
+		t.Errorf("Unexpected domain. Expected %q, got %q", want, got)
+	}
+	if got := ch.Status.Subscribable.Channelable.Name; got != "test-channel" {
+		t.Errorf("Unexpected subscribable Channel. Expected %q, got %q", "test-channel", got)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClusterProvisionerOption configures a ClusterProvisioner built by NewClusterProvisioner.
+type ClusterProvisionerOption func(*eventingv1alpha1.ClusterProvisioner)
+
+// NewClusterProvisioner returns a ClusterProvisioner fixture with the given name, configured by
+// opts. Without options, it reconciles nothing and its status is empty.
+func NewClusterProvisioner(name string, opts ...ClusterProvisionerOption) *eventingv1alpha1.ClusterProvisioner {
+	cp := &eventingv1alpha1.ClusterProvisioner{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ClusterProvisioner",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	for _, opt := range opts {
+		opt(cp)
+	}
+	return cp
+}
+
+// WithReconciles sets the kind of resource the ClusterProvisioner reconciles.
+func WithReconciles(gk schema.GroupKind) ClusterProvisionerOption {
+	return func(cp *eventingv1alpha1.ClusterProvisioner) {
+		cp.Spec.Reconciles = metav1.GroupKind{Group: gk.Group, Kind: gk.Kind}
+	}
+}
+
+// WithReady marks the ClusterProvisioner Ready, or, if ready is false, leaves its readiness
+// Unknown.
+func WithReady(ready bool) ClusterProvisionerOption {
+	return func(cp *eventingv1alpha1.ClusterProvisioner) {
+		cp.Status.InitializeConditions()
+		if ready {
+			cp.Status.MarkReady()
+		}
+	}
+}
+
+// WithNotReadyReason marks the ClusterProvisioner not Ready, for the given reason.
+func WithNotReadyReason(reason, message string) ClusterProvisionerOption {
+	return func(cp *eventingv1alpha1.ClusterProvisioner) {
+		cp.Status.Conditions = duckv1alpha1.Conditions{{
+			Type:    eventingv1alpha1.ClusterProvisionerConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		}}
+	}
+}
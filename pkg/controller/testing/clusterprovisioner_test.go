@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewClusterProvisioner(t *testing.T) {
+	channels := schema.GroupKind{Group: eventingv1alpha1.SchemeGroupVersion.Group, Kind: "Channel"}
+	cp := NewClusterProvisioner("in-memory-channel", WithReconciles(channels), WithReady(true))
+	if cp.Name != "in-memory-channel" {
+		t.Errorf("Unexpected name. Expected %q, got %q", "in-memory-channel", cp.Name)
+	}
+	if !cp.Status.IsReady() {
+		t.Errorf("Expected the ClusterProvisioner to be ready, got conditions %v", cp.Status.Conditions)
+	}
+	if want := (metav1.GroupKind{Group: "eventing.knative.dev", Kind: "Channel"}); cp.Spec.Reconciles != want {
+		t.Errorf("Unexpected reconciled kind. Expected %v, got %v", want, cp.Spec.Reconciles)
+	}
+	if err := eventingv1alpha1.ValidateProvisionerForChannel(eventingv1alpha1.ProvisionerReference{}, cp); err != nil {
+		t.Errorf("Expected the ClusterProvisioner to be valid for Channels, got %v", err)
+	}
+
+	cp = NewClusterProvisioner("kafka", WithReconciles(channels), WithNotReadyReason("BrokersUnreachable", "no Kafka broker is reachable"))
+	if cp.Status.IsReady() {
+		t.Errorf("Expected the ClusterProvisioner not to be ready")
+	}
+	if c := cp.Status.GetCondition(eventingv1alpha1.ClusterProvisionerConditionReady); c == nil || c.Reason != "BrokersUnreachable" {
+		t.Errorf("Unexpected Ready condition %v", c)
+	}
+}
@@ -0,0 +1,564 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/provisioners/kafka"
+	cpcontroller "github.com/knative/eventing/pkg/provisioners/kafka/clusterprovisioner"
+	"github.com/knative/eventing/pkg/sidecar/configmap"
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"github.com/knative/eventing/pkg/system"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	portName      = "http"
+	portNumber    = 80
+	finalizerName = controllerAgentName
+
+	// backend is recorded in the status of Kafka Channels.
+	backend = "kafka"
+)
+
+// topicAdmin is the part of sarama.ClusterAdmin that manages the Channels' topics.
+type topicAdmin interface {
+	CreateTopic(topic string, detail *sarama.TopicDetail, validateOnly bool) error
+	DeleteTopic(topic string) error
+}
+
+// channelArguments are the arguments of Kafka Channels.
+type channelArguments struct {
+	// NumPartitions is the number of partitions of the Channel's topic.
+	NumPartitions *int32 `json:"numPartitions,omitempty"`
+
+	// ReplicationFactor is the number of replicas of each partition of the Channel's topic.
+	ReplicationFactor *int16 `json:"replicationFactor,omitempty"`
+}
+
+type reconciler struct {
+	client   client.Client
+	admin    topicAdmin
+	recorder record.EventRecorder
+	logger   *zap.Logger
+
+	configMapKey client.ObjectKey
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
+
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// TODO: use this to store the logger and set a deadline
+	ctx := context.TODO()
+	logger := r.logger.With(zap.Any("request", request))
+
+	c := &eventingv1alpha1.Channel{}
+	err := r.client.Get(ctx, request.NamespacedName, c)
+
+	// The Channel may have been deleted since it was added to the workqueue. If so, there is
+	// nothing to be done.
+	if errors.IsNotFound(err) {
+		logger.Info("Could not find Channel", zap.Error(err))
+		return reconcile.Result{}, nil
+	}
+
+	// Any other error should be retried in another reconciliation.
+	if err != nil {
+		logger.Error("Unable to Get Channel", zap.Error(err))
+		return reconcile.Result{}, err
+	}
+
+	// Does this Controller control this Channel?
+	if !r.shouldReconcile(c) {
+		logger.Info("Not reconciling Channel, it is not controlled by this Controller", zap.Stringer("provisioner", c.Spec.Provisioner))
+		return reconcile.Result{}, nil
+	}
+	logger.Info("Reconciling Channel")
+
+	// Modify a copy, not the original.
+	c = c.DeepCopy()
+
+	err = r.reconcile(ctx, c)
+	if err != nil {
+		logger.Info("Error reconciling Channel", zap.Error(err))
+		// Note that we do not return the error here, because we want to update the Status
+		// regardless of the error.
+	}
+
+	if updateStatusErr := r.updateChannel(ctx, c); updateStatusErr != nil {
+		logger.Info("Error updating Channel Status", zap.Error(updateStatusErr))
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+// shouldReconcile determines if this Controller should control (and therefore reconcile) a given
+// ClusterProvisioner. This Controller only handles Kafka channels.
+func (r *reconciler) shouldReconcile(c *eventingv1alpha1.Channel) bool {
+	if c.Spec.Provisioner != nil {
+		return cpcontroller.IsControlled(c.Spec.Provisioner, cpcontroller.Channel)
+	}
+	return false
+}
+
+func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel) error {
+	logger := r.logger.With(zap.Any("channel", c))
+
+	// Kafka Channels have no provisioner-specific conditions.
+	c.Status.ClearStaleConditions()
+	c.Status.InitializeConditions()
+
+	// We are syncing four things:
+	// 1. The Kafka topic backing this Channel.
+	// 2. The K8s Service to talk to this Channel.
+	// 3. The Istio VirtualService to talk to this Channel.
+	// 4. The configuration of all Channel subscriptions.
+
+	// We always need to sync the Channel config, so do it first. Deleted Channels are left out of
+	// it, so the dispatcher stops consuming their topics.
+	if err := r.syncChannelConfig(ctx); err != nil {
+		logger.Info("Error updating syncing the Channel config", zap.Error(err))
+		return err
+	}
+
+	if c.DeletionTimestamp != nil {
+		// K8s garbage collection will delete the K8s service and VirtualService for this channel.
+		// We use a finalizer to ensure the channel config has been synced and the topic deleted.
+		if err := r.deleteTopic(c); err != nil {
+			logger.Info("Error deleting the Channel's topic", zap.Error(err))
+			return err
+		}
+		r.removeFinalizer(c)
+		return nil
+	}
+
+	r.addFinalizer(c)
+	if err := r.setSpecHash(c); err != nil {
+		logger.Info("Error hashing the Channel's spec", zap.Error(err))
+		return err
+	}
+
+	args := channelArguments{}
+	if err := c.Spec.DecodeArguments(&args); err != nil {
+		c.Status.MarkIncompatible("InvalidArguments", fmt.Sprintf("unable to decode the arguments: %v", err))
+		logger.Info("Error decoding the Channel's arguments", zap.Error(err))
+		return err
+	}
+	c.Status.MarkCompatible()
+
+	if err := r.createTopic(c, args); err != nil {
+		c.Status.PropagateProvisionerStatus(false, "TopicNotCreated", fmt.Sprintf("unable to create the topic: %v", err))
+		logger.Info("Error creating the Channel's topic", zap.Error(err))
+		return err
+	}
+
+	if err := c.MarkSelfSubscribable(); err != nil {
+		logger.Info("Error making the Channel Subscribable", zap.Error(err))
+		return err
+	}
+
+	if svc, err := r.createK8sService(ctx, c); err != nil {
+		logger.Info("Error creating the Channel's K8s Service", zap.Error(err))
+		return err
+	} else {
+		c.Status.SetSinkable(controller.ServiceHostName(svc.Name, svc.Namespace))
+	}
+
+	if err := r.createVirtualService(ctx, c); err != nil {
+		logger.Info("Error creating the Virtual Service for the Channel", zap.Error(err))
+		return err
+	}
+
+	c.Status.SetBackend(backend)
+	c.Status.MarkProvisioned()
+	c.Status.ObserveGeneration(c.Spec.Generation)
+	return nil
+}
+
+// createTopic creates the Kafka topic backing the Channel, if it doesn't exist yet. The topic's
+// partitions and replication are only set when it is created.
+func (r *reconciler) createTopic(c *eventingv1alpha1.Channel, args channelArguments) error {
+	detail := &sarama.TopicDetail{
+		NumPartitions:     DefaultNumPartitions,
+		ReplicationFactor: DefaultReplicationFactor,
+	}
+	if args.NumPartitions != nil {
+		detail.NumPartitions = *args.NumPartitions
+	}
+	if args.ReplicationFactor != nil {
+		detail.ReplicationFactor = *args.ReplicationFactor
+	}
+
+	topic := kafka.TopicName(c.Namespace, c.Name)
+	err := r.admin.CreateTopic(topic, detail, false)
+	if err == sarama.ErrTopicAlreadyExists {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	r.logger.Info("Created topic", zap.String("topic", topic))
+	return nil
+}
+
+// deleteTopic deletes the Kafka topic backing the Channel, if it exists.
+func (r *reconciler) deleteTopic(c *eventingv1alpha1.Channel) error {
+	topic := kafka.TopicName(c.Namespace, c.Name)
+	err := r.admin.DeleteTopic(topic)
+	if err == sarama.ErrUnknownTopicOrPartition {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	r.logger.Info("Deleted topic", zap.String("topic", topic))
+	return nil
+}
+
+func (r *reconciler) addFinalizer(c *eventingv1alpha1.Channel) {
+	finalizers := sets.NewString(c.Finalizers...)
+	finalizers.Insert(finalizerName)
+	c.Finalizers = finalizers.List()
+}
+
+func (r *reconciler) removeFinalizer(c *eventingv1alpha1.Channel) {
+	finalizers := sets.NewString(c.Finalizers...)
+	finalizers.Delete(finalizerName)
+	c.Finalizers = finalizers.List()
+}
+
+// setSpecHash records the hash of the Channel's spec in its SpecHashAnnotation.
+func (r *reconciler) setSpecHash(c *eventingv1alpha1.Channel) error {
+	hash, err := c.SpecHash()
+	if err != nil {
+		return err
+	}
+	if c.Annotations == nil {
+		c.Annotations = make(map[string]string)
+	}
+	c.Annotations[eventingv1alpha1.SpecHashAnnotation] = hash
+	return nil
+}
+
+func (r *reconciler) getK8sService(ctx context.Context, c *eventingv1alpha1.Channel) (*corev1.Service, error) {
+	svcKey := types.NamespacedName{
+		Namespace: c.Namespace,
+		Name:      controller.ChannelServiceName(c.Name),
+	}
+	svc := &corev1.Service{}
+	err := r.client.Get(ctx, svcKey, svc)
+	return svc, err
+}
+
+func (r *reconciler) createK8sService(ctx context.Context, c *eventingv1alpha1.Channel) (*corev1.Service, error) {
+	svc, err := r.getK8sService(ctx, c)
+
+	if errors.IsNotFound(err) {
+		svc = newK8sService(c)
+		err = r.client.Create(ctx, svc)
+	}
+
+	// If an error occurred in either Get or Create, we need to reconcile again.
+	if err != nil {
+		return nil, err
+	}
+
+	// Older versions of this controller may have created the K8s Service without an owner, so
+	// backfill it to have the Service garbage collected with the Channel.
+	if metav1.GetControllerOf(svc) == nil {
+		svc.OwnerReferences = append(svc.OwnerReferences, *newChannelControllerRef(c))
+		if err := r.client.Update(ctx, svc); err != nil {
+			return nil, err
+		}
+	} else if !metav1.IsControlledBy(svc, c) {
+		r.logger.Warn("Channel's K8s Service is not owned by the Channel", zap.Any("channel", c), zap.Any("service", svc))
+	}
+	return svc, nil
+}
+
+func (r *reconciler) getVirtualService(ctx context.Context, c *eventingv1alpha1.Channel) (*istiov1alpha3.VirtualService, error) {
+	vsk := client.ObjectKey{
+		Namespace: c.Namespace,
+		Name:      controller.ChannelVirtualServiceName(c.ObjectMeta.Name),
+	}
+	vs := &istiov1alpha3.VirtualService{}
+	err := r.client.Get(ctx, vsk, vs)
+	return vs, err
+}
+
+func (r *reconciler) createVirtualService(ctx context.Context, c *eventingv1alpha1.Channel) error {
+	virtualService, err := r.getVirtualService(ctx, c)
+
+	// If the resource doesn't exist, we'll create it
+	if errors.IsNotFound(err) {
+		virtualService = newVirtualService(c)
+		err = r.client.Create(ctx, virtualService)
+	}
+
+	// If an error occurs during Get/Create, we'll requeue the item so we can
+	// attempt processing again later. This could have been caused by a
+	// temporary network failure, or any other transient reason.
+	if err != nil {
+		return err
+	}
+
+	// Older versions of this controller may have created the VirtualService without an owner, so
+	// backfill it to have the VirtualService garbage collected with the Channel. If it is
+	// controlled by something else, we should log a warning, but don't consider it an error.
+	if metav1.GetControllerOf(virtualService) == nil {
+		virtualService.OwnerReferences = append(virtualService.OwnerReferences, *newChannelControllerRef(c))
+		if err := r.client.Update(ctx, virtualService); err != nil {
+			return err
+		}
+	} else if !metav1.IsControlledBy(virtualService, c) {
+		r.logger.Warn("VirtualService not owned by Channel", zap.Any("channel", c), zap.Any("virtualService", virtualService))
+	}
+	return nil
+}
+
+// newChannelControllerRef returns an OwnerReference making the Channel the controller of the
+// resource it is set on.
+func newChannelControllerRef(c *eventingv1alpha1.Channel) *metav1.OwnerReference {
+	return metav1.NewControllerRef(c, schema.GroupVersionKind{
+		Group:   eventingv1alpha1.SchemeGroupVersion.Group,
+		Version: eventingv1alpha1.SchemeGroupVersion.Version,
+		Kind:    "Channel",
+	})
+}
+
+// newK8sService creates a new Service for a Channel resource. It also sets the appropriate
+// OwnerReferences on the resource so handleObject can discover the Channel resource that 'owns' it.
+// As well as being garbage collected when the Channel is deleted.
+func newK8sService(c *eventingv1alpha1.Channel) *corev1.Service {
+	labels := map[string]string{
+		"channel":     c.Name,
+		"provisioner": c.Spec.Provisioner.Ref.Name,
+	}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.ChannelServiceName(c.ObjectMeta.Name),
+			Namespace: c.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*newChannelControllerRef(c),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name: portName,
+					Port: portNumber,
+				},
+			},
+		},
+	}
+}
+
+// newVirtualService creates a new VirtualService for a Channel resource. It also sets the
+// appropriate OwnerReferences on the resource so handleObject can discover the Channel resource
+// that 'owns' it. As well as being garbage collected when the Channel is deleted.
+func newVirtualService(channel *eventingv1alpha1.Channel) *istiov1alpha3.VirtualService {
+	labels := map[string]string{
+		"channel":     channel.Name,
+		"provisioner": channel.Spec.Provisioner.Ref.Name,
+	}
+	destinationHost := controller.ServiceHostName(controller.ClusterBusDispatcherServiceName(channel.Spec.Provisioner.Ref.Name), system.Namespace)
+	return &istiov1alpha3.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.ChannelVirtualServiceName(channel.Name),
+			Namespace: channel.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*newChannelControllerRef(channel),
+			},
+		},
+		Spec: istiov1alpha3.VirtualServiceSpec{
+			Hosts: []string{
+				controller.ServiceHostName(controller.ChannelServiceName(channel.Name), channel.Namespace),
+				controller.ChannelHostName(channel.Name, channel.Namespace),
+			},
+			Http: []istiov1alpha3.HTTPRoute{{
+				Rewrite: &istiov1alpha3.HTTPRewrite{
+					Authority: controller.ChannelHostName(channel.Name, channel.Namespace),
+				},
+				Route: []istiov1alpha3.DestinationWeight{{
+					Destination: istiov1alpha3.Destination{
+						Host: destinationHost,
+						Port: istiov1alpha3.PortSelector{
+							Number: portNumber,
+						},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+func (r *reconciler) updateChannel(ctx context.Context, u *eventingv1alpha1.Channel) error {
+	o := &eventingv1alpha1.Channel{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: u.Namespace, Name: u.Name}, o); err != nil {
+		r.logger.Info("Error getting Channel for status update", zap.Error(err), zap.Any("updatedChannel", u))
+		return err
+	}
+
+	updated := false
+	if !equality.Semantic.DeepEqual(o.Finalizers, u.Finalizers) {
+		updated = true
+		o.SetFinalizers(u.Finalizers)
+	}
+	if hash, ok := u.Annotations[eventingv1alpha1.SpecHashAnnotation]; ok && o.Annotations[eventingv1alpha1.SpecHashAnnotation] != hash {
+		updated = true
+		if o.Annotations == nil {
+			o.Annotations = make(map[string]string)
+		}
+		o.Annotations[eventingv1alpha1.SpecHashAnnotation] = hash
+	}
+	if !equality.Semantic.DeepEqual(o.Status, u.Status) {
+		updated = true
+		o.Status = u.Status
+	}
+
+	if updated {
+		return r.client.Update(ctx, o)
+	}
+	return nil
+}
+
+func (r *reconciler) syncChannelConfig(ctx context.Context) error {
+	channels, err := r.listAllChannels(ctx)
+	if err != nil {
+		r.logger.Info("Unable to list channels", zap.Error(err))
+		return err
+	}
+	config := multiChannelFanoutConfig(channels)
+	return r.writeConfigMap(ctx, config)
+}
+
+func (r *reconciler) writeConfigMap(ctx context.Context, config *multichannelfanout.Config) error {
+	logger := r.logger.With(zap.Any("configMap", r.configMapKey))
+
+	updated, err := configmap.SerializeConfig(*config)
+	if err != nil {
+		r.logger.Error("Unable to serialize config", zap.Error(err), zap.Any("config", config))
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.client.Get(ctx, r.configMapKey, cm)
+	if errors.IsNotFound(err) {
+		cm = r.createNewConfigMap(updated)
+		err = r.client.Create(ctx, cm)
+	}
+	if err != nil {
+		logger.Info("Unable to get/create ConfigMap", zap.Error(err))
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(cm.Data, updated) {
+		// Nothing to update.
+		return nil
+	}
+
+	cm.Data = updated
+	return r.client.Update(ctx, cm)
+}
+
+func (r *reconciler) createNewConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: r.configMapKey.Namespace,
+			Name:      r.configMapKey.Name,
+		},
+		Data: data,
+	}
+}
+
+// multiChannelFanoutConfig returns the dispatcher's configuration for the Channels. Every Channel
+// that isn't being deleted is included, with or without subscribers, so that the dispatcher accepts
+// its events.
+func multiChannelFanoutConfig(channels []eventingv1alpha1.Channel) *multichannelfanout.Config {
+	cc := make([]multichannelfanout.ChannelConfig, 0)
+	for _, c := range channels {
+		if c.DeletionTimestamp != nil {
+			continue
+		}
+		config := multichannelfanout.ChannelConfig{
+			Namespace: c.Namespace,
+			Name:      c.Name,
+		}
+		if c.Spec.Channelable != nil {
+			config.FanoutConfig.Subscriptions = c.Spec.Channelable.Subscribers
+		}
+		cc = append(cc, config)
+	}
+	return &multichannelfanout.Config{
+		ChannelConfigs: cc,
+	}
+}
+
+func (r *reconciler) listAllChannels(ctx context.Context) ([]eventingv1alpha1.Channel, error) {
+	channels := make([]eventingv1alpha1.Channel, 0)
+
+	opts := &client.ListOptions{
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Channel",
+			},
+		},
+	}
+	for {
+		cl := &eventingv1alpha1.ChannelList{}
+		if err := r.client.List(ctx, opts, cl); err != nil {
+			return nil, err
+		}
+
+		for _, c := range cl.Items {
+			if r.shouldReconcile(&c) {
+				channels = append(channels, c)
+			}
+		}
+		if cl.Continue != "" {
+			opts.Raw.Continue = cl.Continue
+		} else {
+			return channels, nil
+		}
+	}
+}
@@ -0,0 +1,1150 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	"github.com/knative/eventing/pkg/sidecar/configmap"
+	"github.com/knative/eventing/pkg/sidecar/fanout"
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	cpName = "kafka"
+
+	cNamespace = "test-namespace"
+	cName      = "test-channel"
+	cUID       = "test-uid"
+
+	cmNamespace = cNamespace
+	cmName      = "test-config-map"
+
+	testErrorMessage = "test induced error"
+
+	insertedByVerifyConfigMapData = "data inserted by verifyConfigMapData so that it can be WantPresent"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+
+	truePointer = true
+
+	// channelsConfig and channels are linked together. A change to one, will likely require a
+	// change to the other. channelsConfig is the serialized config of channels for everything
+	// provisioned by the Kafka provisioner. Deleted Channels are left out.
+	channelsConfig = multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{
+			{
+				Namespace: cNamespace,
+				Name:      "c1",
+				FanoutConfig: fanout.Config{
+					Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
+						{
+							CallableDomain: "foo",
+						},
+						{
+							SinkableDomain: "bar",
+						},
+						{
+							CallableDomain: "baz",
+							SinkableDomain: "qux",
+						},
+					},
+				},
+			},
+			{
+				Namespace: cNamespace,
+				Name:      "c3",
+				FanoutConfig: fanout.Config{
+					Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
+						{
+							CallableDomain: "steve",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	channels = []eventingv1alpha1.Channel{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cNamespace,
+				Name:      "c1",
+			},
+			TypeMeta: metav1.TypeMeta{
+				Kind: "Channel",
+			},
+			Spec: eventingv1alpha1.ChannelSpec{
+				Provisioner: &eventingv1alpha1.ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: cpName,
+					},
+				},
+				Channelable: &eventingv1alpha1.Channelable{
+					Subscribers: []eventingv1alpha1.ChannelSubscriberSpec{
+						{
+							CallableDomain: "foo",
+						},
+						{
+							SinkableDomain: "bar",
+						},
+						{
+							CallableDomain: "baz",
+							SinkableDomain: "qux",
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cNamespace,
+				Name:      "c2",
+			},
+			TypeMeta: metav1.TypeMeta{
+				Kind: "Channel",
+			},
+			Spec: eventingv1alpha1.ChannelSpec{
+				Provisioner: &eventingv1alpha1.ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: "some-other-provisioner",
+					},
+				},
+				Channelable: &eventingv1alpha1.Channelable{
+					Subscribers: []eventingv1alpha1.ChannelSubscriberSpec{
+						{
+							CallableDomain: "anything",
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cNamespace,
+				Name:      "c3",
+			},
+			TypeMeta: metav1.TypeMeta{
+				Kind: "Channel",
+			},
+			Spec: eventingv1alpha1.ChannelSpec{
+				Provisioner: &eventingv1alpha1.ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: cpName,
+					},
+				},
+				Channelable: &eventingv1alpha1.Channelable{
+					Subscribers: []eventingv1alpha1.ChannelSubscriberSpec{
+						{
+							CallableDomain: "steve",
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         cNamespace,
+				Name:              "c4",
+				DeletionTimestamp: &deletionTime,
+			},
+			TypeMeta: metav1.TypeMeta{
+				Kind: "Channel",
+			},
+			Spec: eventingv1alpha1.ChannelSpec{
+				Provisioner: &eventingv1alpha1.ProvisionerReference{
+					Ref: &corev1.ObjectReference{
+						Name: cpName,
+					},
+				},
+				Channelable: &eventingv1alpha1.Channelable{
+					Subscribers: []eventingv1alpha1.ChannelSubscriberSpec{
+						{
+							CallableDomain: "deleted",
+						},
+					},
+				},
+			},
+		},
+	}
+)
+
+func init() {
+	// Add types to scheme.
+	eventingv1alpha1.AddToScheme(scheme.Scheme)
+	corev1.AddToScheme(scheme.Scheme)
+	istiov1alpha3.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	orig := r.client
+	n := fake.NewFakeClient()
+	if orig == n {
+		t.Errorf("Original and new clients are identical: %v", orig)
+	}
+	err := r.InjectClient(n)
+	if err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Channel not found",
+		},
+		{
+			Name: "Error getting Channel",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGettingChannel(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel not reconciled - nil provisioner",
+			InitialState: []runtime.Object{
+				makeChannelNilProvisioner(),
+			},
+		},
+		{
+			Name: "Channel not reconciled - nil ref",
+			InitialState: []runtime.Object{
+				makeChannelNilRef(),
+			},
+		},
+		{
+			Name: "Channel not reconciled - namespace",
+			InitialState: []runtime.Object{
+				makeChannelWithWrongProvisionerNamespace(),
+			},
+		},
+		{
+			Name: "Channel not reconciled - name",
+			InitialState: []runtime.Object{
+				makeChannelWithWrongProvisionerName(),
+			},
+		},
+		{
+			Name: "Channel deleted - Channel config sync fails",
+			InitialState: []runtime.Object{
+				makeDeletingChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists: errorListingChannels(),
+			},
+			WantPresent: []runtime.Object{
+				// Finalizer has not been removed.
+				makeDeletingChannel(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel deleted - finalizer removed",
+			InitialState: []runtime.Object{
+				makeDeletingChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingChannelWithoutFinalizer(),
+			},
+		},
+		{
+			Name: "Channel config sync fails - can't list Channels",
+			InitialState: []runtime.Object{
+				makeChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists: errorListingChannels(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel config sync fails - can't get ConfigMap",
+			InitialState: []runtime.Object{
+				makeChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGettingConfigMap(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel config sync fails - can't create ConfigMap",
+			InitialState: []runtime.Object{
+				makeChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreatingConfigMap(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel config sync fails - can't update ConfigMap",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdatingConfigMap(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "K8s service get fails",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGettingK8sService(),
+			},
+			WantPresent: []runtime.Object{
+				makeChannelWithFinalizerAndSubscribable(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "K8s service creation fails",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreatingK8sService(),
+			},
+			WantPresent: []runtime.Object{
+				// TODO: This should have a useful error message saying that the K8s Service failed.
+				makeChannelWithFinalizerAndSubscribable(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "K8s service already exists - without an owner, owner backfilled",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sServiceNotOwnedByChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sService(),
+			},
+		},
+		{
+			Name: "K8s service already exists - owned by something else",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sServiceOwnedBySomethingElse(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sServiceOwnedBySomethingElse(),
+			},
+		},
+		{
+			Name: "Virtual service get fails",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGettingVirtualService(),
+			},
+			WantPresent: []runtime.Object{
+				// TODO: This should have a useful error message saying that the VirtualService
+				// failed.
+				makeChannelWithFinalizerAndSubscribableAndSinkable(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Virtual service creation fails",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreatingVirtualService(),
+			},
+			WantPresent: []runtime.Object{
+				// TODO: This should have a useful error message saying that the VirtualService
+				// failed.
+				makeChannelWithFinalizerAndSubscribableAndSinkable(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "VirtualService already exists - without an owner, owner backfilled",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualServiceNowOwnedByChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeVirtualService(),
+			},
+		},
+		{
+			Name: "Channel get for update fails",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorOnSecondChannelGet(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel update fails",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdatingChannel(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel reconcile successful - Channel list follows pagination",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeConfigMap(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists: (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+				// This is more accurate to be in WantPresent, but we need to check JSON equality,
+				// not string equality, so it can't be done in WantPresent. Instead, we verify
+				// during the update call, swapping out the data and WantPresent with that inserted
+				// data.
+				MockUpdates: verifyConfigMapData(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sService(),
+				makeVirtualService(),
+				makeConfigMapWithVerifyConfigMapData(),
+			},
+		},
+		{
+			Name: "Channel reconcile successful - spec hash updated",
+			InitialState: []runtime.Object{
+				makeReadyChannelWithStaleSpecHash(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists:   (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+				MockUpdates: verifyConfigMapData(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeConfigMapWithVerifyConfigMapData(),
+			},
+		},
+		{
+			Name: "Channel reconcile successful - new generation observed",
+			InitialState: []runtime.Object{
+				makeReadyChannelWithNewGeneration(),
+				makeConfigMap(),
+				makeK8sService(),
+				makeVirtualService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists:   (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+				MockUpdates: verifyConfigMapData(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannelWithObservedGeneration(),
+				makeConfigMapWithVerifyConfigMapData(),
+			},
+		},
+		{
+			Name: "Channel reconcile successful - stale conditions cleared",
+			InitialState: []runtime.Object{
+				makeChannelWithStaleCondition(),
+				makeConfigMap(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists:   (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+				MockUpdates: verifyConfigMapData(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sService(),
+				makeVirtualService(),
+				makeConfigMapWithVerifyConfigMapData(),
+			},
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		configMapKey := types.NamespacedName{
+			Namespace: cmNamespace,
+			Name:      cmName,
+		}
+		c := tc.GetClient()
+		r := &reconciler{
+			client:       c,
+			admin:        &fakeAdmin{},
+			recorder:     recorder,
+			logger:       zap.NewNop(),
+			configMapKey: configMapKey,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("/%s", cName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func TestReconcile_Topic(t *testing.T) {
+	topic := "knative-eventing-channel.test-namespace.test-channel"
+	testCases := []struct {
+		controllertesting.TestCase
+		admin       *fakeAdmin
+		wantCreated map[string]sarama.TopicDetail
+		wantDeleted []string
+	}{
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Topic created with defaults",
+				InitialState: []runtime.Object{
+					makeChannel(),
+					makeConfigMap(),
+				},
+				WantPresent: []runtime.Object{
+					makeReadyChannel(),
+				},
+			},
+			admin: &fakeAdmin{},
+			wantCreated: map[string]sarama.TopicDetail{
+				topic: {NumPartitions: 1, ReplicationFactor: 1},
+			},
+		},
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Topic created with arguments",
+				InitialState: []runtime.Object{
+					makeChannelWithArguments(`{"numPartitions":3,"replicationFactor":2}`),
+					makeConfigMap(),
+				},
+			},
+			admin: &fakeAdmin{},
+			wantCreated: map[string]sarama.TopicDetail{
+				topic: {NumPartitions: 3, ReplicationFactor: 2},
+			},
+		},
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Topic already exists",
+				InitialState: []runtime.Object{
+					makeChannel(),
+					makeConfigMap(),
+				},
+				WantPresent: []runtime.Object{
+					makeReadyChannel(),
+				},
+			},
+			admin: &fakeAdmin{createErr: sarama.ErrTopicAlreadyExists},
+		},
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Topic creation fails",
+				InitialState: []runtime.Object{
+					makeChannel(),
+					makeConfigMap(),
+				},
+				WantPresent: []runtime.Object{
+					makeChannelWithTopicNotCreated(),
+				},
+				WantErrMsg: testErrorMessage,
+			},
+			admin: &fakeAdmin{createErr: errors.New(testErrorMessage)},
+		},
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Invalid arguments",
+				InitialState: []runtime.Object{
+					makeChannelWithArguments(`{"numPartitions":"three"}`),
+					makeConfigMap(),
+				},
+				WantPresent: []runtime.Object{
+					makeChannelWithInvalidArguments(),
+				},
+				WantErrMsg: "json: cannot unmarshal string into Go struct field channelArguments.numPartitions of type int32",
+			},
+			admin: &fakeAdmin{},
+		},
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Channel deleted - topic deleted",
+				InitialState: []runtime.Object{
+					makeDeletingChannel(),
+				},
+				WantPresent: []runtime.Object{
+					makeDeletingChannelWithoutFinalizer(),
+				},
+			},
+			admin:       &fakeAdmin{},
+			wantDeleted: []string{topic},
+		},
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Channel deleted - topic already gone",
+				InitialState: []runtime.Object{
+					makeDeletingChannel(),
+				},
+				WantPresent: []runtime.Object{
+					makeDeletingChannelWithoutFinalizer(),
+				},
+			},
+			admin: &fakeAdmin{deleteErr: sarama.ErrUnknownTopicOrPartition},
+		},
+		{
+			TestCase: controllertesting.TestCase{
+				Name: "Channel deleted - topic deletion fails",
+				InitialState: []runtime.Object{
+					makeDeletingChannel(),
+				},
+				WantPresent: []runtime.Object{
+					// Finalizer has not been removed.
+					makeDeletingChannel(),
+				},
+				WantErrMsg: testErrorMessage,
+			},
+			admin: &fakeAdmin{deleteErr: errors.New(testErrorMessage)},
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			admin:    tc.admin,
+			recorder: recorder,
+			logger:   zap.NewNop(),
+			configMapKey: types.NamespacedName{
+				Namespace: cmNamespace,
+				Name:      cmName,
+			},
+		}
+		tc.ReconcileKey = fmt.Sprintf("/%s", cName)
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Runner(t, r, c)(t)
+			if tc.wantCreated != nil {
+				if diff := cmp.Diff(tc.wantCreated, tc.admin.created); diff != "" {
+					t.Errorf("Unexpected topics created (-want +got): %s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.wantDeleted, tc.admin.deleted); diff != "" {
+				t.Errorf("Unexpected topics deleted (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func makeChannel() *eventingv1alpha1.Channel {
+	c := &eventingv1alpha1.Channel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Channel",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cNamespace,
+			Name:      cName,
+			UID:       cUID,
+		},
+		Spec: eventingv1alpha1.ChannelSpec{
+			Provisioner: &eventingv1alpha1.ProvisionerReference{
+				Ref: &corev1.ObjectReference{
+					Name: cpName,
+				},
+			},
+		},
+	}
+	c.Status.InitializeConditions()
+	return c
+}
+
+func makeChannelWithFinalizerAndSubscribable() *eventingv1alpha1.Channel {
+	c := makeChannelWithFinalizer()
+	c.Status.MarkCompatible()
+	c.Status.SetSubscribable(c.Namespace, c.Name)
+	return c
+}
+
+func makeChannelWithFinalizerAndSubscribableAndSinkable() *eventingv1alpha1.Channel {
+	c := makeChannelWithFinalizerAndSubscribable()
+	c.Status.SetSinkable(fmt.Sprintf("%s-channel.%s.svc.cluster.local", c.Name, c.Namespace))
+	return c
+}
+
+func makeReadyChannel() *eventingv1alpha1.Channel {
+	// Ready channels have the finalizer and are Subscribable and Sinkable.
+	c := makeChannelWithFinalizerAndSubscribableAndSinkable()
+	c.Status.MarkCompatible()
+	c.Status.SetBackend("kafka")
+	c.Status.MarkProvisioned()
+	return c
+}
+
+func makeReadyChannelWithNewGeneration() *eventingv1alpha1.Channel {
+	c := makeReadyChannel()
+	c.Spec.Generation = 2
+	c.Status.ObservedGeneration = 1
+	return c
+}
+
+func makeReadyChannelWithObservedGeneration() *eventingv1alpha1.Channel {
+	c := makeReadyChannelWithNewGeneration()
+	c.Status.ObservedGeneration = 2
+	return c
+}
+
+func makeChannelWithArguments(args string) *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(args)}
+	return c
+}
+
+func makeChannelWithTopicNotCreated() *eventingv1alpha1.Channel {
+	c := makeChannelWithFinalizer()
+	c.Status.MarkCompatible()
+	c.Status.PropagateProvisionerStatus(false, "TopicNotCreated", "unable to create the topic: "+testErrorMessage)
+	return c
+}
+
+func makeChannelWithInvalidArguments() *eventingv1alpha1.Channel {
+	c := makeChannelWithArguments(`{"numPartitions":"three"}`)
+	c.Finalizers = []string{finalizerName}
+	hash, _ := c.SpecHash()
+	c.Annotations = map[string]string{eventingv1alpha1.SpecHashAnnotation: hash}
+	c.Status.MarkIncompatible("InvalidArguments", "unable to decode the arguments: json: cannot unmarshal string into Go struct field channelArguments.numPartitions of type int32")
+	return c
+}
+
+func makeChannelWithStaleCondition() *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Status.Conditions = append(c.Status.Conditions, duckv1alpha1.Condition{
+		Type:   "DispatcherReady",
+		Status: corev1.ConditionTrue,
+	})
+	return c
+}
+
+func makeChannelNilProvisioner() *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Spec.Provisioner = nil
+	return c
+}
+
+func makeChannelNilRef() *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Spec.Provisioner.Ref = nil
+	return c
+}
+
+func makeChannelWithWrongProvisionerNamespace() *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Spec.Provisioner.Ref.Namespace = "wrong-namespace"
+	return c
+}
+
+func makeChannelWithWrongProvisionerName() *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Spec.Provisioner.Ref.Name = "wrong-name"
+	return c
+}
+
+func makeChannelWithFinalizer() *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Finalizers = []string{finalizerName}
+	hash, _ := c.SpecHash()
+	c.Annotations = map[string]string{eventingv1alpha1.SpecHashAnnotation: hash}
+	return c
+}
+
+func makeReadyChannelWithStaleSpecHash() *eventingv1alpha1.Channel {
+	c := makeReadyChannel()
+	c.Annotations[eventingv1alpha1.SpecHashAnnotation] = "stale"
+	return c
+}
+
+func makeDeletingChannel() *eventingv1alpha1.Channel {
+	c := makeChannelWithFinalizer()
+	c.DeletionTimestamp = &deletionTime
+	return c
+}
+
+func makeDeletingChannelWithoutFinalizer() *eventingv1alpha1.Channel {
+	c := makeDeletingChannel()
+	c.Finalizers = nil
+	return c
+}
+
+func makeConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cmNamespace,
+			Name:      cmName,
+		},
+	}
+}
+
+func makeConfigMapWithVerifyConfigMapData() *corev1.ConfigMap {
+	cm := makeConfigMap()
+	cm.Data = map[string]string{}
+	cm.Data[configmap.MultiChannelFanoutConfigKey] = insertedByVerifyConfigMapData
+	return cm
+}
+
+func makeK8sService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-channel", cName),
+			Namespace: cNamespace,
+			Labels: map[string]string{
+				"channel":     cName,
+				"provisioner": cpName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         eventingv1alpha1.SchemeGroupVersion.String(),
+					Kind:               "Channel",
+					Name:               cName,
+					UID:                cUID,
+					Controller:         &truePointer,
+					BlockOwnerDeletion: &truePointer,
+				},
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name: portName,
+					Port: portNumber,
+				},
+			},
+		},
+	}
+}
+
+func makeK8sServiceNotOwnedByChannel() *corev1.Service {
+	svc := makeK8sService()
+	svc.OwnerReferences = nil
+	return svc
+}
+
+func makeK8sServiceOwnedBySomethingElse() *corev1.Service {
+	svc := makeK8sService()
+	svc.OwnerReferences[0].Kind = "ConfigMap"
+	svc.OwnerReferences[0].Name = "something-else"
+	svc.OwnerReferences[0].UID = "something-else-uid"
+	return svc
+}
+
+func makeVirtualService() *istiov1alpha3.VirtualService {
+	return &istiov1alpha3.VirtualService{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: istiov1alpha3.SchemeGroupVersion.String(),
+			Kind:       "VirtualService",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-channel", cName),
+			Namespace: cNamespace,
+			Labels: map[string]string{
+				"channel":     cName,
+				"provisioner": cpName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         eventingv1alpha1.SchemeGroupVersion.String(),
+					Kind:               "Channel",
+					Name:               cName,
+					UID:                cUID,
+					Controller:         &truePointer,
+					BlockOwnerDeletion: &truePointer,
+				},
+			},
+		},
+		Spec: istiov1alpha3.VirtualServiceSpec{
+			Hosts: []string{
+				fmt.Sprintf("%s-channel.%s.svc.cluster.local", cName, cNamespace),
+				fmt.Sprintf("%s.%s.channels.cluster.local", cName, cNamespace),
+			},
+			Http: []istiov1alpha3.HTTPRoute{{
+				Rewrite: &istiov1alpha3.HTTPRewrite{
+					Authority: fmt.Sprintf("%s.%s.channels.cluster.local", cName, cNamespace),
+				},
+				Route: []istiov1alpha3.DestinationWeight{{
+					Destination: istiov1alpha3.Destination{
+						Host: "kafka-clusterbus.knative-eventing.svc.cluster.local",
+						Port: istiov1alpha3.PortSelector{
+							Number: portNumber,
+						},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+func makeVirtualServiceNowOwnedByChannel() *istiov1alpha3.VirtualService {
+	vs := makeVirtualService()
+	vs.OwnerReferences = nil
+	return vs
+}
+
+func errorOnSecondChannelGet() []controllertesting.MockGet {
+	passThrough := []controllertesting.MockGet{
+		func(innerClient client.Client, ctx context.Context, key client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			return controllertesting.Handled, innerClient.Get(ctx, key, obj)
+		},
+	}
+	return append(passThrough, errorGettingChannel()...)
+}
+
+func errorGettingChannel() []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*eventingv1alpha1.Channel); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorGettingConfigMap() []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*corev1.ConfigMap); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorGettingK8sService() []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*corev1.Service); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorGettingVirtualService() []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*istiov1alpha3.VirtualService); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorListingChannels() []controllertesting.MockList {
+	return []controllertesting.MockList{
+		func(client.Client, context.Context, *client.ListOptions, runtime.Object) (controllertesting.MockHandled, error) {
+			return controllertesting.Handled, errors.New(testErrorMessage)
+		},
+	}
+}
+
+func errorCreatingConfigMap() []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*corev1.ConfigMap); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreatingK8sService() []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*corev1.Service); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreatingVirtualService() []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*istiov1alpha3.VirtualService); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdatingChannel() []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*eventingv1alpha1.Channel); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdatingConfigMap() []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*corev1.ConfigMap); ok {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+type paginatedChannelsListStruct struct {
+	channels []eventingv1alpha1.Channel
+}
+
+func (p *paginatedChannelsListStruct) MockLists() []controllertesting.MockList {
+	return []controllertesting.MockList{
+		func(_ client.Client, _ context.Context, _ *client.ListOptions, list runtime.Object) (controllertesting.MockHandled, error) {
+			if l, ok := list.(*eventingv1alpha1.ChannelList); ok {
+
+				if len(p.channels) > 0 {
+					c := p.channels[0]
+					p.channels = p.channels[1:]
+					l.Continue = "yes"
+					l.Items = []eventingv1alpha1.Channel{
+						c,
+					}
+				}
+				return controllertesting.Handled, nil
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func verifyConfigMapData() []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(innerClient client.Client, ctx context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if cm, ok := obj.(*corev1.ConfigMap); ok {
+				s := cm.Data[configmap.MultiChannelFanoutConfigKey]
+				c := multichannelfanout.Config{}
+				err := json.Unmarshal([]byte(s), &c)
+				if err != nil {
+					return controllertesting.Handled,
+						fmt.Errorf("test is unable to unmarshal ConfigMap data: %v", err)
+				}
+				if diff := cmp.Diff(c, channelsConfig); diff != "" {
+					return controllertesting.Handled,
+						fmt.Errorf("test got unwanted ChannelsConfig (-want +got) %s", diff)
+				}
+				// Verified it is correct, now so that we can verify this actually occurred, swap
+				// out the data with a known value for later comparison.
+				cm.Data[configmap.MultiChannelFanoutConfigKey] = insertedByVerifyConfigMapData
+				return controllertesting.Handled, innerClient.Update(ctx, obj)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+type fakeAdmin struct {
+	createErr error
+	deleteErr error
+	created   map[string]sarama.TopicDetail
+	deleted   []string
+}
+
+func (a *fakeAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, _ bool) error {
+	if a.createErr != nil {
+		return a.createErr
+	}
+	if a.created == nil {
+		a.created = make(map[string]sarama.TopicDetail)
+	}
+	a.created[topic] = *detail
+	return nil
+}
+
+func (a *fakeAdmin) DeleteTopic(topic string) error {
+	if a.deleteErr != nil {
+		return a.deleteErr
+	}
+	a.deleted = append(a.deleted, topic)
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/buses"
+	"github.com/knative/eventing/pkg/provisioners/kafka"
+	"github.com/knative/eventing/pkg/provisioners/kafka/channel"
+	"github.com/knative/eventing/pkg/provisioners/kafka/clusterprovisioner"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	"github.com/knative/pkg/signals"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var (
+	defaultNumPartitions     = flag.Int("defaultNumPartitions", int(channel.DefaultNumPartitions), "The number of partitions of the topics of Channels that don't set the numPartitions argument.")
+	defaultReplicationFactor = flag.Int("defaultReplicationFactor", int(channel.DefaultReplicationFactor), "The replication factor of the topics of Channels that don't set the replicationFactor argument.")
+)
+
+func main() {
+	logConfig := buses.NewLoggingConfig()
+	logger := buses.NewBusLoggerFromConfig(logConfig)
+	defer logger.Sync()
+	logger = logger.With(
+		zap.String("eventing.knative.dev/clusterProvisioner", clusterprovisioner.Name),
+		zap.String("eventing.knative.dev/clusterProvisionerComponent", "Controller"),
+	)
+	sarama.Logger = zap.NewStdLog(logger.With(zap.Namespace("Sarama")).Desugar())
+	flag.Parse()
+	channel.DefaultNumPartitions = int32(*defaultNumPartitions)
+	channel.DefaultReplicationFactor = int16(*defaultReplicationFactor)
+
+	bootstrapServers := os.Getenv("KAFKA_BOOTSTRAP_SERVERS")
+	if bootstrapServers == "" {
+		logger.Fatal("Environment variable KAFKA_BOOTSTRAP_SERVERS not set")
+	}
+	admin, err := sarama.NewClusterAdmin(strings.Split(bootstrapServers, ","), kafka.NewConfig("kafka-channel-controller"))
+	if err != nil {
+		logger.Fatal("Unable to create Kafka admin client", zap.Error(err))
+	}
+	defer admin.Close()
+
+	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	if err != nil {
+		logger.Fatal("Error starting up.", zap.Error(err))
+	}
+
+	// Add custom types to this array to get them into the manager's scheme.
+	eventingv1alpha1.AddToScheme(mgr.GetScheme())
+	istiov1alpha3.AddToScheme(mgr.GetScheme())
+
+	// The controllers for both the ClusterProvisioner and the Channels created by that
+	// ClusterProvisioner run in this process.
+	_, err = clusterprovisioner.ProvideController(mgr, logger.Desugar())
+	if err != nil {
+		logger.Fatal("Unable to create Provisioner controller", zap.Error(err))
+	}
+	_, err = channel.ProvideController(mgr, admin, logger.Desugar())
+	if err != nil {
+		logger.Fatal("Unable to create Channel controller", zap.Error(err))
+	}
+
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+	// Start blocks forever.
+	err = mgr.Start(stopCh)
+	if err != nil {
+		logger.Fatal("Manager.Start() returned an error", zap.Error(err))
+	}
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	cluster "github.com/bsm/sarama-cluster"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/buses"
+	"github.com/knative/eventing/pkg/sidecar/fanout"
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"go.uber.org/zap"
+)
+
+const (
+	// dispatcherClientID identifies the dispatcher to the Kafka brokers.
+	dispatcherClientID = "kafka-channel-dispatcher"
+)
+
+// minRedeliveryBackoff and maxRedeliveryBackoff bound the delay before an event that could not be
+// delivered is delivered again, and minConsumerBackoff and maxConsumerBackoff that before a consumer
+// group that could not be created is created again. They are variables for tests.
+var (
+	minRedeliveryBackoff = time.Second
+	maxRedeliveryBackoff = 5 * time.Minute
+	minConsumerBackoff   = time.Second
+	maxConsumerBackoff   = time.Minute
+)
+
+// Consumer is the part of a consumer group member that the Dispatcher uses. *cluster.Consumer
+// implements it.
+type Consumer interface {
+	Messages() <-chan *sarama.ConsumerMessage
+	MarkOffset(msg *sarama.ConsumerMessage, metadata string)
+	Close() error
+}
+
+// newConsumerFunc creates a member of the consumer group that reads topic.
+type newConsumerFunc func(group, topic string) (Consumer, error)
+
+// Dispatcher receives the events sent to Kafka Channels over HTTP and writes them to the Channels'
+// topics. It reads each topic with a consumer group per Subscription, delivering the events to the
+// Subscription's subscribers in the order they were written.
+type Dispatcher struct {
+	// SkipUndeliverable makes the Dispatcher commit the offset of an event that still can't be
+	// delivered to a subscriber once its delivery policy is exhausted, losing the event unless the
+	// subscriber has a dead-letter sink. By default, the event is delivered again, with a growing
+	// backoff, until it succeeds, holding up the following events of the partition.
+	SkipUndeliverable bool
+
+	producer    sarama.SyncProducer
+	newConsumer newConsumerFunc
+	receiver    *buses.MessageReceiver
+	dispatcher  *buses.MessageDispatcher
+
+	mu sync.Mutex
+	// channels are the Channels the Dispatcher accepts events for.
+	channels map[buses.ChannelReference]bool
+	// consumers are the consumer groups reading the Channels' topics, by name.
+	consumers map[string]*subscriptionConsumer
+
+	logger *zap.Logger
+}
+
+// subscriptionConsumer delivers the events of a Channel's topic to the subscribers sharing a
+// consumer group.
+type subscriptionConsumer struct {
+	group   string
+	channel buses.ChannelReference
+	// stopCh is closed once the consumer group is stopped.
+	stopCh chan struct{}
+
+	mu          sync.RWMutex
+	subscribers []eventingv1alpha1.ChannelSubscriberSpec
+	// consumer is nil until the consumer group has been created.
+	consumer Consumer
+	stopped  bool
+}
+
+func (sc *subscriptionConsumer) getSubscribers() []eventingv1alpha1.ChannelSubscriberSpec {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.subscribers
+}
+
+func (sc *subscriptionConsumer) setSubscribers(subscribers []eventingv1alpha1.ChannelSubscriberSpec) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.subscribers = subscribers
+}
+
+// setConsumer records the consumer once created. It returns false if the consumer group was stopped
+// in the meantime, in which case the caller must close consumer.
+func (sc *subscriptionConsumer) setConsumer(consumer Consumer) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.stopped {
+		return false
+	}
+	sc.consumer = consumer
+	return true
+}
+
+// stop stops the consumer group, closing its consumer if it was created. It may block until the
+// consumer left the group, so it must not be called with Dispatcher.mu held.
+func (sc *subscriptionConsumer) stop() error {
+	sc.mu.Lock()
+	sc.stopped = true
+	close(sc.stopCh)
+	consumer := sc.consumer
+	sc.mu.Unlock()
+	if consumer == nil {
+		return nil
+	}
+	return consumer.Close()
+}
+
+// NewDispatcher creates a Dispatcher for the Kafka cluster reachable at brokers. It accepts no
+// events until its configuration is set with UpdateConfig.
+func NewDispatcher(brokers []string, logger *zap.Logger) (*Dispatcher, error) {
+	conf := NewConfig(dispatcherClientID)
+	// Events are only acknowledged once every in-sync replica has written them.
+	conf.Producer.RequiredAcks = sarama.WaitForAll
+	conf.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer(brokers, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	newConsumer := func(group, topic string) (Consumer, error) {
+		consumerConfig := cluster.NewConfig()
+		consumerConfig.Version = conf.Version
+		consumerConfig.ClientID = dispatcherClientID
+		c, err := cluster.NewConsumer(brokers, group, []string{topic}, consumerConfig)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return newDispatcher(producer, newConsumer, logger), nil
+}
+
+func newDispatcher(producer sarama.SyncProducer, newConsumer newConsumerFunc, logger *zap.Logger) *Dispatcher {
+	d := &Dispatcher{
+		producer:    producer,
+		newConsumer: newConsumer,
+		dispatcher:  buses.NewMessageDispatcher(logger.Sugar()),
+		channels:    make(map[buses.ChannelReference]bool),
+		consumers:   make(map[string]*subscriptionConsumer),
+		logger:      logger,
+	}
+	d.receiver = buses.NewMessageReceiver(d.receive, logger.Sugar())
+	return d
+}
+
+// Start receives events until stopCh is closed, then stops all consumer groups. It implements
+// manager.Runnable.
+func (d *Dispatcher) Start(stopCh <-chan struct{}) error {
+	d.receiver.Run(stopCh)
+
+	d.mu.Lock()
+	consumers := d.consumers
+	d.consumers = make(map[string]*subscriptionConsumer)
+	d.mu.Unlock()
+	for _, sc := range consumers {
+		d.closeConsumer(sc)
+	}
+	return d.producer.Close()
+}
+
+// UpdateConfig sets the Channels the Dispatcher accepts events for and their subscribers. It
+// starts a consumer group for every new Subscription, and stops those of Subscriptions that are
+// gone. Consumer groups are created in the background, and created again with a growing backoff
+// until they succeed. It implements swappable.UpdateConfig.
+func (d *Dispatcher) UpdateConfig(config *multichannelfanout.Config) error {
+	channels := make(map[buses.ChannelReference]bool, len(config.ChannelConfigs))
+	groups := make(map[string]*subscriptionConsumer)
+	for _, cc := range config.ChannelConfigs {
+		channel := buses.ChannelReference{Namespace: cc.Namespace, Name: cc.Name}
+		channels[channel] = true
+		for _, sub := range cc.FanoutConfig.Subscriptions {
+			group := consumerGroupName(channel, sub)
+			if _, ok := groups[group]; !ok {
+				groups[group] = &subscriptionConsumer{group: group, channel: channel, stopCh: make(chan struct{})}
+			}
+			groups[group].subscribers = append(groups[group].subscribers, sub)
+		}
+	}
+
+	var removed []*subscriptionConsumer
+	d.mu.Lock()
+	d.channels = channels
+	for group, sc := range d.consumers {
+		if _, ok := groups[group]; !ok {
+			delete(d.consumers, group)
+			removed = append(removed, sc)
+		}
+	}
+	for group, want := range groups {
+		if sc, ok := d.consumers[group]; ok {
+			sc.setSubscribers(want.subscribers)
+			continue
+		}
+		d.consumers[group] = want
+		go d.run(want)
+	}
+	d.mu.Unlock()
+
+	// Leaving a consumer group can be slow, so it is done without holding d.mu, which receive
+	// needs.
+	for _, sc := range removed {
+		d.closeConsumer(sc)
+	}
+	return nil
+}
+
+// closeConsumer stops the consumer group.
+func (d *Dispatcher) closeConsumer(sc *subscriptionConsumer) {
+	if err := sc.stop(); err != nil {
+		d.logger.Warn("Error closing consumer", zap.String("group", sc.group), zap.Error(err))
+	}
+}
+
+// run creates the consumer group, creating it again with a growing backoff until it succeeds, and
+// then consumes it, until the consumer group is stopped.
+func (d *Dispatcher) run(sc *subscriptionConsumer) {
+	topic := TopicName(sc.channel.Namespace, sc.channel.Name)
+	backoff := minConsumerBackoff
+	for {
+		consumer, err := d.newConsumer(sc.group, topic)
+		if err == nil {
+			if !sc.setConsumer(consumer) {
+				consumer.Close()
+				return
+			}
+			d.logger.Info("Started consumer", zap.String("group", sc.group), zap.String("topic", topic))
+			d.consume(sc.group, sc)
+			return
+		}
+		d.logger.Error("Unable to create consumer", zap.String("group", sc.group), zap.String("topic", topic), zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-time.After(backoff):
+		case <-sc.stopCh:
+			return
+		}
+		if backoff *= 2; backoff > maxConsumerBackoff {
+			backoff = maxConsumerBackoff
+		}
+	}
+}
+
+// receive writes the event to the Channel's topic. It only returns once the write is durable, so
+// that the sender can retry events that failed.
+func (d *Dispatcher) receive(channel buses.ChannelReference, message *buses.Message) error {
+	d.mu.Lock()
+	known := d.channels[channel]
+	d.mu.Unlock()
+	if !known {
+		return buses.ErrUnknownChannel
+	}
+
+	if _, _, err := d.producer.SendMessage(toKafkaMessage(channel, message)); err != nil {
+		d.logger.Warn("Unable to write event to Kafka", zap.String("channel", channel.String()), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// consume delivers every event read by the consumer group to its subscribers, one event at a time,
+// until the consumer is closed. An event's offset is only committed once it has been delivered or
+// dead-lettered, as each subscriber's delivery policy specifies.
+func (d *Dispatcher) consume(group string, sc *subscriptionConsumer) {
+	for msg := range sc.consumer.Messages() {
+		if !d.deliver(group, sc, msg) {
+			// The event's offset isn't committed, so the group reads it again once restarted.
+			break
+		}
+		sc.consumer.MarkOffset(msg, "")
+	}
+	d.logger.Info("Consumer stopped", zap.String("group", group))
+}
+
+// deliver delivers the event to the consumer group's subscribers, delivering it again to those that
+// failed until it succeeds, unless d.SkipUndeliverable is set. It returns false if the consumer
+// group was stopped first.
+func (d *Dispatcher) deliver(group string, sc *subscriptionConsumer, msg *sarama.ConsumerMessage) bool {
+	message := fromKafkaMessage(msg)
+	pending := sc.getSubscribers()
+	backoff := minRedeliveryBackoff
+	for {
+		var failed []eventingv1alpha1.ChannelSubscriberSpec
+		for _, sub := range pending {
+			err := d.dispatcher.DispatchMessageWithRetries(message, sub.CallableDomain, sub.SinkableDomain, sub.DeadLetterSinkDomain,
+				fanout.RetryConfig(sub.Delivery), buses.DispatchDefaults{Namespace: sc.channel.Namespace})
+			if err != nil {
+				d.logger.Warn("Unable to deliver event", zap.String("group", group), zap.Int64("offset", msg.Offset), zap.Error(err))
+				failed = append(failed, sub)
+			}
+		}
+		if len(failed) == 0 {
+			return true
+		}
+		if d.SkipUndeliverable {
+			d.logger.Error("Skipping undeliverable event", zap.String("group", group), zap.Int64("offset", msg.Offset))
+			return true
+		}
+		d.logger.Info("Delivering event again", zap.String("group", group), zap.Int64("offset", msg.Offset), zap.Duration("backoff", backoff))
+		select {
+		case <-time.After(backoff):
+		case <-sc.stopCh:
+			return false
+		}
+		if backoff *= 2; backoff > maxRedeliveryBackoff {
+			backoff = maxRedeliveryBackoff
+		}
+		pending = failed
+	}
+}
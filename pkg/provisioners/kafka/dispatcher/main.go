@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/knative/eventing/pkg/buses"
+	"github.com/knative/eventing/pkg/provisioners/kafka"
+	"github.com/knative/eventing/pkg/provisioners/kafka/channel"
+	"github.com/knative/eventing/pkg/provisioners/kafka/clusterprovisioner"
+	"github.com/knative/eventing/pkg/sidecar/configmap/watcher"
+	"github.com/knative/eventing/pkg/system"
+	"github.com/knative/pkg/signals"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func init() {
+	flag.DurationVar(&buses.DefaultDialTimeout, "dispatchDialTimeout", 0, "The maximum time to establish a connection to a subscriber. Zero means no limit.")
+	flag.DurationVar(&buses.DefaultRequestTimeout, "dispatchRequestTimeout", 0, "The maximum time of each request to a subscriber. Zero means no limit.")
+}
+
+var (
+	ackPort        = flag.Int("ackPort", 8081, "The port to receive asynchronous delivery acknowledgments on, when ackCallbackURL is set.")
+	ackCallbackURL = flag.String("ackCallbackURL", "", "The URL at which subscribers reach ackPort of this dispatcher to acknowledge deliveries asynchronously, e.g. 'http://$(POD_IP):8081'. Empty disables asynchronous acknowledgment.")
+	ackTimeout     = flag.Duration("ackTimeout", time.Minute, "The maximum time to wait for a subscriber to acknowledge a delivery asynchronously.")
+)
+
+var skipUndeliverable = flag.Bool("skipUndeliverable", false, "If true, events that can't be delivered to a subscriber once its delivery policy is exhausted are skipped, rather than delivered again until they succeed.")
+
+func main() {
+	logConfig := buses.NewLoggingConfig()
+	logger := buses.NewBusLoggerFromConfig(logConfig).Desugar()
+	defer logger.Sync()
+	logger = logger.With(
+		zap.String("eventing.knative.dev/clusterProvisioner", clusterprovisioner.Name),
+		zap.String("eventing.knative.dev/clusterProvisionerComponent", "Dispatcher"),
+	)
+	sarama.Logger = zap.NewStdLog(logger.With(zap.Namespace("Sarama")))
+	flag.Parse()
+
+	bootstrapServers := os.Getenv("KAFKA_BOOTSTRAP_SERVERS")
+	if bootstrapServers == "" {
+		logger.Fatal("Environment variable KAFKA_BOOTSTRAP_SERVERS not set")
+	}
+	if *ackCallbackURL != "" {
+		// Set before creating the dispatcher, whose message dispatcher picks it up.
+		buses.DefaultAckTracker = buses.NewAckTracker(*ackCallbackURL, *ackTimeout, logger.Sugar())
+	}
+	d, err := kafka.NewDispatcher(strings.Split(bootstrapServers, ","), logger)
+	if err != nil {
+		logger.Fatal("Unable to create Kafka dispatcher", zap.Error(err))
+	}
+	d.SkipUndeliverable = *skipUndeliverable
+
+	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	if err != nil {
+		logger.Fatal("Error starting up.", zap.Error(err))
+	}
+	kc, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		logger.Fatal("Unable to create K8s client", zap.Error(err))
+	}
+
+	// The Channel controller writes the Channels and their subscribers to the ConfigMap, and the
+	// dispatcher starts and stops consumer groups as it changes.
+	cmw, err := watcher.NewWatcher(logger, kc, system.Namespace, channel.ConfigMapName, d.UpdateConfig)
+	if err != nil {
+		logger.Fatal("Unable to create ConfigMap watcher", zap.Error(err))
+	}
+	mgr.Add(cmw)
+	mgr.Add(d)
+	if buses.DefaultAckTracker != nil {
+		mgr.Add(manager.RunnableFunc(func(stopCh <-chan struct{}) error {
+			return serveAcks(logger, stopCh)
+		}))
+	}
+
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+	// Start blocks forever.
+	err = mgr.Start(stopCh)
+	if err != nil {
+		logger.Fatal("Manager.Start() returned an error", zap.Error(err))
+	}
+}
+
+// serveAcks serves buses.DefaultAckTracker on ackPort until stopCh is closed.
+func serveAcks(logger *zap.Logger, stopCh <-chan struct{}) error {
+	s := &http.Server{
+		Addr:     fmt.Sprintf(":%d", *ackPort),
+		Handler:  buses.DefaultAckTracker,
+		ErrorLog: zap.NewStdLog(logger),
+	}
+	go func() {
+		<-stopCh
+		s.Shutdown(context.Background())
+	}()
+	logger.Info("Receiving acknowledgments", zap.String("Address", s.Addr))
+	if err := s.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,503 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/buses"
+	"github.com/knative/eventing/pkg/sidecar/fanout"
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	testNS = "test-namespace"
+)
+
+func TestConsumerGroupName(t *testing.T) {
+	channel := buses.ChannelReference{Namespace: testNS, Name: "c"}
+	testCases := map[string]struct {
+		a, b eventingv1alpha1.ChannelSubscriberSpec
+		same bool
+	}{
+		"same subscription": {
+			a:    eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s"), CallableDomain: "a"},
+			b:    eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s"), CallableDomain: "b"},
+			same: true,
+		},
+		"different subscriptions": {
+			a: eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s1"), CallableDomain: "a"},
+			b: eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s2"), CallableDomain: "a"},
+		},
+		"no subscription, same domains": {
+			a:    eventingv1alpha1.ChannelSubscriberSpec{CallableDomain: "a", SinkableDomain: "b"},
+			b:    eventingv1alpha1.ChannelSubscriberSpec{CallableDomain: "a", SinkableDomain: "b"},
+			same: true,
+		},
+		"no subscription, different domains": {
+			a: eventingv1alpha1.ChannelSubscriberSpec{CallableDomain: "a", SinkableDomain: "b"},
+			b: eventingv1alpha1.ChannelSubscriberSpec{CallableDomain: "b", SinkableDomain: "a"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			a, b := consumerGroupName(channel, tc.a), consumerGroupName(channel, tc.b)
+			if tc.same != (a == b) {
+				t.Errorf("Unexpected consumer group names. Expected same: %v. Actual: %q, %q", tc.same, a, b)
+			}
+		})
+	}
+}
+
+func TestDispatcher_Receive(t *testing.T) {
+	testCases := map[string]struct {
+		channel     buses.ChannelReference
+		producerErr error
+		expectedErr bool
+		expected    []string
+	}{
+		"unknown channel": {
+			channel:     buses.ChannelReference{Namespace: testNS, Name: "other"},
+			expectedErr: true,
+		},
+		"producer fails": {
+			channel:     buses.ChannelReference{Namespace: testNS, Name: "c"},
+			producerErr: errors.New("test-induced-error"),
+			expectedErr: true,
+		},
+		"written to the topic": {
+			channel:  buses.ChannelReference{Namespace: testNS, Name: "c"},
+			expected: []string{"knative-eventing-channel.test-namespace.c ce-eventid=1 event"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			p := &fakeProducer{err: tc.producerErr}
+			d := newDispatcher(p, newFakeConsumers().newConsumer, zap.NewNop())
+			if err := d.UpdateConfig(channelConfig("c")); err != nil {
+				t.Fatalf("Unexpected error updating the config: %v", err)
+			}
+
+			err := d.receive(tc.channel, &buses.Message{
+				Headers: map[string]string{"ce-eventid": "1"},
+				Payload: []byte("event"),
+			})
+			if tc.expectedErr != (err != nil) {
+				t.Errorf("Unexpected error. Expected %v. Actual: %v", tc.expectedErr, err)
+			}
+			if diff := cmp.Diff(tc.expected, p.sent); diff != "" {
+				t.Errorf("Unexpected messages (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDispatcher_UpdateConfig(t *testing.T) {
+	defer func(backoff time.Duration) { minConsumerBackoff = backoff }(minConsumerBackoff)
+	minConsumerBackoff = time.Millisecond
+
+	fc := newFakeConsumers()
+	d := newDispatcher(&fakeProducer{}, fc.newConsumer, zap.NewNop())
+
+	config := channelConfig("c",
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s1"), CallableDomain: "a"},
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s1"), CallableDomain: "b"},
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s2"), CallableDomain: "a"},
+	)
+	if err := d.UpdateConfig(config); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+	fc.await(t, "kafka.test-namespace.c.s1")
+	fc.await(t, "kafka.test-namespace.c.s2")
+	want := []string{
+		"kafka.test-namespace.c.s1 knative-eventing-channel.test-namespace.c",
+		"kafka.test-namespace.c.s2 knative-eventing-channel.test-namespace.c",
+	}
+	if diff := cmp.Diff(want, fc.created()); diff != "" {
+		t.Errorf("Unexpected consumers (-want +got): %s", diff)
+	}
+
+	// Removing a Subscription stops its consumer group, and keeps the others.
+	config = channelConfig("c",
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s1"), CallableDomain: "a"},
+	)
+	if err := d.UpdateConfig(config); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+	if diff := cmp.Diff(want, fc.created()); diff != "" {
+		t.Errorf("Unexpected consumers (-want +got): %s", diff)
+	}
+	if !fc.get("kafka.test-namespace.c.s2").isClosed() {
+		t.Errorf("Expected the consumer of the removed Subscription to be closed")
+	}
+	if fc.get("kafka.test-namespace.c.s1").isClosed() {
+		t.Errorf("Expected the consumer of the remaining Subscription not to be closed")
+	}
+	if diff := cmp.Diff(config.ChannelConfigs[0].FanoutConfig.Subscriptions, d.consumers["kafka.test-namespace.c.s1"].getSubscribers()); diff != "" {
+		t.Errorf("Unexpected subscribers (-want +got): %s", diff)
+	}
+
+	// A consumer that can't be created is created again until it succeeds.
+	fc.setErr(errors.New("test-induced-error"))
+	config = channelConfig("c",
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s3"), CallableDomain: "a"},
+	)
+	if err := d.UpdateConfig(config); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+	for i := 0; i < 100 && fc.attempts("kafka.test-namespace.c.s3") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if fc.get("kafka.test-namespace.c.s3") != nil {
+		t.Errorf("Unexpected consumer for s3")
+	}
+	fc.setErr(nil)
+	fc.await(t, "kafka.test-namespace.c.s3")
+	if !fc.get("kafka.test-namespace.c.s1").isClosed() {
+		t.Errorf("Expected the consumer of the removed Subscription to be closed")
+	}
+}
+
+func TestDispatcher_UpdateConfigStopsRetries(t *testing.T) {
+	defer func(backoff time.Duration) { minConsumerBackoff = backoff }(minConsumerBackoff)
+	minConsumerBackoff = time.Millisecond
+
+	fc := newFakeConsumers()
+	fc.setErr(errors.New("test-induced-error"))
+	d := newDispatcher(&fakeProducer{}, fc.newConsumer, zap.NewNop())
+	if err := d.UpdateConfig(channelConfig("c",
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s"), CallableDomain: "a"},
+	)); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+	for i := 0; i < 100 && fc.attempts("kafka.test-namespace.c.s") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Removing the Subscription stops creating its consumer group.
+	if err := d.UpdateConfig(&multichannelfanout.Config{}); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+	fc.setErr(nil)
+	time.Sleep(50 * time.Millisecond)
+	if fc.get("kafka.test-namespace.c.s") != nil {
+		t.Errorf("Unexpected consumer for the removed Subscription")
+	}
+}
+
+func TestDispatcher_Consume(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriber.Close()
+
+	fc := newFakeConsumers()
+	d := newDispatcher(&fakeProducer{}, fc.newConsumer, zap.NewNop())
+	config := channelConfig("c",
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s"), CallableDomain: subscriber.URL},
+	)
+	if err := d.UpdateConfig(config); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+
+	c := fc.await(t, "kafka.test-namespace.c.s")
+	for i, e := range []string{"first", "second"} {
+		c.messages <- &sarama.ConsumerMessage{Offset: int64(i), Value: []byte(e)}
+	}
+	c.Close()
+
+	// Wait for the consumer to deliver both events.
+	for i := 0; i < 100 && len(c.markedOffsets()) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if diff := cmp.Diff([]int64{0, 1}, c.markedOffsets()); diff != "" {
+		t.Errorf("Unexpected marked offsets (-want +got): %s", diff)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff([]string{"first", "second"}, received); diff != "" {
+		t.Errorf("Unexpected events (-want +got): %s", diff)
+	}
+}
+
+func TestDispatcher_ConsumeUndeliverable(t *testing.T) {
+	defer func(backoff time.Duration) { minRedeliveryBackoff = backoff }(minRedeliveryBackoff)
+	minRedeliveryBackoff = time.Millisecond
+
+	testCases := map[string]struct {
+		skip             bool
+		expectedOffsets  []int64
+		expectedReceived []string
+	}{
+		"delivered again": {
+			expectedOffsets:  []int64{0, 1},
+			expectedReceived: []string{"first", "first", "first", "second"},
+		},
+		"skipped": {
+			skip:             true,
+			expectedOffsets:  []int64{0, 1},
+			expectedReceived: []string{"first", "second"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var mu sync.Mutex
+			var received []string
+			subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				mu.Lock()
+				defer mu.Unlock()
+				received = append(received, string(body))
+				// The first event fails twice.
+				if string(body) == "first" && len(received) <= 2 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer subscriber.Close()
+
+			fc := newFakeConsumers()
+			d := newDispatcher(&fakeProducer{}, fc.newConsumer, zap.NewNop())
+			d.SkipUndeliverable = tc.skip
+			config := channelConfig("c",
+				eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s"), CallableDomain: subscriber.URL},
+			)
+			if err := d.UpdateConfig(config); err != nil {
+				t.Fatalf("Unexpected error updating the config: %v", err)
+			}
+
+			c := fc.await(t, "kafka.test-namespace.c.s")
+			for i, e := range []string{"first", "second"} {
+				c.messages <- &sarama.ConsumerMessage{Offset: int64(i), Value: []byte(e)}
+			}
+
+			for i := 0; i < 100 && len(c.markedOffsets()) < 2; i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			c.Close()
+			if diff := cmp.Diff(tc.expectedOffsets, c.markedOffsets()); diff != "" {
+				t.Errorf("Unexpected marked offsets (-want +got): %s", diff)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if diff := cmp.Diff(tc.expectedReceived, received); diff != "" {
+				t.Errorf("Unexpected events (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDispatcher_ConsumeStoppedWhileUndeliverable(t *testing.T) {
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer subscriber.Close()
+
+	fc := newFakeConsumers()
+	d := newDispatcher(&fakeProducer{}, fc.newConsumer, zap.NewNop())
+	if err := d.UpdateConfig(channelConfig("c",
+		eventingv1alpha1.ChannelSubscriberSpec{Ref: subRef("s"), CallableDomain: subscriber.URL},
+	)); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+	c := fc.await(t, "kafka.test-namespace.c.s")
+	c.messages <- &sarama.ConsumerMessage{Offset: 0, Value: []byte("event")}
+	time.Sleep(50 * time.Millisecond)
+
+	// Removing the Subscription stops the consumer group without committing the event.
+	if err := d.UpdateConfig(channelConfig("c")); err != nil {
+		t.Fatalf("Unexpected error updating the config: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if offsets := c.markedOffsets(); len(offsets) != 0 {
+		t.Errorf("Expected no offset to be marked, got %v", offsets)
+	}
+}
+
+func subRef(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+		Namespace:  testNS,
+		Name:       name,
+	}
+}
+
+func channelConfig(name string, subs ...eventingv1alpha1.ChannelSubscriberSpec) *multichannelfanout.Config {
+	return &multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{{
+			Namespace: testNS,
+			Name:      name,
+			FanoutConfig: fanout.Config{
+				Subscriptions: subs,
+			},
+		}},
+	}
+}
+
+type fakeProducer struct {
+	sarama.SyncProducer
+	err error
+	// sent are the messages sent, as "<topic> <header>=<value>... <payload>".
+	sent []string
+}
+
+func (p *fakeProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	if p.err != nil {
+		return 0, 0, p.err
+	}
+	sent := msg.Topic
+	for _, h := range msg.Headers {
+		sent += fmt.Sprintf(" %s=%s", h.Key, h.Value)
+	}
+	value, _ := msg.Value.Encode()
+	p.sent = append(p.sent, sent+" "+string(value))
+	return 0, int64(len(p.sent) - 1), nil
+}
+
+type fakeConsumers struct {
+	mu        sync.Mutex
+	err       error
+	consumers map[string]*fakeConsumer
+	topics    map[string]string
+	tries     map[string]int
+}
+
+func newFakeConsumers() *fakeConsumers {
+	return &fakeConsumers{
+		consumers: make(map[string]*fakeConsumer),
+		topics:    make(map[string]string),
+		tries:     make(map[string]int),
+	}
+}
+
+func (fc *fakeConsumers) newConsumer(group, topic string) (Consumer, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.tries[group]++
+	if fc.err != nil {
+		return nil, fc.err
+	}
+	c := &fakeConsumer{messages: make(chan *sarama.ConsumerMessage, 10)}
+	fc.consumers[group] = c
+	fc.topics[group] = topic
+	return c, nil
+}
+
+func (fc *fakeConsumers) setErr(err error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.err = err
+}
+
+func (fc *fakeConsumers) get(group string) *fakeConsumer {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.consumers[group]
+}
+
+// attempts returns the number of times the group's consumer was created, successfully or not.
+func (fc *fakeConsumers) attempts(group string) int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.tries[group]
+}
+
+// await waits for the group's consumer to be created, which the Dispatcher does in the background.
+func (fc *fakeConsumers) await(t *testing.T, group string) *fakeConsumer {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if c := fc.get(group); c != nil {
+			return c
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Consumer %q wasn't created", group)
+	return nil
+}
+
+// created returns the consumers created, as "<group> <topic>".
+func (fc *fakeConsumers) created() []string {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	var created []string
+	for group, topic := range fc.topics {
+		created = append(created, group+" "+topic)
+	}
+	sort.Strings(created)
+	return created
+}
+
+type fakeConsumer struct {
+	messages chan *sarama.ConsumerMessage
+
+	mu     sync.Mutex
+	marked []int64
+	closed bool
+}
+
+func (c *fakeConsumer) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func (c *fakeConsumer) MarkOffset(msg *sarama.ConsumerMessage, _ string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.marked = append(c.marked, msg.Offset)
+}
+
+func (c *fakeConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.messages)
+	}
+	return nil
+}
+
+func (c *fakeConsumer) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *fakeConsumer) markedOffsets() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64(nil), c.marked...)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kafka contains the parts of the Kafka ClusterChannelProvisioner shared by its controller
+// and its dispatcher. Each Channel is backed by a Kafka topic, and each Subscription to the Channel
+// by a consumer group reading that topic.
+package kafka
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/Shopify/sarama"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/buses"
+)
+
+const (
+	// topicPrefix is prepended to the names of the topics backing Channels, so that they don't
+	// collide with other topics in the Kafka cluster.
+	topicPrefix = "knative-eventing-channel"
+
+	// consumerGroupPrefix is prepended to the names of the consumer groups backing Subscriptions.
+	consumerGroupPrefix = "kafka"
+)
+
+// NewConfig returns the configuration of the Kafka clients of the provisioner. clientID identifies
+// the component to the Kafka brokers.
+func NewConfig(clientID string) *sarama.Config {
+	conf := sarama.NewConfig()
+	conf.Version = sarama.V1_1_0_0
+	conf.ClientID = clientID
+	return conf
+}
+
+// TopicName returns the name of the Kafka topic backing the Channel.
+func TopicName(namespace, name string) string {
+	return fmt.Sprintf("%s.%s.%s", topicPrefix, namespace, name)
+}
+
+// consumerGroupName returns the name of the consumer group delivering the Channel's events to sub.
+// Subscribers created from the same Subscription share a consumer group. Subscribers without a
+// Subscription get a consumer group of their own, named after where they deliver events.
+func consumerGroupName(channel buses.ChannelReference, sub eventingv1alpha1.ChannelSubscriberSpec) string {
+	if sub.Ref != nil && sub.Ref.Name != "" {
+		return fmt.Sprintf("%s.%s.%s.%s", consumerGroupPrefix, channel.Namespace, channel.Name, sub.Ref.Name)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(sub.CallableDomain + "|" + sub.SinkableDomain))
+	return fmt.Sprintf("%s.%s.%s.%x", consumerGroupPrefix, channel.Namespace, channel.Name, h.Sum64())
+}
+
+// toKafkaMessage converts a message received by the Channel into a Kafka message for its topic.
+func toKafkaMessage(channel buses.ChannelReference, message *buses.Message) *sarama.ProducerMessage {
+	kafkaMessage := &sarama.ProducerMessage{
+		Topic: TopicName(channel.Namespace, channel.Name),
+		Value: sarama.ByteEncoder(message.Payload),
+	}
+	for h, v := range message.Headers {
+		kafkaMessage.Headers = append(kafkaMessage.Headers, sarama.RecordHeader{
+			Key:   []byte(h),
+			Value: []byte(v),
+		})
+	}
+	return kafkaMessage
+}
+
+// fromKafkaMessage converts a Kafka message read from a Channel's topic back into a message.
+func fromKafkaMessage(kafkaMessage *sarama.ConsumerMessage) *buses.Message {
+	headers := make(map[string]string, len(kafkaMessage.Headers))
+	for _, header := range kafkaMessage.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	return &buses.Message{
+		Headers: headers,
+		Payload: kafkaMessage.Value,
+	}
+}
@@ -20,10 +20,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/sidecar/configmap"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
@@ -103,7 +103,7 @@ func TestReadConfigMap(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: "event-changer.default.svc.cluster.local",
 									SinkableDomain: "message-dumper-bar.default.svc.cluster.local",
@@ -121,7 +121,7 @@ func TestReadConfigMap(t *testing.T) {
 						Namespace: "default",
 						Name:      "c2",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "message-dumper-foo.default.svc.cluster.local",
 								},
@@ -132,7 +132,7 @@ func TestReadConfigMap(t *testing.T) {
 						Namespace: "other",
 						Name:      "c3",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "message-dumper-foo.default.svc.cluster.local",
 								},
@@ -185,7 +185,7 @@ func TestWatch(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "foo.bar",
 								},
@@ -202,7 +202,7 @@ func TestWatch(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "foo.bar",
 								},
@@ -220,7 +220,7 @@ func TestWatch(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "foo.bar",
 								},
@@ -235,7 +235,7 @@ func TestWatch(t *testing.T) {
 						Namespace: "default",
 						Name:      "new-channel",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: "baz.qux",
 								},
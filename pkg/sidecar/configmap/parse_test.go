@@ -18,9 +18,9 @@ package configmap
 
 import (
 	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"go.uber.org/zap"
 	"strings"
 	"testing"
@@ -85,7 +85,7 @@ func TestNewFanoutConfig(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: "event-changer.default.svc.cluster.local",
 									SinkableDomain: "message-dumper-bar.default.svc.cluster.local",
@@ -103,7 +103,7 @@ func TestNewFanoutConfig(t *testing.T) {
 						Namespace: "default",
 						Name:      "c2",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "message-dumper-foo.default.svc.cluster.local",
 								},
@@ -114,7 +114,7 @@ func TestNewFanoutConfig(t *testing.T) {
 						Namespace: "other",
 						Name:      "c3",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "message-dumper-foo.default.svc.cluster.local",
 								},
@@ -156,7 +156,7 @@ func TestSerializeConfig(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: "foo.example.com",
 									SinkableDomain: "bar.example.com",
@@ -175,7 +175,7 @@ func TestSerializeConfig(t *testing.T) {
 						Namespace: "other",
 						Name:      "no-subs",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{},
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{},
 						},
 					},
 				},
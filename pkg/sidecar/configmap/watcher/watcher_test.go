@@ -19,10 +19,10 @@ package watcher
 import (
 	"errors"
 	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	sidecarconfigmap "github.com/knative/eventing/pkg/sidecar/configmap"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/configmap"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -75,7 +75,7 @@ func TestReconcile(t *testing.T) {
 						Name:      "foo",
 						Namespace: "bar",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: "callable",
 									SinkableDomain: "sinkable",
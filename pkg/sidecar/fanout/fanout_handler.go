@@ -23,8 +23,8 @@ package fanout
 
 import (
 	"errors"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/buses"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"go.uber.org/zap"
 	"net/http"
 	"time"
@@ -38,7 +38,7 @@ const (
 
 // Configuration for a fanout.Handler.
 type Config struct {
-	Subscriptions []duckv1alpha1.ChannelSubscriberSpec `json:"subscriptions"`
+	Subscriptions []eventingv1alpha1.ChannelSubscriberSpec `json:"subscriptions"`
 }
 
 // http.Handler that takes a single request in and fans it out to N other servers.
@@ -95,7 +95,7 @@ func (f *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (f *Handler) dispatch(msg *buses.Message) error {
 	errorCh := make(chan error, len(f.config.Subscriptions))
 	for _, sub := range f.config.Subscriptions {
-		go func(s duckv1alpha1.ChannelSubscriberSpec) {
+		go func(s eventingv1alpha1.ChannelSubscriberSpec) {
 			errorCh <- f.makeFanoutRequest(*msg, s)
 		}(sub)
 	}
@@ -117,7 +117,30 @@ func (f *Handler) dispatch(msg *buses.Message) error {
 }
 
 // makeFanoutRequest sends the request to exactly one subscription. It handles both the `call` and
-// the `sink` portions of the subscription.
-func (f *Handler) makeFanoutRequest(m buses.Message, sub duckv1alpha1.ChannelSubscriberSpec) error {
-	return f.dispatcher.DispatchMessage(&m, sub.CallableDomain, sub.SinkableDomain, buses.DispatchDefaults{})
+// the `sink` portions of the subscription, retries them according to the subscription's delivery
+// policy, and sends the request to the subscription's dead-letter sink if either still fails. It
+// gives up once f.timeout elapses, when dispatch has already reported the fanout as failed, so that
+// a sender retrying the message doesn't cause duplicate deliveries.
+func (f *Handler) makeFanoutRequest(m buses.Message, sub eventingv1alpha1.ChannelSubscriberSpec) error {
+	retry := RetryConfig(sub.Delivery)
+	retry.Timeout = f.timeout
+	return f.dispatcher.DispatchMessageWithRetries(&m, sub.CallableDomain, sub.SinkableDomain, sub.DeadLetterSinkDomain, retry, buses.DispatchDefaults{})
+}
+
+// RetryConfig converts a subscription's delivery policy into the dispatcher's retry configuration.
+// Without a policy, requests are attempted only once.
+func RetryConfig(delivery *eventingv1alpha1.DeliverySpec) buses.RetryConfig {
+	if delivery == nil {
+		return buses.RetryConfig{}
+	}
+	c := buses.RetryConfig{
+		BackoffPolicy: buses.BackoffPolicy(delivery.BackoffPolicy),
+	}
+	if delivery.Retry != nil {
+		c.Retry = int(*delivery.Retry)
+	}
+	if delivery.BackoffDelay != nil {
+		c.BackoffDelay = delivery.BackoffDelay.Duration
+	}
+	return c
 }
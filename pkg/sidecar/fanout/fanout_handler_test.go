@@ -18,12 +18,13 @@ package fanout
 
 import (
 	"errors"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/buses"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"io"
 	"io/ioutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -36,6 +37,8 @@ import (
 const (
 	replaceCallable = "replaceCallable"
 	replaceSinkable = "replaceSinkable"
+
+	replaceDeadLetterSink = "replaceDeadLetterSink"
 )
 
 var (
@@ -59,9 +62,10 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 	testCases := map[string]struct {
 		receiverFunc   func(buses.ChannelReference, *buses.Message) error
 		timeout        time.Duration
-		subs           []duckv1alpha1.ChannelSubscriberSpec
+		subs           []eventingv1alpha1.ChannelSubscriberSpec
 		callable       func(http.ResponseWriter, *http.Request)
 		sinkable       func(http.ResponseWriter, *http.Request)
+		deadLetterSink func(http.ResponseWriter, *http.Request)
 		expectedStatus int
 	}{
 		"rejected by receiver": {
@@ -72,7 +76,7 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 		},
 		"fanout times out": {
 			timeout: time.Millisecond,
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{
 					CallableDomain: replaceCallable,
 				},
@@ -84,17 +88,17 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 		},
 		"zero subs succeed": {
-			subs:           []duckv1alpha1.ChannelSubscriberSpec{},
+			subs:           []eventingv1alpha1.ChannelSubscriberSpec{},
 			expectedStatus: http.StatusAccepted,
 		},
 		"empty sub succeeds": {
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{},
 			},
 			expectedStatus: http.StatusAccepted,
 		},
 		"sinkable fails": {
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{
 					SinkableDomain: replaceSinkable,
 				},
@@ -105,9 +109,67 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 		},
 		"callable fails": {
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
+				{
+					CallableDomain: replaceCallable,
+				},
+			},
+			callable: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"callable fails, dead-letter sink succeeds": {
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
+				{
+					CallableDomain:       replaceCallable,
+					DeadLetterSinkDomain: replaceDeadLetterSink,
+				},
+			},
+			callable: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			deadLetterSink: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusAccepted)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"callable fails, dead-letter sink fails": {
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
+				{
+					CallableDomain:       replaceCallable,
+					DeadLetterSinkDomain: replaceDeadLetterSink,
+				},
+			},
+			callable: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			deadLetterSink: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"callable fails once, retry succeeds": {
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{
 					CallableDomain: replaceCallable,
+					Delivery: &eventingv1alpha1.DeliverySpec{
+						Retry:        retry(1),
+						BackoffDelay: &metav1.Duration{Duration: time.Millisecond},
+					},
+				},
+			},
+			callable:       (&failOnce{}).handler,
+			expectedStatus: http.StatusAccepted,
+		},
+		"callable fails, retries exhausted": {
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
+				{
+					CallableDomain: replaceCallable,
+					Delivery: &eventingv1alpha1.DeliverySpec{
+						Retry:        retry(2),
+						BackoffDelay: &metav1.Duration{Duration: time.Millisecond},
+					},
 				},
 			},
 			callable: func(writer http.ResponseWriter, _ *http.Request) {
@@ -116,7 +178,7 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 		},
 		"callable succeeds, sinkable fails": {
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{
 					CallableDomain: replaceCallable,
 					SinkableDomain: replaceSinkable,
@@ -129,7 +191,7 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 		},
 		"one sub succeeds": {
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{
 					CallableDomain: replaceCallable,
 					SinkableDomain: replaceSinkable,
@@ -142,7 +204,7 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			expectedStatus: http.StatusAccepted,
 		},
 		"one sub succeeds, one sub fails": {
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{
 					CallableDomain: replaceCallable,
 					SinkableDomain: replaceSinkable,
@@ -157,7 +219,7 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 		},
 		"all subs succeed": {
-			subs: []duckv1alpha1.ChannelSubscriberSpec{
+			subs: []eventingv1alpha1.ChannelSubscriberSpec{
 				{
 					CallableDomain: replaceCallable,
 					SinkableDomain: replaceSinkable,
@@ -191,9 +253,13 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 				handler: tc.sinkable,
 			})
 			defer sinkableServer.Close()
+			deadLetterSinkServer := httptest.NewServer(&fakeHandler{
+				handler: tc.deadLetterSink,
+			})
+			defer deadLetterSinkServer.Close()
 
 			// Rewrite the subs to use the servers we just started.
-			subs := make([]duckv1alpha1.ChannelSubscriberSpec, 0)
+			subs := make([]eventingv1alpha1.ChannelSubscriberSpec, 0)
 			for _, sub := range tc.subs {
 				if sub.CallableDomain == replaceCallable {
 					sub.CallableDomain = callableServer.URL[7:] // strip the leading 'http://'
@@ -201,6 +267,9 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 				if sub.SinkableDomain == replaceSinkable {
 					sub.SinkableDomain = sinkableServer.URL[7:] // strip the leading 'http://'
 				}
+				if sub.DeadLetterSinkDomain == replaceDeadLetterSink {
+					sub.DeadLetterSinkDomain = deadLetterSinkServer.URL[7:] // strip the leading 'http://'
+				}
 				subs = append(subs, sub)
 			}
 
@@ -224,6 +293,53 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestFanoutHandler_RetriesExceedTimeout(t *testing.T) {
+	var attempts, deadLettered atomic.Int32
+	callableServer := httptest.NewServer(&fakeHandler{
+		handler: func(writer http.ResponseWriter, _ *http.Request) {
+			attempts.Inc()
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		},
+	})
+	defer callableServer.Close()
+	deadLetterSinkServer := httptest.NewServer(&fakeHandler{
+		handler: func(writer http.ResponseWriter, _ *http.Request) {
+			deadLettered.Inc()
+			writer.WriteHeader(http.StatusAccepted)
+		},
+	})
+	defer deadLetterSinkServer.Close()
+
+	// The retries alone take 10+20+40+80+160ms, well past the handler's timeout.
+	h := NewHandler(zap.NewNop(), Config{Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{{
+		CallableDomain:       callableServer.URL[7:], // strip the leading 'http://'
+		DeadLetterSinkDomain: deadLetterSinkServer.URL[7:],
+		Delivery: &eventingv1alpha1.DeliverySpec{
+			Retry:        retry(5),
+			BackoffDelay: &metav1.Duration{Duration: 10 * time.Millisecond},
+		},
+	}}})
+	h.timeout = 50 * time.Millisecond
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, cloudEventReq)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected status code. Expected %v, Actual %v", http.StatusInternalServerError, w.Code)
+	}
+
+	// Once the fanout gave up, the delivery must stop retrying rather than dead-letter the message
+	// the sender may re-send.
+	time.Sleep(h.timeout)
+	stopped := attempts.Load()
+	time.Sleep(300 * time.Millisecond)
+	if got := attempts.Load(); got != stopped {
+		t.Errorf("Expected retries to stop with the fanout, %d more attempts were made", got-stopped)
+	}
+	if got := deadLettered.Load(); got != 0 {
+		t.Errorf("Expected no message to be dead-lettered after the fanout timed out, got %d", got)
+	}
+}
+
 type fakeHandler struct {
 	handler func(http.ResponseWriter, *http.Request)
 }
@@ -245,6 +361,22 @@ func (s *succeedOnce) handler(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
+type failOnce struct {
+	called atomic.Bool
+}
+
+func (f *failOnce) handler(w http.ResponseWriter, _ *http.Request) {
+	if f.called.CAS(false, true) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func retry(r int32) *int32 {
+	return &r
+}
+
 func body(body string) io.ReadCloser {
 	return ioutil.NopCloser(strings.NewReader(body))
 }
@@ -19,8 +19,8 @@ package multichannelfanout
 import (
 	"fmt"
 	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"go.uber.org/zap"
 	"net/http"
 	"net/http/httptest"
@@ -108,7 +108,7 @@ func TestCopyWithNewConfig(t *testing.T) {
 				Namespace: "default",
 				Name:      "c1",
 				FanoutConfig: fanout.Config{
-					Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+					Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							CallableDomain: "callabledomain",
 						},
@@ -123,7 +123,7 @@ func TestCopyWithNewConfig(t *testing.T) {
 				Namespace: "default",
 				Name:      "somethingdifferent",
 				FanoutConfig: fanout.Config{
-					Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+					Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							SinkableDomain: "sinkabledomain",
 						},
@@ -161,7 +161,7 @@ func TestConfigDiff(t *testing.T) {
 				Namespace: "default",
 				Name:      "c1",
 				FanoutConfig: fanout.Config{
-					Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+					Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 						{
 							CallableDomain: "callabledomain",
 						},
@@ -191,7 +191,7 @@ func TestConfigDiff(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: "different",
 								},
@@ -238,7 +238,7 @@ func TestServeHTTP(t *testing.T) {
 						Namespace: "default",
 						Name:      "first-channel",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: replaceDomain,
 								},
@@ -258,7 +258,7 @@ func TestServeHTTP(t *testing.T) {
 						Namespace: "default",
 						Name:      "first-channel",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "first-to-domain",
 								},
@@ -269,7 +269,7 @@ func TestServeHTTP(t *testing.T) {
 						Namespace: "default",
 						Name:      "second-channel",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: replaceDomain,
 								},
@@ -18,8 +18,8 @@ package multichannelfanout
 
 import (
 	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"go.uber.org/zap"
 	"strings"
 	"testing"
@@ -84,7 +84,7 @@ func TestConfigMapData(t *testing.T) {
 						Namespace: "default",
 						Name:      "c1",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: "event-changer.default.svc.cluster.local",
 									SinkableDomain: "message-dumper-bar.default.svc.cluster.local",
@@ -102,7 +102,7 @@ func TestConfigMapData(t *testing.T) {
 						Namespace: "default",
 						Name:      "c2",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "message-dumper-foo.default.svc.cluster.local",
 								},
@@ -113,7 +113,7 @@ func TestConfigMapData(t *testing.T) {
 						Namespace: "other",
 						Name:      "c3",
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									SinkableDomain: "message-dumper-foo.default.svc.cluster.local",
 								},
@@ -18,9 +18,9 @@ package swappable
 
 import (
 	"fmt"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"go.uber.org/zap"
 	"net/http"
 	"net/http/httptest"
@@ -46,7 +46,7 @@ func TestHandler(t *testing.T) {
 							Namespace: namespace,
 							Name:      name,
 							FanoutConfig: fanout.Config{
-								Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+								Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 									{
 										CallableDomain: replaceDomain,
 									},
@@ -61,7 +61,7 @@ func TestHandler(t *testing.T) {
 							Namespace: namespace,
 							Name:      name,
 							FanoutConfig: fanout.Config{
-								Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+								Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 									{
 										SinkableDomain: replaceDomain,
 									},
@@ -98,7 +98,7 @@ func TestHandler_InvalidConfigChange(t *testing.T) {
 						Namespace: namespace,
 						Name:      name,
 						FanoutConfig: fanout.Config{
-							Subscriptions: []duckv1alpha1.ChannelSubscriberSpec{
+							Subscriptions: []eventingv1alpha1.ChannelSubscriberSpec{
 								{
 									CallableDomain: replaceDomain,
 								},
@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// CheckReservedMetadata is a Check denying updates of Channels that change their reserved labels or
+// annotations, unless they were made by one of eventingv1alpha1.ReservedMetadataWriters.
+func CheckReservedMetadata(request *admissionv1beta1.AdmissionRequest) error {
+	if request.Operation != admissionv1beta1.Update || !isChannel(request) {
+		return nil
+	}
+	original, current := decodeChannel(request.OldObject), decodeChannel(request.Object)
+	if original == nil || current == nil {
+		return nil
+	}
+	if fe := current.CheckReservedMetadata(original, request.UserInfo.Username); fe != nil {
+		return fe
+	}
+	return nil
+}
+
+// CheckChannelCreate is a Check denying the creation of Channels that fail
+// eventingv1alpha1.ValidateChannel, once defaulted as the admission controller defaults them.
+func CheckChannelCreate(request *admissionv1beta1.AdmissionRequest) error {
+	if request.Operation != admissionv1beta1.Create || !isChannel(request) {
+		return nil
+	}
+	c := decodeChannel(request.Object)
+	if c == nil {
+		return nil
+	}
+	c.SetDefaults()
+	if fe := eventingv1alpha1.ValidateChannel(c); fe != nil {
+		return fe
+	}
+	return nil
+}
+
+func isChannel(request *admissionv1beta1.AdmissionRequest) bool {
+	gvk := eventingv1alpha1.SchemeGroupVersion.WithKind("Channel")
+	return request.Kind.Group == gvk.Group && request.Kind.Version == gvk.Version && request.Kind.Kind == gvk.Kind
+}
+
+// decodeChannel returns the Channel in raw, or nil if it can't be decoded, leaving the admission
+// controller to reject it.
+func decodeChannel(raw runtime.RawExtension) *eventingv1alpha1.Channel {
+	c := &eventingv1alpha1.Channel{}
+	if err := json.Unmarshal(raw.Raw, c); err != nil {
+		return nil
+	}
+	return c
+}
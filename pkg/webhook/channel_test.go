@@ -0,0 +1,196 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+func TestHandler_ChannelChecks(t *testing.T) {
+	defer func(required []string) { eventingv1alpha1.RequiredLabels = required }(eventingv1alpha1.RequiredLabels)
+	eventingv1alpha1.RequiredLabels = []string{"internal.eventing.knative.dev/topic"}
+	channelKind := metav1.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1alpha1", Kind: "Channel"}
+	channel := func(topic string) runtime.RawExtension {
+		c := &eventingv1alpha1.Channel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "c"},
+			Spec: eventingv1alpha1.ChannelSpec{
+				Provisioner: &eventingv1alpha1.ProvisionerReference{
+					Ref: &corev1.ObjectReference{Name: "in-memory-channel"},
+				},
+			},
+		}
+		if topic != "" {
+			c.Labels = map[string]string{"internal.eventing.knative.dev/topic": topic}
+		}
+		raw, err := json.Marshal(c)
+		if err != nil {
+			t.Fatalf("Unable to marshal the Channel: %v", err)
+		}
+		return runtime.RawExtension{Raw: raw}
+	}
+	testCases := map[string]struct {
+		request     admissionv1beta1.AdmissionRequest
+		wantDenied  bool
+		wantForward bool
+	}{
+		"reserved label changed by a user": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      channelKind,
+				Operation: admissionv1beta1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+				OldObject: channel("t"),
+				Object:    channel("t2"),
+			},
+			wantDenied: true,
+		},
+		"reserved label changed by the controller": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      channelKind,
+				Operation: admissionv1beta1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:knative-eventing:eventing-controller"},
+				OldObject: channel("t"),
+				Object:    channel("t2"),
+			},
+			wantForward: true,
+		},
+		"reserved label unchanged": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      channelKind,
+				Operation: admissionv1beta1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+				OldObject: channel("t"),
+				Object:    channel("t"),
+			},
+			wantForward: true,
+		},
+		"created with a reserved label": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      channelKind,
+				Operation: admissionv1beta1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+				Object:    channel("t"),
+			},
+			wantForward: true,
+		},
+		"created without a required label": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      channelKind,
+				Operation: admissionv1beta1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+				Object:    channel(""),
+			},
+			wantDenied: true,
+		},
+		"created with invalid arguments": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      channelKind,
+				Operation: admissionv1beta1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+				Object: func() runtime.RawExtension {
+					c := &eventingv1alpha1.Channel{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "c",
+							Labels:    map[string]string{"internal.eventing.knative.dev/topic": "t"},
+						},
+						Spec: eventingv1alpha1.ChannelSpec{
+							Provisioner: &eventingv1alpha1.ProvisionerReference{
+								Ref: &corev1.ObjectReference{Name: "in-memory-channel"},
+							},
+							Arguments: &runtime.RawExtension{Raw: []byte(`{"defaultConcurrency":0}`)},
+						},
+					}
+					raw, err := json.Marshal(c)
+					if err != nil {
+						t.Fatalf("Unable to marshal the Channel: %v", err)
+					}
+					return runtime.RawExtension{Raw: raw}
+				}(),
+			},
+			wantDenied: true,
+		},
+		"updated without a required label": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      channelKind,
+				Operation: admissionv1beta1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+				OldObject: channel(""),
+				Object:    channel(""),
+			},
+			wantForward: true,
+		},
+		"other kind": {
+			request: admissionv1beta1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1alpha1", Kind: "Subscription"},
+				Operation: admissionv1beta1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane"},
+				OldObject: channel("t"),
+				Object:    channel("t2"),
+			},
+			wantForward: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			body, err := json.Marshal(admissionv1beta1.AdmissionReview{Request: &tc.request})
+			if err != nil {
+				t.Fatalf("Unable to marshal the review: %v", err)
+			}
+			var forwarded []byte
+			h := &Handler{
+				Next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					forwarded, _ = ioutil.ReadAll(r.Body)
+				}),
+				Logger: zap.NewNop().Sugar(),
+				Checks: []Check{CheckReservedMetadata, CheckChannelCreate},
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+			if tc.wantForward != (forwarded != nil) {
+				t.Errorf("Unexpected forwarding. Expected %v, actually %v", tc.wantForward, forwarded != nil)
+			}
+			if forwarded != nil && !bytes.Equal(forwarded, body) {
+				t.Errorf("Unexpected forwarded body. Expected %s, actually %s", body, forwarded)
+			}
+			if !tc.wantDenied {
+				return
+			}
+			var review admissionv1beta1.AdmissionReview
+			if err := json.NewDecoder(w.Body).Decode(&review); err != nil {
+				t.Fatalf("Unable to decode the response: %v", err)
+			}
+			if review.Response == nil || review.Response.Allowed {
+				t.Errorf("Expected the request to be denied, actually %v", review.Response)
+			}
+		})
+	}
+}
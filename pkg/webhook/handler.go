@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook holds the parts of the eventing webhook that need more of the admission request
+// than the resource being admitted.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Check returns an error to deny an admission request with, or nil to let the admission controller
+// decide.
+type Check func(request *admissionv1beta1.AdmissionRequest) error
+
+// Handler denies the admission requests any of Checks returns an error for, and passes every other
+// request on to Next, the admission controller. The admission controller only passes the resources
+// being admitted on to their validation, so checks that need more of the request, such as its
+// operation or user, are made here instead.
+type Handler struct {
+	Next   http.Handler
+	Logger *zap.SugaredLogger
+	Checks []Check
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read body: %v", err), http.StatusBadRequest)
+		return
+	}
+	var review admissionv1beta1.AdmissionReview
+	// Requests that can't be decoded here are left for Next to reject.
+	if err := json.Unmarshal(body, &review); err == nil && review.Request != nil {
+		for _, check := range h.Checks {
+			if err := check(review.Request); err != nil {
+				h.deny(w, review.Request, err)
+				return
+			}
+		}
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	h.Next.ServeHTTP(w, r)
+}
+
+func (h *Handler) deny(w http.ResponseWriter, request *admissionv1beta1.AdmissionRequest, err error) {
+	h.Logger.Infof("Denied the %v of %v %s/%s by %q: %v", request.Operation, request.Kind, request.Namespace, request.Name, request.UserInfo.Username, err)
+	result := apierrors.NewBadRequest(fmt.Sprintf("validation failed: %v", err)).Status()
+	response := admissionv1beta1.AdmissionReview{
+		Response: &admissionv1beta1.AdmissionResponse{
+			UID:     request.UID,
+			Allowed: false,
+			Result:  &result,
+		},
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, fmt.Sprintf("could not encode response: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/knative/pkg/webhook"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// The keys of the certificates in the webhook's Secret, as the admission controller stores them.
+const (
+	secretServerKey  = "server-key.pem"
+	secretServerCert = "server-cert.pem"
+	secretCACert     = "ca-cert.pem"
+)
+
+// Run runs the admission controller ac behind handler, which is given ac to pass requests on to.
+// ac.Run only ever serves ac itself, so it is moved to internalPort, where it still registers the
+// webhook, and handler is served on ac's port with the same certificates instead. Run blocks until
+// stop is closed, and ac has unregistered the webhook, or until either server fails, in which case
+// the other one is stopped too and the error is returned.
+func Run(ac *webhook.AdmissionController, handler func(http.Handler) http.Handler, internalPort int, stop <-chan struct{}) error {
+	// Create the certificates before ac.Run does, so that both servers use them.
+	tlsConfig, err := serverTLSConfig(ac.Client, ac.Options)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:   handler(ac),
+		Addr:      fmt.Sprintf(":%v", ac.Options.Port),
+		TLSConfig: tlsConfig,
+	}
+	ac.Options.Port = internalPort
+
+	g, ctx := errgroup.WithContext(context.Background())
+	// done is closed once stop is, or once either server failed.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-stop:
+		case <-ctx.Done():
+		}
+		close(done)
+	}()
+	g.Go(func() error {
+		if err := ac.Run(done); err != nil {
+			return fmt.Errorf("the admission controller failed: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("ListenAndServeTLS for admission webhook failed: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		<-done
+		return server.Close()
+	})
+	return g.Wait()
+}
+
+// serverTLSConfig returns the TLS configuration of the webhook's server, from the certificates in
+// the webhook's Secret, which is created first if it doesn't exist.
+func serverTLSConfig(client kubernetes.Interface, options webhook.ControllerOptions) (*tls.Config, error) {
+	secret, err := client.CoreV1().Secrets(options.Namespace).Get(options.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		serverKey, serverCert, caCert, err := webhook.CreateCerts(context.TODO(), options.ServiceName, options.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		secret, err = client.CoreV1().Secrets(options.Namespace).Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      options.SecretName,
+				Namespace: options.Namespace,
+			},
+			Data: map[string][]byte{
+				secretServerKey:  serverKey,
+				secretServerCert: serverCert,
+				secretCACert:     caCert,
+			},
+		})
+		if apierrors.IsAlreadyExists(err) {
+			// Something else created it in the meantime.
+			secret, err = client.CoreV1().Secrets(options.Namespace).Get(options.SecretName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(secret.Data[secretServerCert], secret.Data[secretServerKey])
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+}